@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/example/wphunter/internal/detector"
+	"github.com/example/wphunter/internal/wpprobe"
+)
+
+// scanAggregator collects artifacts and detections as batches and detectors
+// report them. All methods are safe for concurrent use.
+type scanAggregator struct {
+	mu         sync.Mutex
+	artifacts  []string
+	detections []detector.Result
+	severities map[string]int
+	perTarget  map[string]int
+	wpprobe    *wpprobe.Digest
+	// targetArtifacts maps targets to their --per-target-output file.
+	targetArtifacts map[string]string
+}
+
+// scanTotals is a point-in-time copy of the aggregator state.
+type scanTotals struct {
+	Artifacts  []string
+	Detections []detector.Result
+	// Severities counts detections per severity.
+	Severities map[string]int
+	// PerTarget counts detections per target.
+	PerTarget map[string]int
+	// WPProbe condenses wpprobe output; nil unless a digest was added.
+	WPProbe *wpprobe.Digest
+	// TargetArtifacts maps targets to their per-target artifact; nil when none were written.
+	TargetArtifacts map[string]string
+}
+
+func newScanAggregator() *scanAggregator {
+	return &scanAggregator{severities: map[string]int{}, perTarget: map[string]int{}}
+}
+
+// AddArtifact records a written artifact path.
+func (a *scanAggregator) AddArtifact(path string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.artifacts = append(a.artifacts, path)
+}
+
+// AddDetections records detector results and updates the per-severity and per-target counts.
+func (a *scanAggregator) AddDetections(results ...detector.Result) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, res := range results {
+		a.detections = append(a.detections, res)
+		a.severities[res.Severity]++
+		a.perTarget[res.Target]++
+	}
+}
+
+// AddTargetArtifact records the per-target artifact written for target.
+func (a *scanAggregator) AddTargetArtifact(target, path string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.targetArtifacts == nil {
+		a.targetArtifacts = map[string]string{}
+	}
+	a.targetArtifacts[target] = path
+}
+
+// AddWPProbeDigest merges a digest of one wpprobe output file into the totals.
+func (a *scanAggregator) AddWPProbeDigest(d wpprobe.Digest) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.wpprobe == nil {
+		a.wpprobe = &wpprobe.Digest{}
+	}
+	a.wpprobe.Merge(d)
+}
+
+// Snapshot returns copies so callers can read totals while other goroutines keep adding.
+func (a *scanAggregator) Snapshot() scanTotals {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	totals := scanTotals{
+		Artifacts:  append([]string(nil), a.artifacts...),
+		Detections: append([]detector.Result(nil), a.detections...),
+		Severities: make(map[string]int, len(a.severities)),
+		PerTarget:  make(map[string]int, len(a.perTarget)),
+	}
+	for key, count := range a.severities {
+		totals.Severities[key] = count
+	}
+	for key, count := range a.perTarget {
+		totals.PerTarget[key] = count
+	}
+	if a.targetArtifacts != nil {
+		totals.TargetArtifacts = make(map[string]string, len(a.targetArtifacts))
+		for target, path := range a.targetArtifacts {
+			totals.TargetArtifacts[target] = path
+		}
+	}
+	if a.wpprobe != nil {
+		digest := wpprobe.Digest{}
+		digest.Merge(*a.wpprobe)
+		totals.WPProbe = &digest
+	}
+	return totals
+}
+
+// finishedFields summarizes totals for the scan-finished event so automation that
+// only watches the final event gets a verdict: artifact and finding counts, findings
+// per severity, the highest severity seen, and the best confidence reported at it.
+func finishedFields(totals scanTotals) map[string]interface{} {
+	fields := map[string]interface{}{
+		"artifacts":  len(totals.Artifacts),
+		"findings":   len(totals.Detections),
+		"severities": totals.Severities,
+	}
+
+	highest, confidence := "", 0.0
+	for _, res := range totals.Detections {
+		switch {
+		case highest == "" || detector.SeverityRank(res.Severity) > detector.SeverityRank(highest):
+			highest, confidence = res.Severity, res.Confidence
+		case detector.SeverityRank(res.Severity) == detector.SeverityRank(highest) && res.Confidence > confidence:
+			confidence = res.Confidence
+		}
+	}
+	if highest != "" {
+		fields["highestSeverity"] = highest
+		fields["confidence"] = confidence
+	}
+	return fields
+}
+
+// detectorLatency summarizes how long one detector took per target.
+type detectorLatency struct {
+	P50Ms int64 `json:"p50Ms" yaml:"p50Ms"`
+	P95Ms int64 `json:"p95Ms" yaml:"p95Ms"`
+	MaxMs int64 `json:"maxMs" yaml:"maxMs"`
+}
+
+// detectorLatencies computes per-detector latency percentiles from the DurationMs of
+// each result, using the nearest-rank method.
+func detectorLatencies(results []detector.Result) map[string]detectorLatency {
+	durations := map[string][]int64{}
+	for _, res := range results {
+		durations[res.Detector] = append(durations[res.Detector], res.DurationMs)
+	}
+
+	latencies := make(map[string]detectorLatency, len(durations))
+	for name, values := range durations {
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		latencies[name] = detectorLatency{
+			P50Ms: percentile(values, 50),
+			P95Ms: percentile(values, 95),
+			MaxMs: values[len(values)-1],
+		}
+	}
+	return latencies
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted, which must not be empty.
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/example/wphunter/internal/detector"
+	"github.com/example/wphunter/internal/events"
+)
+
+func TestScanAggregatorConcurrentUpdates(t *testing.T) {
+	const workers = 32
+	const perWorker = 100
+
+	agg := newScanAggregator()
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			target := fmt.Sprintf("https://site%d.test", w%4)
+			for i := 0; i < perWorker; i++ {
+				severity := "info"
+				if i%2 == 0 {
+					severity = "critical"
+				}
+				agg.AddDetections(detector.Result{Target: target, Detector: "fake", Severity: severity})
+				agg.AddArtifact(fmt.Sprintf("artifact_%d_%d.json", w, i))
+				_ = agg.Snapshot()
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	totals := agg.Snapshot()
+	total := workers * perWorker
+
+	if len(totals.Detections) != total {
+		t.Fatalf("expected %d detections, got %d", total, len(totals.Detections))
+	}
+
+	if len(totals.Artifacts) != total {
+		t.Fatalf("expected %d artifacts, got %d", total, len(totals.Artifacts))
+	}
+
+	if totals.Severities["critical"] != total/2 || totals.Severities["info"] != total/2 {
+		t.Fatalf("unexpected severity counts: %v", totals.Severities)
+	}
+
+	for target, count := range totals.PerTarget {
+		if count != total/4 {
+			t.Fatalf("expected %d detections for %s, got %d", total/4, target, count)
+		}
+	}
+}
+
+func TestFinishedFieldsSummarizeSeverities(t *testing.T) {
+	agg := newScanAggregator()
+	agg.AddArtifact("detections.json")
+	agg.AddDetections(
+		detector.Result{Target: "https://one.test", Detector: "version", Severity: "info", Confidence: 0.85},
+		detector.Result{Target: "https://one.test", Detector: "php", Severity: "warning", Confidence: 0.7},
+		detector.Result{Target: "https://two.test", Detector: "vcs", Severity: "critical", Confidence: 0.8},
+		detector.Result{Target: "https://two.test", Detector: "admintools", Severity: "critical", Confidence: 0.9},
+	)
+
+	buf := &bytes.Buffer{}
+	if err := events.NewEmitter(buf).Emit(events.Event{Type: "scan-finished", Fields: finishedFields(agg.Snapshot())}); err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+
+	var evt struct {
+		Fields struct {
+			Artifacts       int            `json:"artifacts"`
+			Findings        int            `json:"findings"`
+			Severities      map[string]int `json:"severities"`
+			HighestSeverity string         `json:"highestSeverity"`
+			Confidence      float64        `json:"confidence"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &evt); err != nil {
+		t.Fatalf("parse event: %v", err)
+	}
+
+	if evt.Fields.Artifacts != 1 || evt.Fields.Findings != 4 {
+		t.Fatalf("unexpected counts: %+v", evt.Fields)
+	}
+	want := map[string]int{"info": 1, "warning": 1, "critical": 2}
+	if !reflect.DeepEqual(evt.Fields.Severities, want) {
+		t.Fatalf("severities = %v, want %v", evt.Fields.Severities, want)
+	}
+	if evt.Fields.HighestSeverity != "critical" || evt.Fields.Confidence != 0.9 {
+		t.Fatalf("expected critical verdict at 0.9 confidence, got %+v", evt.Fields)
+	}
+}
+
+func TestFinishedFieldsWithoutFindings(t *testing.T) {
+	fields := finishedFields(newScanAggregator().Snapshot())
+	if fields["findings"] != 0 {
+		t.Fatalf("expected zero findings, got %v", fields["findings"])
+	}
+	if _, ok := fields["highestSeverity"]; ok {
+		t.Fatalf("expected no highestSeverity without findings, got %v", fields)
+	}
+}
+
+func TestDetectorLatencies(t *testing.T) {
+	var results []detector.Result
+	// 20 durations of 10ms..200ms in shuffled order; the nearest-rank p95 is the 19th.
+	for _, ms := range []int64{200, 10, 190, 20, 180, 30, 170, 40, 160, 50, 150, 60, 140, 70, 130, 80, 120, 90, 110, 100} {
+		results = append(results, detector.Result{Target: "https://example.com", Detector: "version", DurationMs: ms})
+	}
+	results = append(results, detector.Result{Target: "https://example.com", Detector: "hosting", DurationMs: 7})
+
+	latencies := detectorLatencies(results)
+	if got, want := latencies["version"], (detectorLatency{P50Ms: 100, P95Ms: 190, MaxMs: 200}); got != want {
+		t.Fatalf("expected version latency %+v, got %+v", want, got)
+	}
+	if got, want := latencies["hosting"], (detectorLatency{P50Ms: 7, P95Ms: 7, MaxMs: 7}); got != want {
+		t.Fatalf("expected hosting latency %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// consoleSeverityOrder is the order severities appear in the end-of-scan summary.
+var consoleSeverityOrder = []string{"critical", "high", "medium", "warning", "low", "info"}
+
+// severityColors are ANSI SGR codes for each severity.
+var severityColors = map[string]string{
+	"critical": "1;31",
+	"high":     "31",
+	"medium":   "33",
+	"warning":  "33",
+	"low":      "36",
+	"info":     "2",
+}
+
+// useConsoleColor reports whether console output may carry ANSI colors. A non-empty
+// NO_COLOR (https://no-color.org) and --ascii both disable them.
+func useConsoleColor(ascii bool) bool {
+	if ascii {
+		return false
+	}
+	return os.Getenv("NO_COLOR") == ""
+}
+
+// formatSeveritySummary renders a one-line verdict such as
+// "Scan summary: 2 critical, 1 warning, 3 info". Critical, warning, and info are
+// always listed; other severities only when present.
+func formatSeveritySummary(severities map[string]int, color bool) string {
+	var parts []string
+	listed := map[string]struct{}{}
+	for _, severity := range consoleSeverityOrder {
+		listed[severity] = struct{}{}
+		count := severities[severity]
+		if count == 0 && severity != "critical" && severity != "warning" && severity != "info" {
+			continue
+		}
+		part := fmt.Sprintf("%d %s", count, severity)
+		if color && count > 0 {
+			part = fmt.Sprintf("\x1b[%sm%s\x1b[0m", severityColors[severity], part)
+		}
+		parts = append(parts, part)
+	}
+	for severity, count := range severities {
+		if _, ok := listed[severity]; !ok && count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, severity))
+		}
+	}
+	return "Scan summary: " + strings.Join(parts, ", ")
+}
+
+func printSeveritySummary(w io.Writer, severities map[string]int, ascii bool) {
+	fmt.Fprintln(w, formatSeveritySummary(severities, useConsoleColor(ascii)))
+}
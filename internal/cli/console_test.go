@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/example/wphunter/internal/detector"
+)
+
+func TestFormatSeveritySummary(t *testing.T) {
+	totals := newScanAggregator()
+	totals.AddDetections(
+		detectionWithSeverity("critical"),
+		detectionWithSeverity("critical"),
+		detectionWithSeverity("warning"),
+		detectionWithSeverity("info"),
+		detectionWithSeverity("info"),
+		detectionWithSeverity("info"),
+	)
+	severities := totals.Snapshot().Severities
+
+	if got := formatSeveritySummary(severities, false); got != "Scan summary: 2 critical, 1 warning, 3 info" {
+		t.Fatalf("unexpected plain summary %q", got)
+	}
+
+	colored := formatSeveritySummary(severities, true)
+	if !strings.Contains(colored, "\x1b[1;31m2 critical\x1b[0m") {
+		t.Fatalf("expected colored critical count, got %q", colored)
+	}
+
+	// An empty NO_COLOR does not disable colors.
+	t.Setenv("NO_COLOR", "")
+	if !useConsoleColor(false) {
+		t.Fatal("expected an empty NO_COLOR to keep colors")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	buf := &bytes.Buffer{}
+	printSeveritySummary(buf, map[string]int{"high": 1}, false)
+	if buf.String() != "Scan summary: 0 critical, 1 high, 0 warning, 0 info\n" {
+		t.Fatalf("expected NO_COLOR summary without escapes, got %q", buf.String())
+	}
+}
+
+func detectionWithSeverity(severity string) detector.Result {
+	return detector.Result{Target: "https://one.test", Detector: "test", Severity: severity, Summary: severity + " finding"}
+}
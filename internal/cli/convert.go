@@ -0,0 +1,377 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/example/wphunter/internal/detector"
+	"gopkg.in/yaml.v3"
+)
+
+// wpprobeFormats are the scan artifact formats wpprobe produces natively. Every other
+// requested format is rendered only from the canonical detector results.
+var wpprobeFormats = map[string]struct{}{
+	"json": {},
+	"csv":  {},
+}
+
+// detectionWriters render detector results in each requested format. The JSON
+// detections artifact is always written separately and is not listed here.
+var detectionWriters = map[string]func(path string, results []detector.Result) error{
+	"yaml":      writeDetectionsYAML,
+	"csv":       writeDetectionsCSV,
+	"html":      writeDetectionsHTML,
+	"sarif":     writeDetectionsSARIF,
+	"cyclonedx": writeDetectionsCycloneDX,
+}
+
+// outputOptions adjust how artifacts are rendered.
+type outputOptions struct {
+	// CSVCRLF ends CSV records with \r\n instead of \n.
+	CSVCRLF bool
+	// CSVLayout selects csvLayoutLong (one row per result, the default) or
+	// csvLayoutWide (one row per target, pivoted by detector).
+	CSVLayout string
+}
+
+// CSV detection layouts accepted by --csv-layout.
+const (
+	csvLayoutLong = "long"
+	csvLayoutWide = "wide"
+)
+
+// detectionWriter returns the writer for format with opts applied.
+func detectionWriter(format string, opts outputOptions) (func(path string, results []detector.Result) error, bool) {
+	if format == "csv" && (opts.CSVCRLF || opts.CSVLayout == csvLayoutWide) {
+		return func(path string, results []detector.Result) error {
+			return writeDetectionsCSVWithOptions(path, results, opts)
+		}, true
+	}
+	write, ok := detectionWriters[format]
+	return write, ok
+}
+
+// convertedArtifact is one detections rendering produced by convertDetections.
+type convertedArtifact struct {
+	Format string
+	Path   string
+}
+
+// convertDetections renders results in every requested format that has a detection
+// writer, running up to workers writers at once (one per format when workers < 1).
+// Writers only read results, so the slice is shared rather than copied. Artifacts
+// are returned in the order of formats; the first error wins.
+func convertDetections(dir, name string, formats []string, results []detector.Result, workers int, opts outputOptions) ([]convertedArtifact, error) {
+	var jobs []convertedArtifact
+	seen := map[string]struct{}{}
+	for _, format := range formats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if _, ok := detectionWriters[format]; !ok {
+			continue
+		}
+		if _, dup := seen[format]; dup {
+			continue
+		}
+		seen[format] = struct{}{}
+		jobs = append(jobs, convertedArtifact{Format: format, Path: filepath.Join(dir, fmt.Sprintf("%s.%s", name, format))})
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	if workers < 1 || workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	errs := make([]error, len(jobs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				write, _ := detectionWriter(jobs[i].Format, opts)
+				errs[i] = write(jobs[i].Path, results)
+			}
+		}()
+	}
+	for i := range jobs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("write %s detections: %w", jobs[i].Format, err)
+		}
+	}
+	return jobs, nil
+}
+
+func writeDetectionsYAML(path string, results []detector.Result) error {
+	if err := ensureOutputDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(results)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// detectionsCSVHeader lists the columns of the CSV detections artifact.
+var detectionsCSVHeader = []string{"target", "detector", "severity", "confidence", "summary", "tags", "detectedAt"}
+
+func writeDetectionsCSV(path string, results []detector.Result) error {
+	return writeDetectionsCSVWithOptions(path, results, outputOptions{})
+}
+
+func writeDetectionsCSVWithOptions(path string, results []detector.Result, opts outputOptions) error {
+	if err := ensureOutputDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.UseCRLF = opts.CSVCRLF
+	records := detectionsCSVRecords(results)
+	if opts.CSVLayout == csvLayoutWide {
+		records = detectionsWideCSVRecords(results)
+	}
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// detectionsCSVRecords renders the long layout: a header and one row per result.
+func detectionsCSVRecords(results []detector.Result) [][]string {
+	records := [][]string{detectionsCSVHeader}
+	for _, res := range results {
+		detectedAt := ""
+		if !res.DetectedAt.IsZero() {
+			detectedAt = res.DetectedAt.UTC().Format(time.RFC3339)
+		}
+		record := []string{
+			res.Target,
+			res.Detector,
+			res.Severity,
+			strconv.FormatFloat(res.Confidence, 'f', -1, 64),
+			res.Summary,
+			strings.Join(res.Tags, ";"),
+			detectedAt,
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// detectionsWideCSVRecords renders the wide layout: one row per target, in first-seen
+// order, with a <detector>_severity and <detector>_summary column pair per detector
+// sorted by name. Detectors that produced no result for a target leave empty cells;
+// when a detector reported several results for a target, the last one wins.
+func detectionsWideCSVRecords(results []detector.Result) [][]string {
+	var targets, detectors []string
+	cells := map[string]map[string]detector.Result{}
+	seenDetectors := map[string]struct{}{}
+	for _, res := range results {
+		byDetector, ok := cells[res.Target]
+		if !ok {
+			byDetector = map[string]detector.Result{}
+			cells[res.Target] = byDetector
+			targets = append(targets, res.Target)
+		}
+		byDetector[res.Detector] = res
+		if _, ok := seenDetectors[res.Detector]; !ok {
+			seenDetectors[res.Detector] = struct{}{}
+			detectors = append(detectors, res.Detector)
+		}
+	}
+	sort.Strings(detectors)
+
+	header := []string{"target"}
+	for _, name := range detectors {
+		header = append(header, name+"_severity", name+"_summary")
+	}
+	records := [][]string{header}
+	for _, target := range targets {
+		record := []string{target}
+		for _, name := range detectors {
+			res := cells[target][name]
+			record = append(record, res.Severity, res.Summary)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+var detectionsHTMLTemplate = template.Must(template.New("detections").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>wphunter detections</title>
+<style>
+body { font-family: sans-serif; margin: 2rem; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: 0.4rem 0.6rem; text-align: left; }
+.sev-critical, .sev-high { color: #b00020; font-weight: bold; }
+.sev-warning, .sev-medium { color: #a15c00; }
+</style>
+</head>
+<body>
+<h1>wphunter detections</h1>
+<p>{{len .}} finding(s)</p>
+<table>
+<thead><tr><th>Target</th><th>Detector</th><th>Severity</th><th>Confidence</th><th>Summary</th></tr></thead>
+<tbody>
+{{- range .}}
+<tr class="finding"><td>{{.Target}}</td><td>{{.Detector}}</td><td class="sev-{{.Severity}}">{{.Severity}}</td><td>{{.Confidence}}</td><td>{{.Summary}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+func writeDetectionsHTML(path string, results []detector.Result) error {
+	if err := ensureOutputDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := detectionsHTMLTemplate.Execute(file, results); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// sarifLog is the subset of SARIF 2.1.0 needed to report detector findings.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifLevel maps detector severities onto SARIF result levels.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "warning", "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func writeDetectionsSARIF(path string, results []detector.Result) error {
+	if err := ensureOutputDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "wphunter", Version: version, Rules: []sarifRule{}}},
+		Results: make([]sarifResult, 0, len(results)),
+	}
+
+	seenRules := map[string]struct{}{}
+	for _, res := range results {
+		if _, ok := seenRules[res.Detector]; !ok {
+			seenRules[res.Detector] = struct{}{}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: res.Detector})
+		}
+
+		properties := map[string]interface{}{"severity": res.Severity}
+		if res.Confidence > 0 {
+			properties["confidence"] = res.Confidence
+		}
+		if len(res.Tags) > 0 {
+			properties["tags"] = res.Tags
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:     res.Detector,
+			Level:      sarifLevel(res.Severity),
+			Message:    sarifMessage{Text: res.Summary},
+			Locations:  []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: res.Target}}}},
+			Properties: properties,
+		})
+	}
+
+	data, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
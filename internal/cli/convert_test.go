@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/example/wphunter/internal/detector"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDetectionWritersProduceConsistentFormats(t *testing.T) {
+	results := []detector.Result{
+		{Target: "https://one.test", Detector: "version", Severity: "info", Summary: "WordPress version 6.5.1 detected", Confidence: 0.85, DetectedAt: time.Now()},
+		{Target: "https://two.test", Detector: "vcs", Severity: "critical", Summary: "Exposed sensitive files: /.git/config <b>", Tags: []string{detector.TagOWASPMisconfiguration}},
+	}
+
+	dir := t.TempDir()
+	paths := map[string]string{}
+	for _, format := range []string{"csv", "html", "sarif"} {
+		write, ok := detectionWriters[format]
+		if !ok {
+			t.Fatalf("no detection writer for %s", format)
+		}
+		paths[format] = filepath.Join(dir, "detections."+format)
+		if err := write(paths[format], results); err != nil {
+			t.Fatalf("write %s: %v", format, err)
+		}
+	}
+
+	csvFile, err := os.Open(paths["csv"])
+	if err != nil {
+		t.Fatalf("open csv: %v", err)
+	}
+	defer csvFile.Close()
+	records, err := csv.NewReader(csvFile).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != len(results)+1 || records[2][0] != "https://two.test" || records[2][5] != detector.TagOWASPMisconfiguration {
+		t.Fatalf("unexpected csv records: %v", records)
+	}
+
+	htmlData, err := os.ReadFile(paths["html"])
+	if err != nil {
+		t.Fatalf("read html: %v", err)
+	}
+	html := string(htmlData)
+	if strings.Count(html, `<tr class="finding">`) != len(results) || strings.Contains(html, "<b>") {
+		t.Fatalf("expected %d escaped rows in html report:\n%s", len(results), html)
+	}
+
+	sarifData, err := os.ReadFile(paths["sarif"])
+	if err != nil {
+		t.Fatalf("read sarif: %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(sarifData, &log); err != nil {
+		t.Fatalf("parse sarif: %v", err)
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != len(results) {
+		t.Fatalf("expected %d sarif results, got %+v", len(results), log.Runs)
+	}
+	if got := log.Runs[0].Results[1]; got.Level != "error" || got.Locations[0].PhysicalLocation.ArtifactLocation.URI != "https://two.test" {
+		t.Fatalf("unexpected sarif result: %+v", got)
+	}
+}
+
+func TestConvertDetectionsConcurrently(t *testing.T) {
+	var results []detector.Result
+	for i := 0; i < 200; i++ {
+		results = append(results, detector.Result{
+			Target:   fmt.Sprintf("https://site%d.test", i),
+			Detector: "version",
+			Severity: "info",
+			Summary:  "WordPress version 6.5.1 detected",
+			Metadata: map[string]interface{}{"version": "6.5.1"},
+		})
+	}
+
+	dir := t.TempDir()
+	formats := []string{"json", "yaml", "csv", "html", "sarif", "CSV"}
+	converted, err := convertDetections(dir, "detections_test", formats, results, 2, outputOptions{})
+	if err != nil {
+		t.Fatalf("convert detections: %v", err)
+	}
+
+	var got []string
+	for _, artifact := range converted {
+		got = append(got, artifact.Format)
+		if artifact.Path != filepath.Join(dir, "detections_test."+artifact.Format) {
+			t.Fatalf("unexpected path for %s: %s", artifact.Format, artifact.Path)
+		}
+	}
+	if strings.Join(got, ",") != "yaml,csv,html,sarif" {
+		t.Fatalf("expected yaml,csv,html,sarif in request order, got %v", got)
+	}
+
+	var fromYAML []detector.Result
+	yamlData, err := os.ReadFile(filepath.Join(dir, "detections_test.yaml"))
+	if err != nil {
+		t.Fatalf("read yaml: %v", err)
+	}
+	if err := yaml.Unmarshal(yamlData, &fromYAML); err != nil || len(fromYAML) != len(results) {
+		t.Fatalf("expected %d yaml results, got %d (%v)", len(results), len(fromYAML), err)
+	}
+
+	csvFile, err := os.Open(filepath.Join(dir, "detections_test.csv"))
+	if err != nil {
+		t.Fatalf("open csv: %v", err)
+	}
+	defer csvFile.Close()
+	records, err := csv.NewReader(csvFile).ReadAll()
+	if err != nil || len(records) != len(results)+1 {
+		t.Fatalf("expected %d csv rows, got %d (%v)", len(results)+1, len(records), err)
+	}
+
+	htmlData, err := os.ReadFile(filepath.Join(dir, "detections_test.html"))
+	if err != nil {
+		t.Fatalf("read html: %v", err)
+	}
+	if rows := strings.Count(string(htmlData), `<tr class="finding">`); rows != len(results) {
+		t.Fatalf("expected %d html rows, got %d", len(results), rows)
+	}
+
+	var log sarifLog
+	sarifData, err := os.ReadFile(filepath.Join(dir, "detections_test.sarif"))
+	if err != nil {
+		t.Fatalf("read sarif: %v", err)
+	}
+	if err := json.Unmarshal(sarifData, &log); err != nil || len(log.Runs) != 1 || len(log.Runs[0].Results) != len(results) {
+		t.Fatalf("expected %d sarif results (%v)", len(results), err)
+	}
+}
+
+func TestWriteDetectionsCycloneDX(t *testing.T) {
+	results := []detector.Result{
+		{
+			Target: "https://one.test", Detector: "version", Severity: "high",
+			Summary:  "WordPress version 5.8 detected (1 known vulnerabilities: CVE-2022-21661)",
+			Metadata: map[string]interface{}{"version": "5.8", "cve": []string{"CVE-2022-21661"}},
+		},
+		{
+			Target: "https://one.test", Detector: "plugins", Severity: "critical",
+			Summary: "contact-form-7 5.3.1 (2 known vulnerabilities: CVE-2020-35489, CVE-2023-6449)",
+			Metadata: map[string]interface{}{
+				"slug": "contact-form-7", "version": "5.3.1", "cve": []string{"CVE-2020-35489", "CVE-2023-6449"},
+				"cveSeverity": map[string]string{"CVE-2020-35489": "critical", "CVE-2023-6449": "high"},
+			},
+		},
+		{Target: "https://one.test", Detector: "php", Severity: "warning", Summary: "PHP 7.4 is end-of-life"},
+	}
+
+	path := filepath.Join(t.TempDir(), "detections.cyclonedx")
+	if err := writeDetectionsCycloneDX(path, results); err != nil {
+		t.Fatalf("write cyclonedx: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cyclonedx: %v", err)
+	}
+
+	var bom map[string]interface{}
+	if err := json.Unmarshal(data, &bom); err != nil {
+		t.Fatalf("parse cyclonedx: %v", err)
+	}
+	if bom["bomFormat"] != "CycloneDX" || bom["specVersion"] != "1.5" {
+		t.Fatalf("unexpected bom header: %v", bom)
+	}
+
+	components, ok := bom["components"].([]interface{})
+	if !ok || len(components) != 2 {
+		t.Fatalf("expected 2 components (core and plugin), got %v", bom["components"])
+	}
+
+	vulns, ok := bom["vulnerabilities"].([]interface{})
+	if !ok || len(vulns) != 3 {
+		t.Fatalf("expected a vulnerabilities array with 3 entries, got %v", bom["vulnerabilities"])
+	}
+	first := vulns[0].(map[string]interface{})
+	affects := first["affects"].([]interface{})
+	if first["id"] != "CVE-2022-21661" || affects[0].(map[string]interface{})["ref"] != "https://one.test#wordpress@5.8" {
+		t.Fatalf("unexpected vulnerability: %v", first)
+	}
+
+	// Each CVE keeps its own rating rather than the plugin result's highest severity.
+	ratings := map[string]interface{}{}
+	for _, v := range vulns {
+		vuln := v.(map[string]interface{})
+		ratings[vuln["id"].(string)] = vuln["ratings"].([]interface{})[0].(map[string]interface{})["severity"]
+	}
+	if ratings["CVE-2020-35489"] != "critical" || ratings["CVE-2023-6449"] != "high" || ratings["CVE-2022-21661"] != "high" {
+		t.Fatalf("unexpected per-CVE ratings: %v", ratings)
+	}
+}
+
+func TestWriteDetectionsCycloneDXFromDecodedResults(t *testing.T) {
+	// Results read back from a detections artifact carry JSON-decoded metadata.
+	var results []detector.Result
+	raw := `[{"target":"https://one.test","detector":"plugins","severity":"critical","summary":"contact-form-7 5.3.1",` +
+		`"metadata":{"slug":"contact-form-7","version":"5.3.1","cve":["CVE-2020-35489","CVE-2023-6449"],` +
+		`"cveSeverity":{"CVE-2020-35489":"critical","CVE-2023-6449":"high"}}}]`
+	if err := json.Unmarshal([]byte(raw), &results); err != nil {
+		t.Fatalf("decode results: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "detections.cyclonedx")
+	if err := writeDetectionsCycloneDX(path, results); err != nil {
+		t.Fatalf("write cyclonedx: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cyclonedx: %v", err)
+	}
+	var bom struct {
+		Vulnerabilities []struct {
+			ID      string `json:"id"`
+			Ratings []struct {
+				Severity string `json:"severity"`
+			} `json:"ratings"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(data, &bom); err != nil {
+		t.Fatalf("parse cyclonedx: %v", err)
+	}
+	if len(bom.Vulnerabilities) != 2 || bom.Vulnerabilities[1].ID != "CVE-2023-6449" || bom.Vulnerabilities[1].Ratings[0].Severity != "high" {
+		t.Fatalf("expected both decoded CVEs with their ratings, got %+v", bom.Vulnerabilities)
+	}
+}
+
+func TestWideCSVPivotsDetectorsPerTarget(t *testing.T) {
+	dir := t.TempDir()
+	results := []detector.Result{
+		{Target: "https://one.test", Detector: "version", Severity: "info", Summary: "WordPress version 6.5.1 detected"},
+		{Target: "https://one.test", Detector: "php", Severity: "warning", Summary: "PHP 7.4 is end-of-life"},
+		{Target: "https://two.test", Detector: "version", Severity: "info", Summary: "WordPress version 6.4.3 detected"},
+	}
+
+	converted, err := convertDetections(dir, "detections", []string{"csv"}, results, 0, outputOptions{CSVLayout: csvLayoutWide})
+	if err != nil || len(converted) != 1 {
+		t.Fatalf("convert detections: %v (%v)", err, converted)
+	}
+
+	file, err := os.Open(converted[0].Path)
+	if err != nil {
+		t.Fatalf("open csv: %v", err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+
+	want := [][]string{
+		{"target", "php_severity", "php_summary", "version_severity", "version_summary"},
+		{"https://one.test", "warning", "PHP 7.4 is end-of-life", "info", "WordPress version 6.5.1 detected"},
+		{"https://two.test", "", "", "info", "WordPress version 6.4.3 detected"},
+	}
+	if fmt.Sprint(records) != fmt.Sprint(want) {
+		t.Fatalf("wide csv = %q, want %q", records, want)
+	}
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/example/wphunter/internal/detector"
+	"github.com/example/wphunter/internal/vuln"
+)
+
+// cycloneDXBOM is the subset of a CycloneDX 1.5 JSON BOM needed for a vulnerability
+// disclosure report: the components found on each target and the CVEs affecting them.
+type cycloneDXBOM struct {
+	BOMFormat       string                   `json:"bomFormat"`
+	SpecVersion     string                   `json:"specVersion"`
+	SerialNumber    string                   `json:"serialNumber"`
+	Version         int                      `json:"version"`
+	Metadata        cycloneDXMetadata        `json:"metadata"`
+	Components      []cycloneDXComponent     `json:"components"`
+	Vulnerabilities []cycloneDXVulnerability `json:"vulnerabilities"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string          `json:"timestamp"`
+	Tools     []cycloneDXTool `json:"tools"`
+}
+
+type cycloneDXTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXVulnerability struct {
+	ID          string            `json:"id"`
+	Source      cycloneDXSource   `json:"source"`
+	Ratings     []cycloneDXRating `json:"ratings"`
+	Description string            `json:"description,omitempty"`
+	Affects     []cycloneDXAffect `json:"affects"`
+}
+
+type cycloneDXSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type cycloneDXRating struct {
+	Severity string `json:"severity"`
+}
+
+type cycloneDXAffect struct {
+	Ref string `json:"ref"`
+}
+
+// cycloneDXSeverity maps detector severities onto the CycloneDX rating enum.
+func cycloneDXSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high", "medium", "low", "info":
+		return strings.ToLower(severity)
+	case "warning":
+		return "medium"
+	default:
+		return "unknown"
+	}
+}
+
+// writeDetectionsCycloneDX renders results naming a component (plugins, themes, and
+// WordPress core from the version detector) as a CycloneDX BOM. CVEs attached by the
+// vulnerability feed become vulnerabilities affecting those components; findings
+// without a component are left out.
+func writeDetectionsCycloneDX(path string, results []detector.Result) error {
+	if err := ensureOutputDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	serial, err := newURNUUID()
+	if err != nil {
+		return err
+	}
+
+	bom := cycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: serial,
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools:     []cycloneDXTool{{Name: "wphunter", Version: version}},
+		},
+		Components:      []cycloneDXComponent{},
+		Vulnerabilities: []cycloneDXVulnerability{},
+	}
+
+	components := map[string]struct{}{}
+	vulnIndex := map[string]int{}
+	for _, res := range results {
+		slug, componentVersion := vuln.Component(res)
+		if slug == "" {
+			continue
+		}
+
+		ref := fmt.Sprintf("%s#%s@%s", res.Target, slug, componentVersion)
+		if _, seen := components[ref]; !seen {
+			components[ref] = struct{}{}
+			componentType := "library"
+			if slug == vuln.CoreSlug {
+				componentType = "application"
+			}
+			bom.Components = append(bom.Components, cycloneDXComponent{
+				Type:       componentType,
+				BOMRef:     ref,
+				Name:       slug,
+				Version:    componentVersion,
+				Properties: []cycloneDXProperty{{Name: "wphunter:target", Value: res.Target}},
+			})
+		}
+
+		for _, cve := range resultCVEs(res) {
+			if i, ok := vulnIndex[cve]; ok {
+				bom.Vulnerabilities[i].Affects = append(bom.Vulnerabilities[i].Affects, cycloneDXAffect{Ref: ref})
+				continue
+			}
+			vulnIndex[cve] = len(bom.Vulnerabilities)
+			bom.Vulnerabilities = append(bom.Vulnerabilities, cycloneDXVulnerability{
+				ID:          cve,
+				Source:      cycloneDXSource{Name: "NVD", URL: "https://nvd.nist.gov/vuln/detail/" + cve},
+				Ratings:     []cycloneDXRating{{Severity: cycloneDXSeverity(cveSeverity(res, cve))}},
+				Description: res.Summary,
+				Affects:     []cycloneDXAffect{{Ref: ref}},
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// resultCVEs returns the CVE IDs vuln.Enrich recorded on res. Both the live slice and
+// its decoded JSON form (from replayed, baseline, or per-target artifacts) are accepted.
+func resultCVEs(res detector.Result) []string {
+	switch cves := res.Metadata[detector.MetaCVE].(type) {
+	case []string:
+		return cves
+	case []interface{}:
+		out := make([]string, 0, len(cves))
+		for _, cve := range cves {
+			if id, ok := cve.(string); ok && id != "" {
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// cveSeverity returns the feed severity vuln.Enrich recorded for cve, falling back to
+// the result's severity (the highest among its CVEs) when none was recorded. Both the
+// live map and its decoded JSON form are accepted.
+func cveSeverity(res detector.Result, cve string) string {
+	var severity string
+	switch bySeverity := res.Metadata[detector.MetaCVESeverity].(type) {
+	case map[string]string:
+		severity = bySeverity[cve]
+	case map[string]interface{}:
+		severity, _ = bySeverity[cve].(string)
+	}
+	if severity == "" {
+		return res.Severity
+	}
+	return severity
+}
+
+// newURNUUID returns a random (version 4) UUID in the urn:uuid form CycloneDX expects.
+func newURNUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
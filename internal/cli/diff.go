@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/example/wphunter/internal/detector"
+	"github.com/spf13/cobra"
+)
+
+// detectionDiff compares two detections artifacts. Findings are matched by target and
+// detector; a finding present in both runs whose severity, summary, or metadata
+// differs is reported as changed with one entry per differing field.
+type detectionDiff struct {
+	Added   []detector.Result `json:"added"`
+	Removed []detector.Result `json:"removed"`
+	Changed []changedFinding  `json:"changed"`
+}
+
+// changedFinding is a finding that persisted between runs with different fields.
+type changedFinding struct {
+	Target   string        `json:"target"`
+	Detector string        `json:"detector"`
+	Changes  []fieldChange `json:"changes"`
+}
+
+// fieldChange is one differing field. Metadata keys are prefixed with "metadata.".
+type fieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+func newDiffCmd() *cobra.Command {
+	var maxInputBytes int64
+
+	cmd := &cobra.Command{
+		Use:   "diff <baseline.json> <current.json>",
+		Short: "Compare two detections artifacts, reporting added, removed, and changed findings",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var runs [2][]detector.Result
+			for i, path := range args {
+				data, err := readReportInput(path, cmd.InOrStdin(), maxInputBytes)
+				if err != nil {
+					return err
+				}
+				if runs[i], err = parseDetections(data); err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+			}
+
+			out, err := json.MarshalIndent(diffDetections(runs[0], runs[1]), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64Var(&maxInputBytes, "max-input-bytes", defaultReportMaxInputBytes, "Maximum artifact size to read in bytes (0 disables the limit)")
+	return cmd
+}
+
+type findingKey struct {
+	target   string
+	detector string
+}
+
+// diffDetections compares current against baseline. Results keep the order of the
+// artifact they came from.
+func diffDetections(baseline, current []detector.Result) detectionDiff {
+	diff := detectionDiff{Added: []detector.Result{}, Removed: []detector.Result{}, Changed: []changedFinding{}}
+
+	before := make(map[findingKey]detector.Result, len(baseline))
+	for _, res := range baseline {
+		before[findingKey{res.Target, res.Detector}] = res
+	}
+
+	seen := make(map[findingKey]struct{}, len(current))
+	for _, res := range current {
+		key := findingKey{res.Target, res.Detector}
+		seen[key] = struct{}{}
+
+		old, ok := before[key]
+		if !ok {
+			diff.Added = append(diff.Added, res)
+			continue
+		}
+		if changes := resultChanges(old, res); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, changedFinding{Target: res.Target, Detector: res.Detector, Changes: changes})
+		}
+	}
+
+	for _, res := range baseline {
+		if _, ok := seen[findingKey{res.Target, res.Detector}]; !ok {
+			diff.Removed = append(diff.Removed, res)
+		}
+	}
+	return diff
+}
+
+// resultChanges lists the fields that differ between two runs of the same finding,
+// sorted by field name. The explanation is ignored since it restates the evidence.
+func resultChanges(before, after detector.Result) []fieldChange {
+	var changes []fieldChange
+	if before.Severity != after.Severity {
+		changes = append(changes, fieldChange{Field: "severity", Before: before.Severity, After: after.Severity})
+	}
+	if before.Summary != after.Summary {
+		changes = append(changes, fieldChange{Field: "summary", Before: before.Summary, After: after.Summary})
+	}
+
+	keys := map[string]struct{}{}
+	for key := range before.Metadata {
+		keys[key] = struct{}{}
+	}
+	for key := range after.Metadata {
+		keys[key] = struct{}{}
+	}
+	delete(keys, detector.MetaExplanation)
+
+	for key := range keys {
+		old, hadOld := before.Metadata[key]
+		cur, hasCur := after.Metadata[key]
+		if hadOld && hasCur && sameJSON(old, cur) {
+			continue
+		}
+		changes = append(changes, fieldChange{Field: "metadata." + key, Before: old, After: cur})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// sameJSON compares values by their JSON encoding, so metadata decoded from an
+// artifact matches the typed values a live scan produces.
+func sameJSON(a, b interface{}) bool {
+	left, errA := json.Marshal(a)
+	right, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(left, right)
+}
+
+// batchSuffixPattern matches the _batchN suffix --batch-size adds to artifact names.
+var batchSuffixPattern = regexp.MustCompile(`_batch[0-9]+$`)
+
+// latestDetectionsArtifacts returns the detections_*.json files of the most recent
+// run in dir, or nil when there are none. The newest file by modification time (ties
+// go to the later name) picks the run, and every file sharing its timestamp is
+// returned, so a --batch-size run contributes all of its batches. Paths come back
+// sorted by name.
+func latestDetectionsArtifacts(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "detections_*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var regular []string
+	var latest string
+	var latestMod int64
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		regular = append(regular, path)
+		mod := info.ModTime().UnixNano()
+		if latest == "" || mod > latestMod || (mod == latestMod && path > latest) {
+			latest, latestMod = path, mod
+		}
+	}
+	if latest == "" {
+		return nil, nil
+	}
+
+	run := detectionsRunKey(latest)
+	var selected []string
+	for _, path := range regular {
+		if detectionsRunKey(path) == run {
+			selected = append(selected, path)
+		}
+	}
+	sort.Strings(selected)
+	return selected, nil
+}
+
+// detectionsRunKey strips the batch suffix from a detections artifact name, leaving
+// the run timestamp (and watch cycle) shared by every batch of one run.
+func detectionsRunKey(path string) string {
+	return batchSuffixPattern.ReplaceAllString(strings.TrimSuffix(filepath.Base(path), ".json"), "")
+}
+
+// loadDetectionsArtifact reads a flat or grouped detections artifact from path.
+func loadDetectionsArtifact(path string) ([]detector.Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	results, err := parseDetections(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return results, nil
+}
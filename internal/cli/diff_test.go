@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/example/wphunter/internal/detector"
+)
+
+func TestDiffCommandReportsMetadataChanges(t *testing.T) {
+	baseline := writeDetectionsFixture(t, []detector.Result{
+		{Target: "https://one.test", Detector: "version", Severity: "info", Summary: "WordPress detected", Metadata: map[string]interface{}{"version": "6.4", "source": "meta-generator"}},
+		{Target: "https://one.test", Detector: "vcs", Severity: "critical", Summary: "Exposed .git/config"},
+	})
+	current := writeDetectionsFixture(t, []detector.Result{
+		{Target: "https://one.test", Detector: "version", Severity: "info", Summary: "WordPress detected", Metadata: map[string]interface{}{"version": "6.5", "source": "meta-generator"}},
+		{Target: "https://one.test", Detector: "php", Severity: "warning", Summary: "End-of-life PHP version 7.4 detected"},
+	})
+
+	cmd := newDiffCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{baseline, current})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("diff command failed: %v", err)
+	}
+
+	var diff detectionDiff
+	if err := json.Unmarshal(buf.Bytes(), &diff); err != nil {
+		t.Fatalf("parse output %q: %v", buf.String(), err)
+	}
+
+	if len(diff.Added) != 1 || diff.Added[0].Detector != "php" {
+		t.Fatalf("expected php finding added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Detector != "vcs" {
+		t.Fatalf("expected vcs finding removed, got %+v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Detector != "version" {
+		t.Fatalf("expected version finding changed, got %+v", diff.Changed)
+	}
+
+	changes := diff.Changed[0].Changes
+	if len(changes) != 1 || changes[0].Field != "metadata.version" || changes[0].Before != "6.4" || changes[0].After != "6.5" {
+		t.Fatalf("expected a single version change 6.4 -> 6.5, got %+v", changes)
+	}
+}
+
+func TestDiffDetectionsMatchesTypedAndDecodedMetadata(t *testing.T) {
+	live := []detector.Result{{Target: "https://one.test", Detector: "admintools", Metadata: map[string]interface{}{"tools": []map[string]string{{"tool": "Adminer"}}}}}
+	decoded := []detector.Result{{Target: "https://one.test", Detector: "admintools", Metadata: map[string]interface{}{"tools": []interface{}{map[string]interface{}{"tool": "Adminer"}}}}}
+
+	if diff := diffDetections(decoded, live); len(diff.Changed) != 0 {
+		t.Fatalf("expected equal metadata after JSON round trip, got %+v", diff.Changed)
+	}
+}
+
+func TestLatestDetectionsArtifactsReturnsEveryBatchOfTheNewestRun(t *testing.T) {
+	dir := t.TempDir()
+	older := filepath.Join(dir, "detections_20240101_000000_batch1.json")
+	batch1 := filepath.Join(dir, "detections_20240201_000000_batch1.json")
+	batch2 := filepath.Join(dir, "detections_20240201_000000_batch2.json")
+	for _, path := range []string{older, batch1, batch2} {
+		if err := writeDetectionsArtifact(path, []detector.Result{}); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(older, past.Add(-time.Hour), past.Add(-time.Hour)); err != nil {
+		t.Fatalf("age older detections: %v", err)
+	}
+	// The first batch finishes before the second, so only batch2 is the newest file.
+	if err := os.Chtimes(batch1, past, past); err != nil {
+		t.Fatalf("age batch1 detections: %v", err)
+	}
+
+	paths, err := latestDetectionsArtifacts(dir)
+	if err != nil {
+		t.Fatalf("latest detections: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != batch1 || paths[1] != batch2 {
+		t.Fatalf("expected both batches of the newest run, got %v", paths)
+	}
+}
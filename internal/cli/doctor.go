@@ -2,11 +2,19 @@ package cli
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/example/wphunter/internal/config"
@@ -19,11 +27,25 @@ type doctorCheck struct {
 	Status string // "✓" (pass), "✗" (fail), or "⊘" (skipped)
 	Detail string
 	Error  error
+	// Category classifies a failed network probe (see classifyNetworkError).
+	Category string
 }
 
+// Network error categories reported by the doctor.
+const (
+	networkErrorDNS     = "DNS failure"
+	networkErrorRefused = "connection refused"
+	networkErrorReset   = "connection reset"
+	networkErrorTimeout = "timeout"
+	networkErrorTLS     = "TLS error"
+	networkErrorURL     = "invalid URL"
+	networkErrorOther   = "other"
+)
+
 func newDoctorCmd(loader *config.Loader) *cobra.Command {
 	flags := &runtimeFlagSet{}
 	var timeout int
+	var ascii bool
 
 	cmd := &cobra.Command{
 		Use:   "doctor",
@@ -43,8 +65,13 @@ func newDoctorCmd(loader *config.Loader) *cobra.Command {
 			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
 			defer cancel()
 
+			// A non-empty NO_COLOR (https://no-color.org) also opts out of unicode status glyphs.
+			if os.Getenv("NO_COLOR") != "" {
+				ascii = true
+			}
+
 			checks := runDoctorChecks(ctx, &cfg)
-			printDoctorReport(cmd, checks)
+			printDoctorReport(cmd, checks, ascii)
 
 			// Return error if any check failed
 			for _, check := range checks {
@@ -53,13 +80,14 @@ func newDoctorCmd(loader *config.Loader) *cobra.Command {
 				}
 			}
 
-			fmt.Fprintln(cmd.OutOrStdout(), "\n✓ All checks passed. System is ready.")
+			fmt.Fprintf(cmd.OutOrStdout(), "\n%s All checks passed. System is ready.\n", doctorStatusSymbol("✓", ascii))
 			return nil
 		},
 	}
 
 	bindRuntimeFlags(cmd, flags)
 	cmd.Flags().IntVar(&timeout, "timeout", 30, "Timeout in seconds for network checks")
+	cmd.Flags().BoolVar(&ascii, "ascii", false, "Print [OK]/[FAIL]/[SKIP] instead of unicode status symbols (also enabled by NO_COLOR)")
 
 	return cmd
 }
@@ -84,7 +112,10 @@ func runDoctorChecks(ctx context.Context, cfg *config.RuntimeConfig) []doctorChe
 	// Check 4: Network reachability to targets
 	if len(cfg.Targets) > 0 && !cfg.DryRun {
 		networkChecks := checkNetworkReachability(ctx, cfg.Targets)
-		checks = append(checks, networkChecks...)
+		checks = append(checks, limitNetworkChecks(networkChecks, maxNetworkLines)...)
+		if summary, ok := summarizeNetworkErrors(networkChecks); ok {
+			checks = append(checks, summary)
+		}
 	}
 
 	// Check 5: Configuration validity
@@ -185,16 +216,18 @@ func checkWPProbeDatabase(ctx context.Context) doctorCheck {
 	}
 }
 
-func checkNetworkReachability(ctx context.Context, targets []string) []doctorCheck {
-	checks := []doctorCheck{}
-
-	// Limit to first 3 targets for performance
-	maxChecks := 3
-	originalTargetCount := len(targets)
-	if len(targets) > maxChecks {
-		targets = targets[:maxChecks]
-	}
+const (
+	// maxNetworkProbes bounds how many targets doctor probes at once.
+	maxNetworkProbes = 8
+	// maxNetworkLines caps the per-target lines in the report; the "Network errors"
+	// summary still counts every target.
+	maxNetworkLines = 10
+)
 
+// checkNetworkReachability probes every target, at most maxNetworkProbes at a time,
+// and returns one check per target in input order. Targets not probed before ctx
+// ends are reported as skipped rather than unreachable.
+func checkNetworkReachability(ctx context.Context, targets []string) []doctorCheck {
 	client := &http.Client{
 		Timeout: 5 * time.Second,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
@@ -202,43 +235,172 @@ func checkNetworkReachability(ctx context.Context, targets []string) []doctorChe
 		},
 	}
 
-	for _, target := range targets {
-		check := doctorCheck{
-			Name: fmt.Sprintf("Network: %s", target),
+	checks := make([]doctorCheck, len(targets))
+	sem := make(chan struct{}, maxNetworkProbes)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
 		}
-
-		req, err := http.NewRequestWithContext(ctx, "HEAD", target, nil)
-		if err != nil {
-			check.Status = "✗"
-			check.Detail = "Invalid URL"
-			check.Error = err
-			checks = append(checks, check)
+		if ctx.Err() != nil {
+			checks[i] = skippedNetworkCheck(target)
 			continue
 		}
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			checks[i] = probeTarget(ctx, client, target)
+		}(i, target)
+	}
+	wg.Wait()
+
+	return checks
+}
+
+// probeTarget sends a HEAD request to target and reports whether it answered.
+func probeTarget(ctx context.Context, client *http.Client, target string) doctorCheck {
+	check := doctorCheck{
+		Name: fmt.Sprintf("Network: %s", target),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", target, nil)
+	if err != nil {
+		check.Status = "✗"
+		check.Detail = "Invalid URL"
+		check.Error = err
+		check.Category = networkErrorURL
+		return check
+	}
 
-		resp, err := client.Do(req)
-		if err != nil {
-			check.Status = "✗"
-			check.Detail = "Unreachable"
-			check.Error = err
-		} else {
-			resp.Body.Close()
-			check.Status = "✓"
-			check.Detail = fmt.Sprintf("HTTP %d", resp.StatusCode)
+	resp, err := client.Do(req)
+	if err != nil && ctx.Err() != nil {
+		// The doctor's overall timeout ran out, which says nothing about the target.
+		return skippedNetworkCheck(target)
+	}
+	if err != nil {
+		check.Status = "✗"
+		check.Category = classifyNetworkError(err)
+		check.Detail = fmt.Sprintf("Unreachable (%s)", check.Category)
+		check.Error = err
+		return check
+	}
+	resp.Body.Close()
+	check.Status = "✓"
+	check.Detail = fmt.Sprintf("HTTP %d", resp.StatusCode)
+	return check
+}
+
+// skippedNetworkCheck reports a target left unprobed when the doctor ran out of time.
+func skippedNetworkCheck(target string) doctorCheck {
+	return doctorCheck{
+		Name:   fmt.Sprintf("Network: %s", target),
+		Status: "⊘",
+		Detail: "Skipped (doctor timeout reached)",
+	}
+}
+
+// limitNetworkChecks keeps the first limit per-target checks and replaces the rest
+// with a single line, leaving their failures to the "Network errors" summary.
+func limitNetworkChecks(checks []doctorCheck, limit int) []doctorCheck {
+	if len(checks) <= limit {
+		return checks
+	}
+
+	limited := append([]doctorCheck(nil), checks[:limit]...)
+	return append(limited, doctorCheck{
+		Name:   fmt.Sprintf("Network: ... (%d more targets)", len(checks)-limit),
+		Status: "⊘",
+		Detail: "Omitted for brevity; counted in Network errors",
+	})
+}
+
+// classifyNetworkError sorts a failed probe into a category so many unreachable
+// targets can be summarized by cause.
+func classifyNetworkError(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var certErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var recordErr tls.RecordHeaderError
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return networkErrorDNS
+	case isSyscallError(err, syscall.ECONNREFUSED, "connection refused", "actively refused"):
+		return networkErrorRefused
+	case isSyscallError(err, syscall.ECONNRESET, "connection reset", "forcibly closed"):
+		return networkErrorReset
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return networkErrorTimeout
+	case errors.As(err, &certErr), errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &recordErr):
+		return networkErrorTLS
+	default:
+		return networkErrorOther
+	}
+}
+
+// isSyscallError reports whether err wraps errno, or a system call error whose
+// message contains one of texts. Windows reports WSAECONNREFUSED and WSAECONNRESET,
+// which do not match the syscall constants, so their messages are checked too.
+func isSyscallError(err error, errno syscall.Errno, texts ...string) bool {
+	if errors.Is(err, errno) {
+		return true
+	}
+
+	var sysErr *os.SyscallError
+	if !errors.As(err, &sysErr) {
+		return false
+	}
+	msg := strings.ToLower(sysErr.Err.Error())
+	for _, text := range texts {
+		if strings.Contains(msg, text) {
+			return true
 		}
+	}
+	return false
+}
 
-		checks = append(checks, check)
+// summarizeNetworkErrors counts failed network probes per category, most common
+// first, returning false when every probe succeeded.
+func summarizeNetworkErrors(checks []doctorCheck) (doctorCheck, bool) {
+	counts := map[string]int{}
+	total := 0
+	for _, check := range checks {
+		if check.Category != "" {
+			counts[check.Category]++
+			total++
+		}
+	}
+	if total == 0 {
+		return doctorCheck{}, false
 	}
 
-	if originalTargetCount > maxChecks {
-		checks = append(checks, doctorCheck{
-			Name:   fmt.Sprintf("Network: ... (%d more targets)", originalTargetCount-maxChecks),
-			Status: "⊘",
-			Detail: "Skipped for brevity",
-		})
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, category)
 	}
+	sort.Slice(categories, func(i, j int) bool {
+		if counts[categories[i]] != counts[categories[j]] {
+			return counts[categories[i]] > counts[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
 
-	return checks
+	parts := make([]string, len(categories))
+	for i, category := range categories {
+		parts[i] = fmt.Sprintf("%d %s", counts[category], category)
+	}
+	// The summary carries an error so doctor fails even when every failing target's
+	// own line was omitted from the report.
+	return doctorCheck{
+		Name:   "Network errors",
+		Status: "✗",
+		Detail: fmt.Sprintf("%d unreachable: %s", total, strings.Join(parts, ", ")),
+		Error:  fmt.Errorf("%d of %d targets unreachable", total, len(checks)),
+	}, true
 }
 
 func checkConfiguration(cfg *config.RuntimeConfig) doctorCheck {
@@ -277,13 +439,32 @@ func checkOutputDirectory(outputDir string) doctorCheck {
 	}
 }
 
-func printDoctorReport(cmd *cobra.Command, checks []doctorCheck) {
+func printDoctorReport(cmd *cobra.Command, checks []doctorCheck, ascii bool) {
 	fmt.Fprintln(cmd.OutOrStdout(), "Running environment diagnostics...")
 
 	for _, check := range checks {
-		fmt.Fprintf(cmd.OutOrStdout(), "%s %-30s %s\n", check.Status, check.Name+":", check.Detail)
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %-30s %s\n", doctorStatusSymbol(check.Status, ascii), check.Name+":", check.Detail)
 		if check.Error != nil {
 			fmt.Fprintf(cmd.OutOrStderr(), "   Error: %v\n", check.Error)
 		}
 	}
 }
+
+// doctorStatusSymbol translates a check status into its ASCII form when requested,
+// for terminals and CI logs that render unicode glyphs poorly.
+func doctorStatusSymbol(status string, ascii bool) string {
+	if !ascii {
+		return status
+	}
+
+	switch status {
+	case "✓":
+		return "[OK]"
+	case "✗":
+		return "[FAIL]"
+	case "⊘":
+		return "[SKIP]"
+	default:
+		return status
+	}
+}
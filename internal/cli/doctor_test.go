@@ -3,14 +3,19 @@ package cli
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/example/wphunter/internal/config"
 )
@@ -250,9 +255,9 @@ func TestCheckNetworkReachability(t *testing.T) {
 			wantSuccess:    false,
 		},
 		{
-			name:           "more than max targets",
+			name:           "many targets",
 			targets:        []string{server.URL, server.URL, server.URL, server.URL, server.URL},
-			expectedChecks: 4, // maxNetworkChecks (3) + 1 summary message for remaining targets
+			expectedChecks: 5, // every target is probed; the report trims the lines
 			wantSuccess:    true,
 		},
 	}
@@ -348,7 +353,7 @@ func TestPrintDoctorReport(t *testing.T) {
 			cmd.SetOut(&stdout)
 			cmd.SetErr(&stderr)
 
-			printDoctorReport(cmd, tt.checks)
+			printDoctorReport(cmd, tt.checks, false)
 
 			// Check both stdout and stderr for expected output
 			output := stdout.String() + stderr.String()
@@ -361,6 +366,42 @@ func TestPrintDoctorReport(t *testing.T) {
 	}
 }
 
+func TestPrintDoctorReportASCII(t *testing.T) {
+	checks := []doctorCheck{
+		{Name: "Passing", Status: "✓", Detail: "OK"},
+		{Name: "Failing", Status: "✗", Detail: "Bad"},
+		{Name: "Skipped", Status: "⊘", Detail: "Not applicable"},
+	}
+
+	var unicodeOut, asciiOut bytes.Buffer
+	cmd := newDoctorCmd(&config.Loader{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	cmd.SetOut(&unicodeOut)
+	printDoctorReport(cmd, checks, false)
+
+	cmd.SetOut(&asciiOut)
+	printDoctorReport(cmd, checks, true)
+
+	for _, symbol := range []string{"[OK]", "[FAIL]", "[SKIP]"} {
+		if !strings.Contains(asciiOut.String(), symbol) {
+			t.Errorf("expected ASCII output to contain %q, got:\n%s", symbol, asciiOut.String())
+		}
+		if strings.Contains(unicodeOut.String(), symbol) {
+			t.Errorf("unicode output should not contain %q", symbol)
+		}
+	}
+
+	for _, glyph := range []string{"✓", "✗", "⊘"} {
+		if strings.Contains(asciiOut.String(), glyph) {
+			t.Errorf("ASCII output should not contain %q, got:\n%s", glyph, asciiOut.String())
+		}
+		if !strings.Contains(unicodeOut.String(), glyph) {
+			t.Errorf("expected unicode output to contain %q", glyph)
+		}
+	}
+}
+
 func TestRunDoctorChecks(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -480,3 +521,130 @@ dryRun: true
 		t.Errorf("expected output to contain 'diagnostics', got:\n%s", output)
 	}
 }
+
+func TestClassifyNetworkErrorConnectionRefused(t *testing.T) {
+	// Grab a free port, then close the listener so nothing accepts connections on it.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	target := "http://" + listener.Addr().String()
+	listener.Close()
+
+	checks := checkNetworkReachability(context.Background(), []string{target})
+	if len(checks) != 1 || checks[0].Error == nil {
+		t.Fatalf("expected one failed check, got %+v", checks)
+	}
+	if got := classifyNetworkError(checks[0].Error); got != networkErrorRefused {
+		t.Fatalf("expected %q, got %q (%v)", networkErrorRefused, got, checks[0].Error)
+	}
+	if checks[0].Category != networkErrorRefused || !strings.Contains(checks[0].Detail, "connection refused") {
+		t.Fatalf("expected the category on the check, got %+v", checks[0])
+	}
+}
+
+func TestClassifyNetworkError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "dns", err: &url.Error{Op: "Head", URL: "http://nx.test", Err: &net.DNSError{Err: "no such host", Name: "nx.test", IsNotFound: true}}, want: networkErrorDNS},
+		{name: "deadline", err: fmt.Errorf("head: %w", context.DeadlineExceeded), want: networkErrorTimeout},
+		{name: "reset", err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, want: networkErrorReset},
+		// Windows errnos (WSAECONNREFUSED) do not match syscall.ECONNREFUSED.
+		{name: "windows refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connectex", errors.New("No connection could be made because the target machine actively refused it."))}, want: networkErrorRefused},
+		{name: "other", err: errors.New("boom"), want: networkErrorOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyNetworkError(tt.err); got != tt.want {
+				t.Fatalf("classifyNetworkError(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeNetworkErrors(t *testing.T) {
+	checks := []doctorCheck{
+		{Name: "Network: a", Status: "✗", Category: networkErrorRefused},
+		{Name: "Network: b", Status: "✓"},
+		{Name: "Network: c", Status: "✗", Category: networkErrorDNS},
+		{Name: "Network: d", Status: "✗", Category: networkErrorRefused},
+	}
+	summary, ok := summarizeNetworkErrors(checks)
+	if !ok {
+		t.Fatal("expected a summary for failed probes")
+	}
+	if want := "3 unreachable: 2 connection refused, 1 DNS failure"; summary.Detail != want {
+		t.Fatalf("summary detail = %q, want %q", summary.Detail, want)
+	}
+
+	if _, ok := summarizeNetworkErrors(checks[1:2]); ok {
+		t.Fatal("expected no summary when every probe succeeded")
+	}
+}
+
+func TestDoctorCountsEveryUnreachableTarget(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	target := "http://" + listener.Addr().String()
+	listener.Close()
+
+	targets := make([]string, maxNetworkLines+5)
+	for i := range targets {
+		targets[i] = target
+	}
+
+	checks := checkNetworkReachability(context.Background(), targets)
+	if len(checks) != len(targets) {
+		t.Fatalf("expected a check per target, got %d", len(checks))
+	}
+	summary, ok := summarizeNetworkErrors(checks)
+	if !ok {
+		t.Fatal("expected a summary for failed probes")
+	}
+	if want := fmt.Sprintf("%d unreachable: %d connection refused", len(targets), len(targets)); summary.Detail != want {
+		t.Fatalf("summary detail = %q, want %q", summary.Detail, want)
+	}
+
+	lines := limitNetworkChecks(checks, maxNetworkLines)
+	if len(lines) != maxNetworkLines+1 || !strings.Contains(lines[maxNetworkLines].Name, "5 more targets") {
+		t.Fatalf("expected %d target lines and an omission line, got %+v", maxNetworkLines, lines)
+	}
+}
+
+func TestCheckNetworkReachabilitySkipsTargetsPastDeadline(t *testing.T) {
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer fast.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	targets := []string{fast.URL}
+	for i := 0; i < 3*maxNetworkProbes; i++ {
+		targets = append(targets, slow.URL)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	checks := checkNetworkReachability(ctx, targets)
+
+	if len(checks) != len(targets) || checks[0].Status != "✓" {
+		t.Fatalf("expected a check per target with the fast one reachable, got %+v", checks)
+	}
+	for _, check := range checks[1:] {
+		if check.Status != "⊘" || check.Category != "" {
+			t.Fatalf("expected targets cut off by the deadline to be skipped, got %+v", check)
+		}
+	}
+	if summary, ok := summarizeNetworkErrors(checks); ok {
+		t.Fatalf("expected no targets counted as unreachable, got %q", summary.Detail)
+	}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// encryptedSuffix is appended to an artifact path when it is encrypted at rest.
+const encryptedSuffix = ".enc"
+
+// loadEncryptionKey reads an AES key file holding 16, 24, or 32 raw bytes, or the
+// same in hex (as written by `openssl rand -hex 32`).
+func loadEncryptionKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read encryption key: %w", err)
+	}
+
+	key := data
+	if decoded, err := hex.DecodeString(string(bytes.TrimSpace(data))); err == nil {
+		key = decoded
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("encryption key %s must be 16, 24, or 32 bytes (raw or hex), got %d bytes", path, len(key))
+	}
+}
+
+// newGCM builds the AES-GCM AEAD used for artifacts.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptArtifact AES-GCM-encrypts the file at path into path + encryptedSuffix, with
+// the random nonce prepended to the ciphertext, then removes the plaintext. It
+// returns the encrypted path.
+func encryptArtifact(path string, key []byte) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read artifact to encrypt: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	encPath := path + encryptedSuffix
+	if err := os.WriteFile(encPath, gcm.Seal(nonce, nonce, data, nil), 0o600); err != nil {
+		return "", fmt.Errorf("write encrypted artifact: %w", err)
+	}
+	if err := os.Remove(path); err != nil {
+		return "", fmt.Errorf("remove plaintext artifact: %w", err)
+	}
+	return encPath, nil
+}
+
+// decryptArtifact reverses encryptArtifact, returning the plaintext of the file at path.
+func decryptArtifact(path string, key []byte) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("%s is too short to be an encrypted artifact", path)
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: wrong key or corrupted artifact", path)
+	}
+	return plaintext, nil
+}
+
+func newDecryptCmd() *cobra.Command {
+	var keyPath, outputPath string
+
+	cmd := &cobra.Command{
+		Use:   "decrypt <artifact.enc>",
+		Short: "Decrypt an artifact written with scan --encrypt-key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input := args[0]
+			if outputPath == "" {
+				if !strings.HasSuffix(input, encryptedSuffix) {
+					return fmt.Errorf("%s has no %s suffix; pass --output", input, encryptedSuffix)
+				}
+				outputPath = strings.TrimSuffix(input, encryptedSuffix)
+			}
+
+			key, err := loadEncryptionKey(keyPath)
+			if err != nil {
+				return err
+			}
+			plaintext, err := decryptArtifact(input, key)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(outputPath, plaintext, 0o600); err != nil {
+				return fmt.Errorf("write decrypted artifact: %w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&keyPath, "key", "", "AES key file used with scan --encrypt-key")
+	cmd.Flags().StringVar(&outputPath, "output", "", "Where to write the plaintext (default: the input path without "+encryptedSuffix+")")
+	if err := cmd.MarkFlagRequired("key"); err != nil {
+		panic(err)
+	}
+	return cmd
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/example/wphunter/internal/config"
+)
+
+// writeEncryptionKey generates a random AES-256 key and stores it hex-encoded.
+func writeEncryptionKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "artifacts.key")
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return path
+}
+
+func TestEncryptArtifactRoundTrip(t *testing.T) {
+	key, err := loadEncryptionKey(writeEncryptionKey(t))
+	if err != nil {
+		t.Fatalf("load key: %v", err)
+	}
+
+	plaintext := make([]byte, 4096)
+	if _, err := rand.Read(plaintext); err != nil {
+		t.Fatalf("generate plaintext: %v", err)
+	}
+	artifact := filepath.Join(t.TempDir(), "detections.json")
+	if err := os.WriteFile(artifact, plaintext, 0o600); err != nil {
+		t.Fatalf("write artifact: %v", err)
+	}
+
+	encPath, err := encryptArtifact(artifact, key)
+	if err != nil {
+		t.Fatalf("encrypt artifact: %v", err)
+	}
+	if encPath != artifact+encryptedSuffix {
+		t.Fatalf("unexpected encrypted path %s", encPath)
+	}
+	if _, err := os.Stat(artifact); !os.IsNotExist(err) {
+		t.Fatalf("expected the plaintext to be removed, stat err = %v", err)
+	}
+
+	decrypted, err := decryptArtifact(encPath, key)
+	if err != nil {
+		t.Fatalf("decrypt artifact: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("decrypted artifact differs from the original")
+	}
+
+	otherKey, err := loadEncryptionKey(writeEncryptionKey(t))
+	if err != nil {
+		t.Fatalf("load second key: %v", err)
+	}
+	if _, err := decryptArtifact(encPath, otherKey); err == nil {
+		t.Fatalf("expected decryption with the wrong key to fail")
+	}
+}
+
+func TestScanCommandEncryptsArtifacts(t *testing.T) {
+	keyPath := writeEncryptionKey(t)
+	outputDir := t.TempDir()
+	summaryPath := filepath.Join(outputDir, "summary.json")
+
+	cmd := newScanCmd(&config.Loader{ConfigPath: ""})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"--targets", "https://one.test",
+		"--dry-run",
+		"--detectors", "",
+		"--output-dir", outputDir,
+		"--formats", "json",
+		"--summary-file", summaryPath,
+		"--encrypt-key", keyPath,
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("scan command failed: %v", err)
+	}
+
+	if plain, _ := filepath.Glob(filepath.Join(outputDir, "*.json")); len(plain) != 0 {
+		t.Fatalf("expected no plaintext artifacts, found %v", plain)
+	}
+	encrypted, err := filepath.Glob(filepath.Join(outputDir, "scan_*.json"+encryptedSuffix))
+	if err != nil || len(encrypted) != 1 {
+		t.Fatalf("expected one encrypted artifact, got %v (%v)", encrypted, err)
+	}
+
+	decrypt := newDecryptCmd()
+	out := &bytes.Buffer{}
+	decrypt.SetOut(out)
+	decrypt.SetArgs([]string{"--key", keyPath, summaryPath + encryptedSuffix})
+	if err := decrypt.Execute(); err != nil {
+		t.Fatalf("decrypt command failed: %v", err)
+	}
+	data, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("read decrypted summary: %v", err)
+	}
+	if !bytes.Contains(data, []byte(encrypted[0])) {
+		t.Fatalf("expected the summary to list the encrypted artifact %s:\n%s", encrypted[0], data)
+	}
+}
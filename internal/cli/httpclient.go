@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/example/wphunter/internal/config"
+	"github.com/example/wphunter/internal/detector"
+	"github.com/example/wphunter/internal/tunnel"
+)
+
+// idleConnsPerThread scales the default idle pool with the thread count so
+// concurrent detectors reuse keep-alive connections instead of redialing.
+const idleConnsPerThread = 4
+
+// buildDetectorClient returns the HTTP client shared by detectors for one scan.
+// Responses are cached per scan so detectors requesting the same page share a
+// single upstream request, each host is resolved once per DNS cache TTL, requests
+// to hosts that answer slowly are spaced out, and rate-limited requests are retried
+// after the server's Retry-After. The returned cleanup func is always safe to call.
+func buildDetectorClient(ctx context.Context, cfg config.RuntimeConfig) (*http.Client, func(), error) {
+	cleanup := func() {}
+	transport := newDetectorTransport(cfg)
+
+	if cfg.SSHTunnel != "" {
+		tunnelCfg, err := tunnel.ParseConfig(cfg.SSHTunnel)
+		if err != nil {
+			return nil, cleanup, err
+		}
+		tunnelCfg.KeyFile = cfg.SSHKey
+
+		t, err := tunnel.Dial(ctx, tunnelCfg)
+		if err != nil {
+			return nil, cleanup, err
+		}
+
+		transport.Proxy = nil
+		transport.DialContext = t.DialContext
+		cleanup = func() { t.Close() }
+	} else {
+		// Names tunnelled through a bastion resolve on the far side, so only direct
+		// connections share the per-scan DNS cache.
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		transport.DialContext = detector.NewDNSCache(nil, detector.DefaultDNSCacheTTL).DialContext(dialer.DialContext)
+	}
+
+	if len(cfg.Resolve) > 0 {
+		overrides, err := config.ParseResolveOverrides(cfg.Resolve)
+		if err != nil {
+			return nil, cleanup, err
+		}
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		transport.DialContext = resolveDialer(dial, overrides)
+	}
+
+	if len(cfg.CACerts) > 0 {
+		pool, err := config.LoadCertPool(cfg.CACerts)
+		if err != nil {
+			return nil, cleanup, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	client := &http.Client{
+		Timeout:       detector.DefaultHTTPTimeout,
+		Transport:     detector.NewCachingTransport(detector.NewThrottlingTransport(detector.WithRetry(transport, 0, 0), 0, 0), detector.DefaultCacheTTL),
+		CheckRedirect: detector.CheckRedirect(detector.DefaultMaxRedirects),
+	}
+	return client, cleanup, nil
+}
+
+// newDetectorTransport clones the default transport and sizes its connection pool
+// from cfg, deriving unset limits from the thread count.
+func newDetectorTransport(cfg config.RuntimeConfig) *http.Transport {
+	threads := cfg.Threads
+	if threads < 1 {
+		threads = 1
+	}
+
+	maxIdle := cfg.MaxIdleConns
+	if maxIdle == 0 {
+		maxIdle = threads * idleConnsPerThread
+	}
+
+	maxPerHost := cfg.MaxConnsPerHost
+	if maxPerHost == 0 {
+		maxPerHost = threads
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = maxIdle
+	transport.MaxConnsPerHost = maxPerHost
+	transport.MaxIdleConnsPerHost = maxPerHost
+	return transport
+}
+
+// headerTransport sets a header on every request before handing it to base. The
+// value is read per request so it can follow a run ID that changes between cycles.
+type headerTransport struct {
+	base  http.RoundTripper
+	name  string
+	value func() string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request.
+	req = req.Clone(req.Context())
+	req.Header.Set(t.name, t.value())
+	return t.base.RoundTrip(req)
+}
+
+// validHeaderName reports whether name is a valid HTTP header field name (an RFC 7230 token).
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > 0x7e || r <= ' ' || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// withRequestHeader makes client send name: value() on every request, so site owners
+// can correlate a scan's requests in their logs.
+func withRequestHeader(client *http.Client, name string, value func() string) {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client.Transport = &headerTransport{base: base, name: name, value: value}
+}
+
+// resolveDialer wraps dial so connections to an overridden host go to its pinned IP.
+// Only the dialed address changes; the transport still derives the Host header and
+// TLS server name from the request URL.
+func resolveDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error), overrides map[string]string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err == nil {
+			if ip, ok := overrides[strings.ToLower(host)]; ok {
+				addr = net.JoinHostPort(ip, port)
+			}
+		}
+		return dial(ctx, network, addr)
+	}
+}
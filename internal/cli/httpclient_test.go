@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/example/wphunter/internal/config"
+	"github.com/example/wphunter/internal/detector"
+)
+
+func TestBuildDetectorClientConfiguresConnectionPool(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.RuntimeConfig
+		wantIdle    int
+		wantPerHost int
+	}{
+		{name: "derived from threads", cfg: config.RuntimeConfig{Threads: 8}, wantIdle: 32, wantPerHost: 8},
+		{name: "explicit limits", cfg: config.RuntimeConfig{Threads: 8, MaxIdleConns: 50, MaxConnsPerHost: 3}, wantIdle: 50, wantPerHost: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, cleanup, err := buildDetectorClient(context.Background(), tt.cfg)
+			if err != nil {
+				t.Fatalf("build client: %v", err)
+			}
+			defer cleanup()
+
+			caching, ok := client.Transport.(*detector.CachingTransport)
+			if !ok {
+				t.Fatalf("expected caching transport, got %T", client.Transport)
+			}
+			throttling, ok := caching.Base.(*detector.ThrottlingTransport)
+			if !ok {
+				t.Fatalf("expected throttling transport, got %T", caching.Base)
+			}
+			retry, ok := throttling.Base.(*detector.RetryTransport)
+			if !ok {
+				t.Fatalf("expected retry transport, got %T", throttling.Base)
+			}
+			transport, ok := retry.Base.(*http.Transport)
+			if !ok {
+				t.Fatalf("expected *http.Transport base, got %T", retry.Base)
+			}
+
+			if transport.MaxIdleConns != tt.wantIdle {
+				t.Fatalf("MaxIdleConns = %d, want %d", transport.MaxIdleConns, tt.wantIdle)
+			}
+			if transport.MaxConnsPerHost != tt.wantPerHost || transport.MaxIdleConnsPerHost != tt.wantPerHost {
+				t.Fatalf("per-host limits = %d/%d, want %d", transport.MaxConnsPerHost, transport.MaxIdleConnsPerHost, tt.wantPerHost)
+			}
+		})
+	}
+}
+
+func TestBuildDetectorClientResolveOverride(t *testing.T) {
+	var gotHost string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		_, _ = w.Write([]byte("origin"))
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("split server addr: %v", err)
+	}
+
+	client, cleanup, err := buildDetectorClient(context.Background(), config.RuntimeConfig{Threads: 1, Resolve: []string{"foo.test:127.0.0.1"}})
+	if err != nil {
+		t.Fatalf("build client: %v", err)
+	}
+	defer cleanup()
+
+	resp, err := client.Get("http://foo.test:" + port + "/")
+	if err != nil {
+		t.Fatalf("request through resolve override: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotHost != "foo.test:"+port {
+		t.Fatalf("expected Host header foo.test:%s to be preserved, got %q", port, gotHost)
+	}
+}
+
+func TestWithRequestHeaderSendsRunID(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Scan-ID")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, cleanup, err := buildDetectorClient(context.Background(), config.RuntimeConfig{Threads: 1})
+	if err != nil {
+		t.Fatalf("build client: %v", err)
+	}
+	defer cleanup()
+
+	meta := newScanMeta(nil, time.Now())
+	if meta.RunID == "" {
+		t.Fatal("expected a run ID")
+	}
+	withRequestHeader(client, "X-Scan-ID", func() string { return meta.RunID })
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+
+	if got != meta.RunID {
+		t.Fatalf("X-Scan-ID = %q, want run ID %q", got, meta.RunID)
+	}
+	if req.Header.Get("X-Scan-ID") != "" {
+		t.Fatal("expected the caller's request to be left unmodified")
+	}
+}
+
+func TestValidHeaderName(t *testing.T) {
+	for name, want := range map[string]bool{"X-Scan-ID": true, "": false, "X Scan": false, "X-Scan:": false} {
+		if got := validHeaderName(name); got != want {
+			t.Fatalf("validHeaderName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestBuildDetectorClientTrustsCACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// Without the CA, the test server's self-signed certificate must be rejected.
+	plain, cleanup, err := buildDetectorClient(context.Background(), config.RuntimeConfig{Threads: 1})
+	if err != nil {
+		t.Fatalf("build client: %v", err)
+	}
+	defer cleanup()
+	if resp, err := plain.Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Fatal("expected verification to fail without the CA certificate")
+	}
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caPath, caPEM, 0o600); err != nil {
+		t.Fatalf("write ca: %v", err)
+	}
+
+	client, cleanup, err := buildDetectorClient(context.Background(), config.RuntimeConfig{Threads: 1, CACerts: []string{caPath}})
+	if err != nil {
+		t.Fatalf("build client: %v", err)
+	}
+	defer cleanup()
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected verification to succeed with the CA certificate: %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// outputFormat describes one value accepted by --formats.
+type outputFormat struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// WPProbe is set when wpprobe writes the scan artifact in this format natively.
+	WPProbe bool `json:"wpprobe"`
+	// Detections is set when detector findings are rendered in this format.
+	Detections bool `json:"detections"`
+}
+
+// outputFormats lists every supported output format in config.SupportedFormats order.
+var outputFormats = []outputFormat{
+	{Name: "json", Description: "wpprobe scan results as JSON (detections are always written as JSON too)", WPProbe: true},
+	{Name: "csv", Description: "wpprobe scan results and detector findings as CSV", WPProbe: true, Detections: true},
+	{Name: "yaml", Description: "Detector findings as YAML", Detections: true},
+	{Name: "html", Description: "Detector findings as a standalone HTML report", Detections: true},
+	{Name: "sarif", Description: "Detector findings as SARIF 2.1.0 for code-scanning dashboards", Detections: true},
+	{Name: "cyclonedx", Description: "Detected components and CVEs as a CycloneDX 1.5 vulnerability disclosure report", Detections: true},
+}
+
+func newListFormatsCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "list-formats",
+		Short: "List the output formats accepted by --formats",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if asJSON {
+				data, err := json.MarshalIndent(outputFormats, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(data))
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			for _, format := range outputFormats {
+				fmt.Fprintf(w, "%s\t%s\n", format.Name, format.Description)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the formats as a JSON array")
+	return cmd
+}
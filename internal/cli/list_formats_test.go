@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/example/wphunter/internal/config"
+)
+
+func TestListFormatsCommand(t *testing.T) {
+	cmd := newListFormatsCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetArgs(nil)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list-formats failed: %v", err)
+	}
+	for _, name := range []string{"json", "csv"} {
+		if !strings.Contains(buf.String(), name+" ") {
+			t.Fatalf("expected %s in listing:\n%s", name, buf.String())
+		}
+	}
+
+	cmd = newListFormatsCmd()
+	buf.Reset()
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list-formats --json failed: %v", err)
+	}
+
+	var listed []outputFormat
+	if err := json.Unmarshal(buf.Bytes(), &listed); err != nil {
+		t.Fatalf("parse json listing: %v", err)
+	}
+	var names []string
+	for _, format := range listed {
+		names = append(names, format.Name)
+	}
+	if strings.Join(names, ",") != strings.Join(config.SupportedFormats, ",") {
+		t.Fatalf("listing %v out of sync with supported formats %v", names, config.SupportedFormats)
+	}
+}
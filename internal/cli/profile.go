@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// profiler runs the hidden --cpuprofile and --memprofile diagnostics for the
+// lifetime of one command.
+type profiler struct {
+	cpuFile *os.File
+	started bool
+}
+
+// start begins CPU profiling into cpuPath when it is set.
+func (p *profiler) start(cpuPath string) error {
+	p.started = true
+	if cpuPath == "" {
+		return nil
+	}
+
+	file, err := os.Create(cpuPath)
+	if err != nil {
+		return fmt.Errorf("create cpu profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(file); err != nil {
+		file.Close()
+		return fmt.Errorf("start cpu profile: %w", err)
+	}
+	p.cpuFile = file
+	return nil
+}
+
+// stop ends CPU profiling and writes a heap profile to memPath when it is set.
+// It does nothing when the command never started.
+func (p *profiler) stop(memPath string) error {
+	if !p.started {
+		return nil
+	}
+
+	if p.cpuFile != nil {
+		pprof.StopCPUProfile()
+		if err := p.cpuFile.Close(); err != nil {
+			return fmt.Errorf("write cpu profile: %w", err)
+		}
+		p.cpuFile = nil
+	}
+
+	if memPath == "" {
+		return nil
+	}
+	file, err := os.Create(memPath)
+	if err != nil {
+		return fmt.Errorf("create memory profile: %w", err)
+	}
+	defer file.Close()
+	// Collect garbage first so the profile reflects live memory.
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		return fmt.Errorf("write memory profile: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecuteWritesProfiles(t *testing.T) {
+	dir := t.TempDir()
+	cpuPath := filepath.Join(dir, "cpu.pprof")
+	memPath := filepath.Join(dir, "mem.pprof")
+
+	out := &bytes.Buffer{}
+	if err := execute([]string{"list-formats", "--cpuprofile", cpuPath, "--memprofile", memPath}, out); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if out.Len() == 0 {
+		t.Fatalf("expected list-formats output")
+	}
+
+	for _, path := range []string{cpuPath, memPath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("expected profile %s: %v", filepath.Base(path), err)
+		}
+		if info.Size() == 0 {
+			t.Fatalf("expected profile %s to be non-empty", filepath.Base(path))
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"github.com/example/wphunter/internal/detector"
+	"github.com/example/wphunter/internal/events"
+	"github.com/spf13/cobra"
+)
+
+func newReplayCmd() *cobra.Command {
+	var maxInputBytes int64
+
+	cmd := &cobra.Command{
+		Use:   "replay <detections.json>",
+		Short: "Re-emit a saved detections artifact as NDJSON detection events",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readReportInput(args[0], cmd.InOrStdin(), maxInputBytes)
+			if err != nil {
+				return err
+			}
+
+			results, err := parseDetections(data)
+			if err != nil {
+				return err
+			}
+
+			emitter := events.NewEmitter(cmd.OutOrStdout())
+			for _, res := range results {
+				if err := emitter.Emit(detectionEvent(res)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64Var(&maxInputBytes, "max-input-bytes", defaultReportMaxInputBytes, "Maximum artifact size to read in bytes (0 disables the limit)")
+	return cmd
+}
+
+// detectionEvent builds the `detection` event emitted for each detector result, so
+// live scans and replays feed log pipelines identical records.
+func detectionEvent(res detector.Result) events.Event {
+	return events.Event{
+		Type:    "detection",
+		Message: res.Summary,
+		Fields: map[string]interface{}{
+			"target":     res.Target,
+			"detector":   res.Detector,
+			"severity":   res.Severity,
+			"confidence": res.Confidence,
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/example/wphunter/internal/detector"
+)
+
+func TestReplayCommandEmitsOneEventPerResult(t *testing.T) {
+	results := []detector.Result{
+		{Target: "https://one.test", Detector: "version", Severity: "info", Summary: "WordPress version 6.5.1 detected"},
+		{Target: "https://two.test", Detector: "vcs", Severity: "critical", Summary: "Exposed .git/config"},
+		{Target: "https://one.test", Detector: "php", Severity: "warning", Summary: "PHP 7.4 is end-of-life"},
+	}
+	inputPath := writeDetectionsFixture(t, results)
+
+	cmd := newReplayCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{inputPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("replay command failed: %v", err)
+	}
+
+	var summaries []string
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var evt struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &evt); err != nil {
+			t.Fatalf("parse event %q: %v", scanner.Text(), err)
+		}
+		if evt.Type != "detection" {
+			t.Fatalf("unexpected event type %q", evt.Type)
+		}
+		summaries = append(summaries, evt.Message)
+	}
+
+	if len(summaries) != len(results) {
+		t.Fatalf("expected %d detection events, got %d", len(results), len(summaries))
+	}
+	for i, res := range results {
+		if summaries[i] != res.Summary {
+			t.Fatalf("event %d out of order: got %q, want %q", i, summaries[i], res.Summary)
+		}
+	}
+}
@@ -5,16 +5,29 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/example/wphunter/internal/detector"
 	"github.com/example/wphunter/internal/events"
+	"github.com/jmespath/go-jmespath"
 	"github.com/spf13/cobra"
 )
 
+// defaultReportMaxInputBytes caps how much of an artifact the report command will load into memory.
+const defaultReportMaxInputBytes = 64 * 1024 * 1024
+
 func newReportCmd() *cobra.Command {
 	var inputPath string
 	var summaryPath string
+	var queryExpr string
+	var maxInputBytes int64
+	var since string
+	var filterTags []string
+	var filterDetectors []string
 
 	cmd := &cobra.Command{
 		Use:   "report",
@@ -24,11 +37,49 @@ func newReportCmd() *cobra.Command {
 				return errors.New("--input is required")
 			}
 
-			data, err := os.ReadFile(inputPath)
+			data, err := readReportInput(inputPath, cmd.InOrStdin(), maxInputBytes)
 			if err != nil {
 				return err
 			}
 
+			if queryExpr != "" || since != "" || len(filterTags) > 0 || len(filterDetectors) > 0 {
+				results, err := parseDetections(data)
+				if err != nil {
+					return err
+				}
+
+				if since != "" {
+					cutoff, err := time.Parse(time.RFC3339, since)
+					if err != nil {
+						return fmt.Errorf("invalid --since value %q: expected RFC3339 (e.g. 2024-01-02T15:04:05Z): %w", since, err)
+					}
+					results = filterDetectionsSince(results, cutoff)
+				}
+
+				if len(filterTags) > 0 {
+					results = filterDetectionsByTag(results, filterTags)
+				}
+
+				if len(filterDetectors) > 0 {
+					results = filterDetectionsByDetector(results, filterDetectors)
+				}
+
+				var projection interface{} = results
+				if queryExpr != "" {
+					projection, err = queryDetections(results, queryExpr)
+					if err != nil {
+						return err
+					}
+				}
+
+				out, err := json.MarshalIndent(projection, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+
 			stats := map[string]interface{}{
 				"input":       inputPath,
 				"sizeBytes":   len(data),
@@ -52,8 +103,13 @@ func newReportCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&inputPath, "input", "", "Path to JSON scan artifact")
+	cmd.Flags().StringVar(&inputPath, "input", "", "Path to JSON scan artifact (use - to read from stdin)")
 	cmd.Flags().StringVar(&summaryPath, "summary-file", "", "Optional path to store summary JSON")
+	cmd.Flags().StringVar(&queryExpr, "query", "", "JMESPath expression applied to detection results (e.g. \"[?severity=='critical'].target\")")
+	cmd.Flags().StringVar(&since, "since", "", "Only keep detections found at or after this RFC3339 time; prints the filtered results (combines with --query)")
+	cmd.Flags().StringSliceVar(&filterTags, "filter-tag", nil, "Only keep detections carrying any of these tags (repeatable or comma-separated, e.g. owasp-a05)")
+	cmd.Flags().StringSliceVar(&filterDetectors, "detector", nil, "Only keep detections produced by these detectors (repeatable or comma-separated, e.g. version)")
+	cmd.Flags().Int64Var(&maxInputBytes, "max-input-bytes", defaultReportMaxInputBytes, "Maximum artifact size to read in bytes (0 disables the limit)")
 	if err := cmd.MarkFlagRequired("input"); err != nil {
 		panic(err)
 	}
@@ -61,6 +117,36 @@ func newReportCmd() *cobra.Command {
 	return cmd
 }
 
+// readReportInput loads the artifact from path, or from stdin when path is "-",
+// refusing inputs larger than maxBytes.
+func readReportInput(path string, stdin io.Reader, maxBytes int64) ([]byte, error) {
+	var src io.Reader
+	if path == "-" {
+		src = stdin
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		src = file
+	}
+
+	if maxBytes <= 0 {
+		return io.ReadAll(src)
+	}
+
+	// Read one extra byte so an input of exactly maxBytes is accepted.
+	data, err := io.ReadAll(io.LimitReader(src, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("input exceeds --max-input-bytes limit of %d bytes", maxBytes)
+	}
+	return data, nil
+}
+
 func writeReportSummary(path string, stats map[string]interface{}) error {
 	data, err := json.MarshalIndent(stats, "", "  ")
 	if err != nil {
@@ -68,3 +154,98 @@ func writeReportSummary(path string, stats map[string]interface{}) error {
 	}
 	return os.WriteFile(path, append(data, '\n'), 0o600)
 }
+
+// parseDetections decodes a detections artifact into detector results. Both the flat
+// array and the target-grouped object written by `scan --group-by target` are accepted;
+// grouped results are flattened in target order.
+func parseDetections(data []byte) ([]detector.Result, error) {
+	var flat []detector.Result
+	if err := json.Unmarshal(data, &flat); err == nil {
+		return flat, nil
+	}
+
+	var grouped map[string][]detector.Result
+	if err := json.Unmarshal(data, &grouped); err != nil {
+		return nil, fmt.Errorf("input is not a detections artifact: %w", err)
+	}
+
+	targets := make([]string, 0, len(grouped))
+	for target := range grouped {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	var results []detector.Result
+	for _, target := range targets {
+		results = append(results, grouped[target]...)
+	}
+	return results, nil
+}
+
+// filterDetectionsSince drops results detected before cutoff. Results without a
+// timestamp (artifacts written before DetectedAt existed) are treated as older.
+func filterDetectionsSince(results []detector.Result, cutoff time.Time) []detector.Result {
+	filtered := make([]detector.Result, 0, len(results))
+	for _, res := range results {
+		if !res.DetectedAt.Before(cutoff) {
+			filtered = append(filtered, res)
+		}
+	}
+	return filtered
+}
+
+// filterDetectionsByTag keeps results that carry at least one of tags (case-insensitive).
+func filterDetectionsByTag(results []detector.Result, tags []string) []detector.Result {
+	wanted := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		wanted[strings.ToLower(strings.TrimSpace(tag))] = struct{}{}
+	}
+
+	filtered := make([]detector.Result, 0, len(results))
+	for _, res := range results {
+		for _, tag := range res.Tags {
+			if _, ok := wanted[strings.ToLower(tag)]; ok {
+				filtered = append(filtered, res)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// filterDetectionsByDetector keeps results produced by one of detectors (case-insensitive).
+func filterDetectionsByDetector(results []detector.Result, detectors []string) []detector.Result {
+	wanted := make(map[string]struct{}, len(detectors))
+	for _, name := range detectors {
+		wanted[strings.ToLower(strings.TrimSpace(name))] = struct{}{}
+	}
+
+	filtered := make([]detector.Result, 0, len(results))
+	for _, res := range results {
+		if _, ok := wanted[strings.ToLower(res.Detector)]; ok {
+			filtered = append(filtered, res)
+		}
+	}
+	return filtered
+}
+
+// queryDetections evaluates a JMESPath expression against the JSON form of the results,
+// so expressions use the same field names that appear in artifacts.
+func queryDetections(results []detector.Result, expr string) (interface{}, error) {
+	compiled, err := jmespath.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --query expression: %w", err)
+	}
+
+	raw, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	return compiled.Search(doc)
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/example/wphunter/internal/detector"
+)
+
+// writeDetectionsFixture stores results as a detections artifact and returns its path.
+func writeDetectionsFixture(t *testing.T, results []detector.Result) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "detections.json")
+	if err := writeDetectionsArtifact(path, results); err != nil {
+		t.Fatalf("write detections fixture: %v", err)
+	}
+	return path
+}
+
+func TestReportCommandQuery(t *testing.T) {
+	inputPath := writeDetectionsFixture(t, []detector.Result{
+		{Target: "https://critical.test", Detector: "vcs", Severity: "critical", Summary: "Exposed .git/config"},
+		{Target: "https://info.test", Detector: "version", Severity: "info", Summary: "WordPress version 6.5.1 detected"},
+	})
+
+	cmd := newReportCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--input", inputPath, "--query", "[?severity=='critical'].target"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("report command failed: %v", err)
+	}
+
+	var projection []string
+	if err := json.Unmarshal(buf.Bytes(), &projection); err != nil {
+		t.Fatalf("parse query output %q: %v", buf.String(), err)
+	}
+
+	if len(projection) != 1 || projection[0] != "https://critical.test" {
+		t.Fatalf("expected only the critical target, got %v", projection)
+	}
+}
+
+func TestReportCommandQueryInvalidExpression(t *testing.T) {
+	inputPath := writeDetectionsFixture(t, []detector.Result{{Target: "https://one.test", Severity: "info"}})
+
+	cmd := newReportCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--input", inputPath, "--query", "[?severity=="})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected invalid query expression to fail")
+	}
+}
+
+func TestReportCommandRejectsOversizedInput(t *testing.T) {
+	inputPath := filepath.Join(t.TempDir(), "scan.json")
+	if err := os.WriteFile(inputPath, bytes.Repeat([]byte("a"), 128), 0o600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	cmd := newReportCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--input", inputPath, "--max-input-bytes", "64"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected oversized input to be rejected")
+	}
+
+	if !strings.Contains(err.Error(), "--max-input-bytes") {
+		t.Fatalf("expected size limit error, got %v", err)
+	}
+}
+
+func TestReportCommandReadsStdin(t *testing.T) {
+	cmd := newReportCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetIn(strings.NewReader(`{"vulnerability": "CVE-2024-0001"}`))
+	cmd.SetArgs([]string{"--input", "-"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("report command failed: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `"mentions":1`) {
+		t.Fatalf("expected stdin content to be reported, got %s", buf.String())
+	}
+}
+
+func TestReportCommandSince(t *testing.T) {
+	cutoff := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	inputPath := writeDetectionsFixture(t, []detector.Result{
+		{Target: "https://old.test", Severity: "info", DetectedAt: cutoff.Add(-time.Hour)},
+		{Target: "https://exact.test", Severity: "info", DetectedAt: cutoff},
+		{Target: "https://new.test", Severity: "critical", DetectedAt: cutoff.Add(time.Hour)},
+		{Target: "https://undated.test", Severity: "info"},
+	})
+
+	cmd := newReportCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--input", inputPath, "--since", cutoff.Format(time.RFC3339), "--query", "[].target"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("report command failed: %v", err)
+	}
+
+	var targets []string
+	if err := json.Unmarshal(buf.Bytes(), &targets); err != nil {
+		t.Fatalf("parse output %q: %v", buf.String(), err)
+	}
+
+	expected := []string{"https://exact.test", "https://new.test"}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Fatalf("expected %v, got %v", expected, targets)
+	}
+}
+
+func TestReportCommandSinceInvalid(t *testing.T) {
+	inputPath := writeDetectionsFixture(t, []detector.Result{{Target: "https://one.test", Severity: "info"}})
+
+	cmd := newReportCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--input", inputPath, "--since", "yesterday"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected invalid --since value to fail")
+	}
+}
+
+func TestReportCommandFilterDetector(t *testing.T) {
+	inputPath := writeDetectionsFixture(t, []detector.Result{
+		{Target: "https://one.test", Detector: "version", Severity: "info", Tags: []string{detector.TagOWASPOutdated}},
+		{Target: "https://one.test", Detector: "php", Severity: "warning", Tags: []string{detector.TagOWASPOutdated}},
+		{Target: "https://two.test", Detector: "version", Severity: "info"},
+	})
+
+	cmd := newReportCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--input", inputPath, "--detector", "version", "--filter-tag", detector.TagOWASPOutdated})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("report command failed: %v", err)
+	}
+
+	var results []detector.Result
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("parse output %q: %v", buf.String(), err)
+	}
+
+	if len(results) != 1 || results[0].Detector != "version" || results[0].Target != "https://one.test" {
+		t.Fatalf("expected only the tagged version result, got %+v", results)
+	}
+}
+
+func TestReportCommandFilterTag(t *testing.T) {
+	inputPath := writeDetectionsFixture(t, []detector.Result{
+		{Target: "https://git.test", Detector: "vcs", Severity: "critical", Tags: []string{detector.TagOWASPMisconfiguration}},
+		{Target: "https://php.test", Detector: "php", Severity: "warning", Tags: []string{detector.TagOWASPOutdated, "cis-1.2"}},
+		{Target: "https://plain.test", Detector: "version", Severity: "info"},
+	})
+
+	cmd := newReportCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--input", inputPath, "--filter-tag", "CIS-1.2", "--filter-tag", "owasp-a05"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("report command failed: %v", err)
+	}
+
+	var results []detector.Result
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("parse output %q: %v", buf.String(), err)
+	}
+
+	if len(results) != 2 || results[0].Target != "https://git.test" || results[1].Target != "https://php.test" {
+		t.Fatalf("unexpected filtered results: %+v", results)
+	}
+
+	if !reflect.DeepEqual(results[1].Tags, []string{detector.TagOWASPOutdated, "cis-1.2"}) {
+		t.Fatalf("tags did not survive the round trip: %v", results[1].Tags)
+	}
+}
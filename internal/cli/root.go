@@ -1,12 +1,20 @@
 package cli
 
 import (
+	"io"
+	"os"
+
 	"github.com/example/wphunter/internal/config"
 	"github.com/spf13/cobra"
 )
 
 // Execute builds the root command tree and runs the CLI.
 func Execute() error {
+	return execute(os.Args[1:], os.Stdout)
+}
+
+// execute runs the CLI with args, writing command output to out.
+func execute(args []string, out io.Writer) error {
 	loader := &config.Loader{ConfigPath: config.DefaultConfigPath}
 	rootOpts := &rootOptions{}
 
@@ -20,22 +28,48 @@ func Execute() error {
 	rootCmd.SetVersionTemplate("wphunter version {{.Version}}\n")
 
 	rootCmd.PersistentFlags().StringVar(&rootOpts.ConfigPath, "config", config.DefaultConfigPath, "Path to wphunter.config.yml (optional)")
-	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+	rootCmd.PersistentFlags().BoolVar(&rootOpts.AllowSystemPaths, "allow-system-paths", false, "Allow targets files under protected system paths such as /dev/shm (use with care)")
+	rootCmd.PersistentFlags().StringVar(&rootOpts.CPUProfile, "cpuprofile", "", "Write a CPU profile of the command to this file")
+	rootCmd.PersistentFlags().StringVar(&rootOpts.MemProfile, "memprofile", "", "Write a heap profile to this file when the command exits")
+	_ = rootCmd.PersistentFlags().MarkHidden("cpuprofile")
+	_ = rootCmd.PersistentFlags().MarkHidden("memprofile")
+
+	prof := &profiler{}
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		if rootOpts.ConfigPath != "" {
 			loader.ConfigPath = rootOpts.ConfigPath
 		}
+		loader.AllowSystemPaths = rootOpts.AllowSystemPaths
+		return prof.start(rootOpts.CPUProfile)
 	}
 
 	rootCmd.AddCommand(
 		newInitCmd(loader),
 		newScanCmd(loader),
 		newReportCmd(),
+		newReplayCmd(),
+		newDiffCmd(),
+		newDecryptCmd(),
+		newListFormatsCmd(),
+		newValidateCmd(),
 		newDoctorCmd(loader),
 	)
 
-	return rootCmd.Execute()
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(out)
+
+	// Profiles are written after the command returns so failed runs are profiled too.
+	err := rootCmd.Execute()
+	if stopErr := prof.stop(rootOpts.MemProfile); err == nil {
+		err = stopErr
+	}
+	return err
 }
 
 type rootOptions struct {
-	ConfigPath string
+	ConfigPath       string
+	AllowSystemPaths bool
+	// CPUProfile and MemProfile are the hidden pprof outputs for diagnosing slow scans.
+	CPUProfile string
+	MemProfile string
 }
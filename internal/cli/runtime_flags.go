@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/example/wphunter/internal/config"
 	"github.com/spf13/cobra"
@@ -9,27 +10,57 @@ import (
 
 // runtimeFlagSet tracks shared scan/init flags before they are converted into config overrides.
 type runtimeFlagSet struct {
-	targets     string
-	targetsFile string
-	mode        string
-	threads     int
-	outputDir   string
-	formats     string
-	detectors   string
-	dryRun      bool
-	summaryFile string
+	targets      string
+	targetsFile  string
+	targetsJSONL string
+	targetsFmt   string
+	csvColumn    string
+	targetPrefix string
+	targetSuffix string
+	mode         string
+	threads      threadsFlag
+	outputDir    string
+	formats      string
+	detectors    string
+	detectorFile string
+	disabledDets string
+	dryRun       bool
+	useXDG       bool
+	summaryFiles []string
+	sandboxRoot  string
+	sshTunnel    string
+	sshKey       string
+	resolve      []string
+	caCerts      []string
+	maxIdle      int
+	maxPerHost   int
 }
 
 func bindRuntimeFlags(cmd *cobra.Command, flags *runtimeFlagSet) {
 	cmd.Flags().StringVar(&flags.targets, "targets", "", "Comma-separated list of targets (overrides config)")
 	cmd.Flags().StringVar(&flags.targetsFile, "targets-file", "", "Path to a file with one target per line")
+	cmd.Flags().StringVar(&flags.targetsJSONL, "targets-jsonl", "", "JSON Lines targets file; each line is {\"url\":...} with optional per-target \"mode\" and \"detectors\" (same as --targets-file with --targets-file-format jsonl)")
+	cmd.Flags().StringVar(&flags.targetsFmt, "targets-file-format", "", "Targets file format: lines, csv, json, or jsonl (default lines)")
+	cmd.Flags().StringVar(&flags.csvColumn, "targets-csv-column", "", "CSV column or JSON field holding target URLs (default url)")
+	cmd.Flags().StringVar(&flags.targetPrefix, "target-prefix", "", "Prepend to each target's host after scheme normalization (e.g. www.; a scheme like http:// replaces https)")
+	cmd.Flags().StringVar(&flags.targetSuffix, "target-suffix", "", "Append to each target's path after scheme normalization (e.g. /wp/)")
 	cmd.Flags().StringVar(&flags.mode, "mode", "", "Scan mode: stealthy, bruteforce, or hybrid")
-	cmd.Flags().IntVar(&flags.threads, "threads", 0, fmt.Sprintf("Number of concurrent threads (1-%d)", config.MaxThreads))
+	cmd.Flags().Var(&flags.threads, "threads", fmt.Sprintf("Number of concurrent threads (1-%d, or %q for 4 per CPU)", config.MaxThreads, config.ThreadsAuto))
 	cmd.Flags().StringVar(&flags.outputDir, "output-dir", "", "Directory for scan artifacts")
-	cmd.Flags().StringVar(&flags.formats, "formats", "", "Comma-separated output formats (json,csv)")
+	cmd.Flags().StringVar(&flags.formats, "formats", "", "Comma-separated output formats (json,csv,yaml)")
 	cmd.Flags().StringVar(&flags.detectors, "detectors", "", "Comma-separated detectors to run (version,plugins,...)")
+	cmd.Flags().StringVar(&flags.detectorFile, "detectors-file", "", "File of detectors to run, one per line (# comments allowed); combined with --detectors")
+	cmd.Flags().StringVar(&flags.disabledDets, "disabled-detectors", "", "Comma-separated detectors to remove from the set even when requested (policy guardrail)")
 	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "Skip wpprobe execution and emit placeholder artifacts")
-	cmd.Flags().StringVar(&flags.summaryFile, "summary-file", "", "Optional summary JSON output path")
+	cmd.Flags().BoolVar(&flags.useXDG, "use-xdg", false, "Default --output-dir to $XDG_DATA_HOME/wphunter (or ~/.local/share/wphunter) instead of ./scan-results")
+	cmd.Flags().StringSliceVar(&flags.summaryFiles, "summary-file", nil, "Optional summary output path; repeatable, format follows the extension (.json, .yml/.yaml, .xml)")
+	cmd.Flags().StringVar(&flags.sandboxRoot, "sandbox-root", "", "Reject output and summary paths that resolve outside this directory")
+	cmd.Flags().StringVar(&flags.sshTunnel, "ssh-tunnel", "", "Route detector traffic through an SSH jump host (user@host[:port])")
+	cmd.Flags().StringVar(&flags.sshKey, "ssh-key", "", "Private key for --ssh-tunnel (defaults to ssh-agent)")
+	cmd.Flags().StringArrayVar(&flags.resolve, "resolve", nil, "Connect detector requests for host to ip instead of resolving it, as host:ip (repeatable); Host header and SNI are kept")
+	cmd.Flags().StringArrayVar(&flags.caCerts, "ca-cert", nil, "PEM file of CA certificates to trust for detector requests in addition to the system roots (repeatable)")
+	cmd.Flags().IntVar(&flags.maxIdle, "max-idle-conns", 0, "Idle keep-alive connections kept by the detector HTTP client (default 4x threads)")
+	cmd.Flags().IntVar(&flags.maxPerHost, "max-conns-per-host", 0, "Maximum concurrent detector connections per host (default threads)")
 }
 
 func (f runtimeFlagSet) toOverrides(cmd *cobra.Command) config.Overrides {
@@ -42,12 +73,33 @@ func (f runtimeFlagSet) toOverrides(cmd *cobra.Command) config.Overrides {
 		ov.TargetsFile = f.targetsFile
 	}
 
+	if cmd.Flags().Changed("targets-file-format") {
+		ov.TargetsFileFormat = f.targetsFmt
+	}
+
+	if cmd.Flags().Changed("targets-csv-column") {
+		ov.TargetsCSVColumn = f.csvColumn
+	}
+
+	if cmd.Flags().Changed("targets-jsonl") {
+		ov.TargetsFile = f.targetsJSONL
+		ov.TargetsFileFormat = config.TargetsFileFormatJSONL
+	}
+
+	if cmd.Flags().Changed("target-prefix") {
+		ov.TargetPrefix = f.targetPrefix
+	}
+
+	if cmd.Flags().Changed("target-suffix") {
+		ov.TargetSuffix = f.targetSuffix
+	}
+
 	if cmd.Flags().Changed("mode") {
 		ov.Mode = f.mode
 	}
 
 	if cmd.Flags().Changed("threads") {
-		ov.Threads = f.threads
+		ov.Threads = int(f.threads)
 		ov.ThreadsSet = true
 	}
 
@@ -63,13 +115,73 @@ func (f runtimeFlagSet) toOverrides(cmd *cobra.Command) config.Overrides {
 		ov.Detectors = config.ParseDetectors(f.detectors)
 	}
 
+	if cmd.Flags().Changed("detectors-file") {
+		ov.DetectorsFile = f.detectorFile
+	}
+
+	if cmd.Flags().Changed("disabled-detectors") {
+		ov.DisabledDetectors = config.ParseDetectors(f.disabledDets)
+	}
+
 	if cmd.Flags().Changed("dry-run") {
 		ov.DryRun = &f.dryRun
 	}
 
+	if cmd.Flags().Changed("use-xdg") {
+		ov.UseXDG = &f.useXDG
+	}
+
 	if cmd.Flags().Changed("summary-file") {
-		ov.SummaryFile = f.summaryFile
+		ov.SummaryFiles = f.summaryFiles
+	}
+
+	if cmd.Flags().Changed("sandbox-root") {
+		ov.SandboxRoot = f.sandboxRoot
+	}
+
+	if cmd.Flags().Changed("ssh-tunnel") {
+		ov.SSHTunnel = f.sshTunnel
+	}
+
+	if cmd.Flags().Changed("ssh-key") {
+		ov.SSHKey = f.sshKey
+	}
+
+	if cmd.Flags().Changed("resolve") {
+		ov.Resolve = f.resolve
+	}
+
+	if cmd.Flags().Changed("ca-cert") {
+		ov.CACerts = f.caCerts
+	}
+
+	if cmd.Flags().Changed("max-idle-conns") {
+		ov.MaxIdleConns = f.maxIdle
+	}
+
+	if cmd.Flags().Changed("max-conns-per-host") {
+		ov.MaxConnsPerHost = f.maxPerHost
 	}
 
 	return ov
 }
+
+// threadsFlag accepts an integer or "auto" for --threads, resolving auto at parse time.
+type threadsFlag int
+
+func (t *threadsFlag) String() string {
+	return strconv.Itoa(int(*t))
+}
+
+func (t *threadsFlag) Set(value string) error {
+	threads, err := config.ParseThreads(value)
+	if err != nil {
+		return err
+	}
+	*t = threadsFlag(threads)
+	return nil
+}
+
+func (t *threadsFlag) Type() string {
+	return "int|auto"
+}
@@ -62,6 +62,16 @@ func TestRuntimeFlagSetToOverrides(t *testing.T) {
 				ThreadsSet: true,
 			},
 		},
+		{
+			name: "threads auto resolves from CPU count",
+			setup: func(cmd *cobra.Command, flags *runtimeFlagSet) {
+				cmd.Flags().Set("threads", "auto")
+			},
+			expected: config.Overrides{
+				Threads:    config.AutoThreads(),
+				ThreadsSet: true,
+			},
+		},
 		{
 			name: "output-dir flag changed",
 			setup: func(cmd *cobra.Command, flags *runtimeFlagSet) {
@@ -115,11 +125,20 @@ func TestRuntimeFlagSetToOverrides(t *testing.T) {
 		{
 			name: "summary-file flag changed",
 			setup: func(cmd *cobra.Command, flags *runtimeFlagSet) {
-				flags.summaryFile = "/path/to/summary.json"
-				cmd.Flags().Set("summary-file", flags.summaryFile)
+				cmd.Flags().Set("summary-file", "/path/to/summary.json")
+			},
+			expected: config.Overrides{
+				SummaryFiles: []string{"/path/to/summary.json"},
+			},
+		},
+		{
+			name: "sandbox-root flag changed",
+			setup: func(cmd *cobra.Command, flags *runtimeFlagSet) {
+				flags.sandboxRoot = "/workspace"
+				cmd.Flags().Set("sandbox-root", flags.sandboxRoot)
 			},
 			expected: config.Overrides{
-				SummaryFile: "/path/to/summary.json",
+				SandboxRoot: "/workspace",
 			},
 		},
 		{
@@ -187,15 +206,15 @@ func TestRuntimeFlagSetToOverridesUnchangedFlags(t *testing.T) {
 		Use: "test",
 	}
 	flags := &runtimeFlagSet{
-		targets:     "https://default.com",
-		targetsFile: "/default/targets.txt",
-		mode:        "hybrid",
-		threads:     10,
-		outputDir:   "/default/output",
-		formats:     "json",
-		detectors:   "version",
-		dryRun:      false,
-		summaryFile: "/default/summary.json",
+		targets:      "https://default.com",
+		targetsFile:  "/default/targets.txt",
+		mode:         "hybrid",
+		threads:      10,
+		outputDir:    "/default/output",
+		formats:      "json",
+		detectors:    "version",
+		dryRun:       false,
+		summaryFiles: []string{"/default/summary.json"},
 	}
 	bindRuntimeFlags(cmd, flags)
 
@@ -1,28 +1,178 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/example/wphunter/internal/config"
 	"github.com/example/wphunter/internal/detector"
 	"github.com/example/wphunter/internal/events"
+	"github.com/example/wphunter/internal/findingsdb"
+	"github.com/example/wphunter/internal/tui"
+	"github.com/example/wphunter/internal/vuln"
 	"github.com/example/wphunter/internal/wpprobe"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
+// defaultScanTimeout bounds a whole scan invocation unless --timeout overrides it.
+const defaultScanTimeout = 30 * time.Minute
+
+// targetsTempPattern names the per-batch targets files handed to wpprobe.
+const targetsTempPattern = "wphunter-targets-*.txt"
+
+// staleTempAge is how old a leftover targets file must be before a new scan removes
+// it. Files of scans still running in parallel are much younger, so they are kept.
+const staleTempAge = time.Hour
+
+// minVersionPattern accepts the dotted numeric versions --min-wp-version compares against.
+var minVersionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+){0,2}$`)
+
+// newWPProbeRunner constructs the wpprobe runner used by scan; tests replace it.
+var newWPProbeRunner = wpprobe.NewRunner
+
+// scanOptions holds scan-only flags that do not participate in layered configuration.
+type scanOptions struct {
+	groupBy    string
+	batchSize  int
+	syslog     bool
+	syslogAddr string
+	// otlpEndpoint exports a trace of the scan via OTLP/HTTP when set.
+	otlpEndpoint string
+	// confirmWordPress skips wpprobe for targets that do not look like WordPress.
+	confirmWordPress bool
+	vulnFeed         string
+	timeout          time.Duration
+	// perTargetTimeout bounds the time all detectors may spend on one target.
+	perTargetTimeout time.Duration
+	// dedupFindings collapses findings sharing dedupKey before artifacts are written.
+	dedupFindings bool
+	dedupKey      []string
+	// dbPath, when set, also inserts findings into a SQLite database.
+	dbPath string
+	// interactive replaces the NDJSON stream on stdout with a terminal UI.
+	interactive bool
+	// noDetectionsFile skips the detections_<ts>.json artifact.
+	noDetectionsFile bool
+	// perTargetOutput also writes <outputDir>/<host>/detections.json for each target.
+	perTargetOutput bool
+	// autoBaseline diffs each run against the previous run's detections artifacts in the output directory.
+	autoBaseline bool
+	// continueOnWPProbeError turns a failed wpprobe run into an event so detectors still run.
+	continueOnWPProbeError bool
+	// embedWPProbe adds a digest of wpprobe's JSON output to the summary.
+	embedWPProbe bool
+	// wpprobeInput is an existing wpprobe JSON artifact used instead of running wpprobe.
+	wpprobeInput string
+	// explain asks detectors to record the evidence behind each finding.
+	explain bool
+	// statusWarn lists homepage status ranges the status detector flags as warnings.
+	statusWarn []string
+	// homePath is the entry path the version detector fetches on each target.
+	homePath string
+	// versionPattern replaces the version detector's generator regex.
+	versionPattern string
+	// minWPVersion flags older detected WordPress versions at outdatedSeverity.
+	minWPVersion     string
+	outdatedSeverity string
+	// headFirst makes the version detector skip non-HTML homepages after a HEAD.
+	headFirst bool
+	// streamTo is a file or named pipe receiving detection results as NDJSON.
+	streamTo string
+	// signKey is a PEM ed25519 private key used to sign every written artifact.
+	signKey string
+	// encryptKey is an AES key file; every artifact is encrypted to <artifact>.enc with it.
+	encryptKey string
+	// requestIDHeader names a header carrying the run ID on every detector request.
+	requestIDHeader string
+	// externalDetectors are "name:/path/to/cmd" specs added to the detector set.
+	externalDetectors []string
+	// externalTimeout bounds each external detector command.
+	externalTimeout time.Duration
+	// ascii disables colors in the end-of-scan console summary.
+	ascii bool
+	// onlyFindings drops results below findingsMinSeverity before artifacts are written.
+	onlyFindings        bool
+	findingsMinSeverity string
+	// csvCRLF ends CSV artifact records with \r\n for Windows consumers.
+	csvCRLF bool
+	// csvLayout selects the long or wide (pivoted per target) CSV detections artifact.
+	csvLayout string
+	// convertWorkers bounds how many detection format conversions run at once (0 = one per format).
+	convertWorkers int
+	// watch re-runs the scan on this interval until interrupted (0 scans once);
+	// watchCycles stops after that many cycles when positive.
+	watch       time.Duration
+	watchCycles int
+}
+
+// output returns the rendering options selected by scan flags.
+func (o scanOptions) output() outputOptions {
+	return outputOptions{CSVCRLF: o.csvCRLF, CSVLayout: o.csvLayout}
+}
+
+// scanRun carries the state shared by every batch of a single scan invocation.
+type scanRun struct {
+	cmd       *cobra.Command
+	cfg       config.RuntimeConfig
+	opts      *scanOptions
+	emitter   *events.Emitter
+	runner    wpprobe.Runner
+	client    *http.Client
+	detectors []detector.Detector
+	// detectorOpts are the options detectors were built with; --confirm-wordpress
+	// reuses their home path and version pattern.
+	detectorOpts detector.DetectorOptions
+	// targetDetectors holds the detectors of targets that name their own set in
+	// --targets-jsonl, keyed by target; targetDetectorNames holds the resolved names.
+	targetDetectors     map[string][]detector.Detector
+	targetDetectorNames map[string][]string
+	vulnDB              *vuln.DB
+	findings            *findingsdb.DB
+	agg                 *scanAggregator
+	meta                scanMeta
+	timestamp           string
+	// signKey, when set, signs every artifact and summary file written by the run.
+	signKey ed25519.PrivateKey
+	// encryptKey, when set, AES-GCM-encrypts every artifact and summary file at rest.
+	encryptKey []byte
+	// stream, when set, receives every detection result as one JSON line.
+	stream *json.Encoder
+	// wpprobeInput is the digest of --wpprobe-input; when set wpprobe is never run.
+	wpprobeInput *wpprobe.Digest
+}
+
+// scanBatchIndex links a batch to the artifacts it produced.
+type scanBatchIndex struct {
+	Batch     int      `json:"batch"`
+	Targets   []string `json:"targets"`
+	Artifacts []string `json:"artifacts"`
+}
+
 func newScanCmd(loader *config.Loader) *cobra.Command {
 	flags := &runtimeFlagSet{}
+	opts := &scanOptions{}
 
 	cmd := &cobra.Command{
 		Use:   "scan",
 		Short: "Run wpprobe plus configured detectors against WordPress targets",
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
+			startedAt := time.Now()
 			overrides := flags.toOverrides(cmd)
 			cfg, err := loader.Load(overrides)
 			if err != nil {
@@ -33,142 +183,1047 @@ func newScanCmd(loader *config.Loader) *cobra.Command {
 				return err
 			}
 
-			if err := ensureOutputDir(cfg.OutputDir); err != nil {
-				return err
+			for _, path := range cfg.SummaryFiles {
+				if _, err := summaryFormatFor(path); err != nil {
+					return err
+				}
+			}
+
+			// Interrupts cancel the scan context so deferred cleanup (such as removing the
+			// targets temp file) runs instead of the process dying mid-batch.
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			cmd.SetContext(ctx)
+
+			if opts.timeout < 0 {
+				return fmt.Errorf("--timeout must not be negative (got %s)", opts.timeout)
+			}
+			if opts.watch < 0 {
+				return fmt.Errorf("--watch must not be negative (got %s)", opts.watch)
+			}
+			if opts.perTargetTimeout < 0 {
+				return fmt.Errorf("--per-target-timeout must not be negative (got %s)", opts.perTargetTimeout)
+			}
+			if opts.externalTimeout < 0 {
+				return fmt.Errorf("--external-detector-timeout must not be negative (got %s)", opts.externalTimeout)
+			}
+			if opts.watchCycles < 0 {
+				return fmt.Errorf("--watch-cycles must not be negative (got %d)", opts.watchCycles)
+			}
+			// In watch mode the timeout bounds each cycle instead of the whole run.
+			if opts.timeout > 0 && opts.watch == 0 {
+				ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+				defer cancel()
+				cmd.SetContext(ctx)
 			}
 
-			targetsFile, err := writeTargetsTempFile(cfg.Targets)
+			if opts.requestIDHeader != "" && !validHeaderName(opts.requestIDHeader) {
+				return fmt.Errorf("invalid --request-id-header %q", opts.requestIDHeader)
+			}
+
+			if opts.minWPVersion != "" && !minVersionPattern.MatchString(opts.minWPVersion) {
+				return fmt.Errorf("invalid --min-wp-version %q (expected a version such as 6.4 or 6.4.2)", opts.minWPVersion)
+			}
+			if opts.outdatedSeverity != "warning" && opts.outdatedSeverity != "critical" {
+				return fmt.Errorf("unsupported --outdated-severity value %q (supported: warning, critical)", opts.outdatedSeverity)
+			}
+
+			var versionPattern *regexp.Regexp
+			if opts.versionPattern != "" {
+				if versionPattern, err = detector.ParseVersionPattern(opts.versionPattern); err != nil {
+					return fmt.Errorf("invalid --version-pattern: %w", err)
+				}
+			}
+
+			statusWarn, err := detector.ParseStatusRanges(opts.statusWarn)
 			if err != nil {
-				return err
+				return fmt.Errorf("invalid --status-warn: %w", err)
 			}
-			defer os.Remove(targetsFile)
 
-			emitter := events.NewEmitter(cmd.OutOrStdout())
-			if err := emitter.Emit(events.Event{Type: "scan-start", Message: "Starting scan", Fields: map[string]interface{}{"targets": len(cfg.Targets), "mode": cfg.Mode, "dryRun": cfg.DryRun}}); err != nil {
-				return err
+			if opts.groupBy != "" && opts.groupBy != "target" {
+				return fmt.Errorf("unsupported --group-by value %q (supported: target)", opts.groupBy)
 			}
 
-			runner := wpprobe.NewRunner()
-			if !cfg.DryRun {
-				if err := runner.EnsureBinary(); err != nil {
+			if opts.dedupFindings {
+				if err := detector.ValidateDedupKey(opts.dedupKey); err != nil {
 					return err
 				}
 			}
 
-			timestamp := time.Now().UTC().Format("20060102_150405")
-			var outputs []string
-			var detectionResults []detector.Result
+			registry, detectorNames, err := detectorRegistry(cfg.Detectors, opts.externalDetectors)
+			if err != nil {
+				return err
+			}
+			detectorNames, disabledDetectors := detector.RemoveDisabled(detectorNames, cfg.DisabledDetectors)
+			// Build the detector set once up front, even for dry runs, so a typo in
+			// --detectors fails before any wpprobe work instead of passing silently.
+			if _, err := registry.BuildDetectors(detectorNames, detector.DetectorOptions{}); err != nil {
+				return err
+			}
+			targetDetectorNames, err := resolveTargetDetectors(cfg, opts.externalDetectors)
+			if err != nil {
+				return err
+			}
 
-			for _, format := range cfg.Formats {
-				format = strings.ToLower(strings.TrimSpace(format))
-				if format == "" {
-					continue
+			if opts.onlyFindings {
+				if err := detector.ValidateSeverity(opts.findingsMinSeverity); err != nil {
+					return fmt.Errorf("invalid --findings-min-severity: %w", err)
 				}
+			}
 
-				outputPath := filepath.Join(cfg.OutputDir, fmt.Sprintf("scan_%s.%s", timestamp, format))
-				if cfg.DryRun {
-					if err := writePlaceholderArtifact(outputPath, format, cfg.Targets); err != nil {
-						return err
-					}
-				} else {
-					if err := runner.Scan(cmd.Context(), wpprobe.ScanInput{
-						TargetsFile: targetsFile,
-						Mode:        cfg.Mode,
-						Threads:     cfg.Threads,
-						OutputPath:  outputPath,
-						Stdout:      cmd.ErrOrStderr(),
-						Stderr:      cmd.ErrOrStderr(),
-					}); err != nil {
-						return err
-					}
+			if opts.csvLayout != csvLayoutLong && opts.csvLayout != csvLayoutWide {
+				return fmt.Errorf("unsupported --csv-layout value %q (supported: %s, %s)", opts.csvLayout, csvLayoutLong, csvLayoutWide)
+			}
+
+			if opts.convertWorkers < 0 {
+				return fmt.Errorf("--convert-workers must not be negative (got %d)", opts.convertWorkers)
+			}
+
+			if opts.batchSize < 0 {
+				return fmt.Errorf("--batch-size must not be negative (got %d)", opts.batchSize)
+			}
+
+			// The auto baseline reads plaintext detections artifacts from earlier runs,
+			// which these flags stop the scan from writing.
+			if opts.autoBaseline && opts.noDetectionsFile {
+				return fmt.Errorf("--auto-baseline cannot be combined with --no-detections-file")
+			}
+			if opts.autoBaseline && opts.encryptKey != "" {
+				return fmt.Errorf("--auto-baseline cannot be combined with --encrypt-key")
+			}
+
+			var signKey ed25519.PrivateKey
+			if opts.signKey != "" {
+				signKey, err = loadSigningKey(opts.signKey)
+				if err != nil {
+					return err
 				}
+			}
 
-				outputs = append(outputs, outputPath)
-				if err := emitter.Emit(events.Event{Type: "artifact-written", Fields: map[string]interface{}{"path": outputPath, "format": format}}); err != nil {
+			var encryptKey []byte
+			if opts.encryptKey != "" {
+				encryptKey, err = loadEncryptionKey(opts.encryptKey)
+				if err != nil {
 					return err
 				}
 			}
 
-			if !cfg.DryRun {
-				dets, err := detector.DefaultRegistry.BuildDetectors(cfg.Detectors)
+			if err := ensureOutputDir(cfg.OutputDir); err != nil {
+				return err
+			}
+
+			var vulnDB *vuln.DB
+			if opts.vulnFeed != "" {
+				vulnDB, err = vuln.Load(opts.vulnFeed)
 				if err != nil {
 					return err
 				}
+			}
 
-				if len(dets) > 0 {
-					detectionResults, err = detector.Run(cmd.Context(), dets, cfg.Targets)
-					if err != nil {
-						return err
-					}
+			var findings *findingsdb.DB
+			if opts.dbPath != "" {
+				if err := ensureWithinSandbox(cfg, "--db", opts.dbPath); err != nil {
+					return err
+				}
+				findings, err = findingsdb.Open(opts.dbPath)
+				if err != nil {
+					return err
+				}
+				defer findings.Close()
+			}
 
-					detectionsPath := filepath.Join(cfg.OutputDir, fmt.Sprintf("detections_%s.json", timestamp))
-					if err := writeDetectionsArtifact(detectionsPath, detectionResults); err != nil {
-						return err
+			eventsOut := cmd.OutOrStdout()
+			var ui *tui.Session
+			if opts.interactive {
+				ctx, cancel := context.WithCancel(cmd.Context())
+				defer cancel()
+				cmd.SetContext(ctx)
+
+				ui = tui.Start(cfg.Targets, cmd.InOrStdin(), cmd.OutOrStdout(), cancel)
+				defer func() {
+					if err := ui.Finish(runErr); runErr == nil {
+						runErr = err
 					}
+				}()
+				// The UI owns the terminal; NDJSON is only forwarded to the UI and other sinks.
+				eventsOut = io.Discard
+			}
 
-					outputs = append(outputs, detectionsPath)
-					if err := emitter.Emit(events.Event{Type: "artifact-written", Fields: map[string]interface{}{"path": detectionsPath, "format": "detections"}}); err != nil {
+			emitter := events.NewEmitter(eventsOut)
+			if ui != nil {
+				emitter.AddSink(ui)
+			}
+			if opts.syslog || cmd.Flags().Changed("syslog-addr") {
+				sink, err := events.NewSyslogSink(opts.syslogAddr)
+				if err != nil {
+					// Syslog is a secondary sink; keep scanning with NDJSON output only.
+					if err := emitter.Emit(events.Event{Type: "syslog-unavailable", Message: err.Error()}); err != nil {
 						return err
 					}
+				} else {
+					defer sink.Close()
+					emitter.AddSink(sink)
+				}
+			}
+			if opts.otlpEndpoint != "" {
+				provider, err := newTracerProvider(cmd.Context(), opts.otlpEndpoint)
+				if err != nil {
+					return err
+				}
+				defer shutdownTracerProvider(provider)
 
-					for _, res := range detectionResults {
-						if err := emitter.Emit(events.Event{
-							Type:    "detection",
-							Message: res.Summary,
-							Fields: map[string]interface{}{
-								"target":     res.Target,
-								"detector":   res.Detector,
-								"severity":   res.Severity,
-								"confidence": res.Confidence,
-							},
-						}); err != nil {
-							return err
-						}
-					}
+				tracer := provider.Tracer(tracerName)
+				ctx, span := tracer.Start(cmd.Context(), "scan")
+				defer span.End()
+				cmd.SetContext(detector.WithTracer(ctx, tracer))
+				emitter.AddSink(events.NewSpanSink(span))
+			}
+			if opts.perTargetTimeout > 0 {
+				cmd.SetContext(detector.WithTargetTimeout(cmd.Context(), opts.perTargetTimeout))
+			}
+
+			if removed, err := cleanStaleTargetFiles(os.TempDir(), time.Now(), staleTempAge); err == nil && len(removed) > 0 {
+				if err := emitter.Emit(events.Event{Type: "temp-cleanup", Message: "Removed stale targets files from earlier scans", Fields: map[string]interface{}{"paths": removed}}); err != nil {
+					return err
+				}
+			}
+
+			for _, name := range disabledDetectors {
+				if err := emitter.Emit(events.Event{Type: "detector-disabled", Message: "Detector disabled by policy", Fields: map[string]interface{}{"detector": name}}); err != nil {
+					return err
+				}
+			}
+
+			if err := emitter.Emit(events.Event{Type: "scan-start", Message: "Starting scan", Fields: map[string]interface{}{"targets": len(cfg.Targets), "mode": cfg.Mode, "dryRun": cfg.DryRun}}); err != nil {
+				return err
+			}
+
+			var wpprobeInput *wpprobe.Digest
+			if opts.wpprobeInput != "" {
+				digest, err := loadWPProbeInput(opts.wpprobeInput)
+				if err != nil {
+					return err
 				}
-			} else if len(cfg.Detectors) > 0 {
-				if err := emitter.Emit(events.Event{Type: "detectors-skipped", Message: "Detectors require live targets; skipped due to --dry-run"}); err != nil {
+				wpprobeInput = &digest
+				if err := emitter.Emit(events.Event{Type: "wpprobe-input", Message: "Using existing wpprobe results", Fields: map[string]interface{}{"path": opts.wpprobeInput, "targets": digest.Targets}}); err != nil {
 					return err
 				}
 			}
 
-			if cfg.SummaryFile != "" {
-				if err := writeSummary(cfg.SummaryFile, cfg, outputs, detectionResults); err != nil {
+			runner := newWPProbeRunner()
+			if !cfg.DryRun && wpprobeInput == nil {
+				if err := runner.EnsureBinary(); err != nil {
+					return err
+				}
+			}
+
+			run := &scanRun{
+				cmd:          cmd,
+				cfg:          cfg,
+				opts:         opts,
+				emitter:      emitter,
+				runner:       runner,
+				vulnDB:       vulnDB,
+				findings:     findings,
+				wpprobeInput: wpprobeInput,
+				agg:          newScanAggregator(),
+				meta:         newScanMeta(os.Args[1:], startedAt),
+				timestamp:    startedAt.UTC().Format("20060102_150405"),
+				signKey:      signKey,
+				encryptKey:   encryptKey,
+			}
+			if signKey != nil {
+				run.meta.SigningKey = publicKeyFingerprint(signKey.Public().(ed25519.PublicKey))
+			}
+
+			if opts.streamTo != "" {
+				if err := ensureWithinSandbox(cfg, "--stream-to", opts.streamTo); err != nil {
 					return err
 				}
+				stream, err := openDetectionStream(cmd.Context(), opts.streamTo)
+				if err != nil {
+					return err
+				}
+				defer stream.Close()
+				run.stream = json.NewEncoder(stream)
 			}
 
-			return emitter.Emit(events.Event{Type: "scan-finished", Message: "Scan complete", Fields: map[string]interface{}{"artifacts": len(outputs)}})
+			if !cfg.DryRun {
+				client, closeClient, err := buildDetectorClient(cmd.Context(), cfg)
+				if err != nil {
+					return err
+				}
+				defer closeClient()
+				if opts.requestIDHeader != "" {
+					withRequestHeader(client, opts.requestIDHeader, func() string { return run.meta.RunID })
+				}
+				run.client = client
+
+				detOpts := detectorOptions(cfg)
+				detOpts.Client = client
+				detOpts.Explain = opts.explain
+				detOpts.StatusWarn = statusWarn
+				detOpts.HomePath = opts.homePath
+				detOpts.VersionPattern = versionPattern
+				detOpts.MinVersion = opts.minWPVersion
+				detOpts.OutdatedSeverity = opts.outdatedSeverity
+				detOpts.HeadFirst = opts.headFirst
+				detOpts.ExternalTimeout = opts.externalTimeout
+
+				run.detectorOpts = detOpts
+				run.detectors, err = registry.BuildDetectors(detectorNames, detOpts)
+				if err != nil {
+					return err
+				}
+
+				// Targets sharing a detector list share one built set.
+				built := map[string][]detector.Detector{}
+				run.targetDetectors = map[string][]detector.Detector{}
+				run.targetDetectorNames = targetDetectorNames
+				for target, names := range targetDetectorNames {
+					key := strings.Join(names, ",")
+					dets, ok := built[key]
+					if !ok {
+						if dets, err = registry.BuildDetectors(names, detOpts); err != nil {
+							return err
+						}
+						built[key] = dets
+					}
+					run.targetDetectors[target] = dets
+				}
+			}
+
+			if opts.watch <= 0 {
+				return run.runCycle(detectorNames)
+			}
+			return run.watch(detectorNames, startedAt)
 		},
 	}
 
 	bindRuntimeFlags(cmd, flags)
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", defaultScanTimeout, "Abort the scan after this duration (0 disables the limit)")
+	cmd.Flags().DurationVar(&opts.perTargetTimeout, "per-target-timeout", 0, "Give all detectors of one target at most this long (e.g. 45s); detectors still pending are skipped with target-timeout results (0 disables the limit)")
+	cmd.Flags().DurationVar(&opts.watch, "watch", 0, "Re-run the scan every interval (e.g. 15m) until interrupted, writing artifacts per cycle")
+	cmd.Flags().IntVar(&opts.watchCycles, "watch-cycles", 0, "Stop --watch after this many cycles (0 runs until interrupted)")
+	cmd.Flags().StringVar(&opts.groupBy, "group-by", "", "Group the detections artifact by key instead of a flat array (target)")
+	cmd.Flags().IntVar(&opts.batchSize, "batch-size", 0, "Scan targets in batches of N, writing separate artifacts per batch (0 disables batching)")
+	cmd.Flags().BoolVar(&opts.perTargetOutput, "per-target-output", false, "Also write each target's results to <output-dir>/<host>/detections_<timestamp>.json, indexed under targetArtifacts in the summary")
+	cmd.Flags().BoolVar(&opts.noDetectionsFile, "no-detections-file", false, "Do not write detections_<timestamp>.json; detection events, the summary, and other --formats renderings still carry the results")
+	cmd.Flags().BoolVar(&opts.autoBaseline, "auto-baseline", false, "Diff findings against the previous run's detections_*.json files (every batch) in the output directory, emitting new-finding and baseline-diff events")
+	cmd.Flags().BoolVar(&opts.continueOnWPProbeError, "continue-on-wpprobe-error", false, "Report a failed wpprobe run as a wpprobe-failed event and still run detectors and write their artifacts")
+	cmd.Flags().BoolVar(&opts.confirmWordPress, "confirm-wordpress", false, "Check each target for WordPress first and skip wpprobe for targets that are not confirmed")
+	cmd.Flags().StringArrayVar(&opts.externalDetectors, "external-detector", nil, "Run a command as a detector, given as name:/path/to/cmd (repeatable); it receives the target URL and prints a result JSON object")
+	cmd.Flags().DurationVar(&opts.externalTimeout, "external-detector-timeout", detector.DefaultExternalTimeout, "Kill an --external-detector command that runs longer than this for one target (0 uses the default)")
+	cmd.Flags().StringSliceVar(&opts.statusWarn, "status-warn", nil, "Homepage HTTP status codes or ranges the status detector reports as warnings (repeatable, e.g. 500-599 or 404; default 500-599)")
+	cmd.Flags().StringVar(&opts.homePath, "home-path", detector.DefaultHomePath, "Path the version detector fetches as the homepage (e.g. /index.php or /blog/)")
+	cmd.Flags().StringVar(&opts.versionPattern, "version-pattern", "", "Regex replacing the generator version match for localized installs; its first capture group is the version")
+	cmd.Flags().StringVar(&opts.minWPVersion, "min-wp-version", "", "Report WordPress versions below this one (e.g. 6.4) as outdated instead of info")
+	cmd.Flags().BoolVar(&opts.headFirst, "head-first", false, "Send a HEAD before the version detector's homepage GET and skip targets that do not serve HTML (e.g. JSON APIs)")
+	cmd.Flags().StringVar(&opts.outdatedSeverity, "outdated-severity", detector.DefaultOutdatedSeverity, "Severity for versions below --min-wp-version: warning or critical")
+	cmd.Flags().StringVar(&opts.streamTo, "stream-to", "", "Write each detection result as an NDJSON line to this file or named pipe (a FIFO waits for its reader, up to --timeout)")
+	cmd.Flags().StringVar(&opts.signKey, "sign-key", "", "Sign every artifact with this PEM ed25519 private key, writing <artifact>.sig alongside")
+	cmd.Flags().StringVar(&opts.encryptKey, "encrypt-key", "", "AES-GCM-encrypt every artifact with this key file (16, 24, or 32 bytes, raw or hex), writing <artifact>.enc and removing the plaintext")
+	cmd.Flags().StringVar(&opts.requestIDHeader, "request-id-header", "", "Send the scan's run ID in this header (e.g. X-Scan-ID) on every detector request")
+	cmd.Flags().BoolVar(&opts.explain, "explain", false, "Describe the evidence behind each finding in metadata.explanation")
+	cmd.Flags().BoolVar(&opts.dedupFindings, "dedup-findings", false, "Collapse duplicate findings within a run, keeping the highest confidence")
+	cmd.Flags().StringSliceVar(&opts.dedupKey, "dedup-key", detector.DefaultDedupKey, "Result fields identifying duplicates for --dedup-findings (target, detector, severity, summary)")
+	cmd.Flags().BoolVar(&opts.onlyFindings, "only-findings", false, "Drop informational and clean results, keeping only findings at or above --findings-min-severity")
+	cmd.Flags().StringVar(&opts.findingsMinSeverity, "findings-min-severity", "warning", "Lowest severity kept by --only-findings (low, medium, warning, high, critical)")
+	cmd.Flags().BoolVar(&opts.embedWPProbe, "embed-wpprobe", false, "Embed a digest of wpprobe's JSON output (counts, top vulnerabilities) in summary files; requires the json format")
+	cmd.Flags().StringVar(&opts.wpprobeInput, "wpprobe-input", "", "Skip running wpprobe and embed the results from this existing wpprobe JSON artifact in summary files; detectors still run")
+	cmd.Flags().BoolVar(&opts.csvCRLF, "csv-crlf", false, "End CSV artifact lines with CRLF (\\r\\n) instead of LF")
+	cmd.Flags().StringVar(&opts.csvLayout, "csv-layout", csvLayoutLong, "CSV detections layout: long (one row per finding) or wide (one row per target, a severity/summary column pair per detector)")
+	cmd.Flags().IntVar(&opts.convertWorkers, "convert-workers", 0, "Render detection formats (yaml, csv, html, sarif) with up to N workers in parallel (0 uses one per format)")
+	cmd.Flags().StringVar(&opts.dbPath, "db", "", "Also insert findings into this SQLite database (created if absent)")
+	cmd.Flags().StringVar(&opts.vulnFeed, "vuln-feed", "", "JSON vulnerability feed used to annotate detected versions with CVEs")
+	cmd.Flags().BoolVar(&opts.ascii, "ascii", false, "Print the end-of-scan severity summary without colors (also enabled by NO_COLOR)")
+	cmd.Flags().BoolVar(&opts.interactive, "interactive", false, "Show targets and findings in a terminal UI instead of NDJSON on stdout")
+	cmd.Flags().BoolVar(&opts.syslog, "syslog", false, "Also send events to syslog")
+	cmd.Flags().StringVar(&opts.otlpEndpoint, "otlp-endpoint", "", "Export a trace of the scan (a span per target, events as span events) to this OTLP/HTTP endpoint, e.g. http://localhost:4318")
+	cmd.Flags().StringVar(&opts.syslogAddr, "syslog-addr", "", "Syslog address as network://address, e.g. udp://logs:514 (default "+events.DefaultSyslogAddr+"; implies --syslog)")
 
 	return cmd
 }
 
-func writeTargetsTempFile(targets []string) (string, error) {
-	file, err := os.CreateTemp("", "wphunter-targets-*.txt")
+// watch runs scan cycles every r.opts.watch until the command context is cancelled
+// (SIGINT/SIGTERM) or watchCycles cycles have run. An interrupt between cycles ends
+// the watch cleanly; one that cuts a cycle short is returned as an error, since that
+// cycle's artifacts may be partial. Each cycle gets a fresh run ID, fresh totals, and
+// artifact names carrying its start time and cycle number.
+func (r *scanRun) watch(detectorNames []string, startedAt time.Time) error {
+	base := r.cmd.Context()
+	defer r.cmd.SetContext(base)
+
+	for cycle := 1; ; cycle++ {
+		if cycle > 1 {
+			startedAt = time.Now()
+			r.agg = newScanAggregator()
+			r.meta.RunID = newRunID(startedAt)
+			r.meta.StartedAt = startedAt.UTC().Format(time.RFC3339)
+		}
+		r.timestamp = fmt.Sprintf("%s_cycle%d", startedAt.UTC().Format("20060102_150405"), cycle)
+
+		if err := r.emitter.Emit(events.Event{Type: "scan-cycle", Message: "Starting scan cycle", Fields: map[string]interface{}{"cycle": cycle, "interval": r.opts.watch.String()}}); err != nil {
+			return err
+		}
+
+		ctx, cancel := base, context.CancelFunc(func() {})
+		if r.opts.timeout > 0 {
+			ctx, cancel = context.WithTimeout(base, r.opts.timeout)
+		}
+		r.cmd.SetContext(ctx)
+		err := r.runCycle(detectorNames)
+		cancel()
+		r.cmd.SetContext(base)
+		// Detectors record cancellation as error results, so a cycle cut short by an
+		// interrupt can finish without an error of its own.
+		if base.Err() != nil {
+			return fmt.Errorf("watch interrupted during cycle %d: %w", cycle, base.Err())
+		}
+		if err != nil {
+			return err
+		}
+
+		if r.opts.watchCycles > 0 && cycle >= r.opts.watchCycles {
+			return nil
+		}
+
+		timer := time.NewTimer(r.opts.watch)
+		select {
+		case <-base.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// runCycle scans every batch once, then writes the index, summaries, and the
+// console summary for the artifacts recorded since the aggregator was reset.
+func (r *scanRun) runCycle(detectorNames []string) error {
+	var index []scanBatchIndex
+
+	// Existing wpprobe results stand in for the wpprobe runs this cycle skips.
+	if r.wpprobeInput != nil {
+		r.agg.AddWPProbeDigest(*r.wpprobeInput)
+	}
+
+	// The baseline is picked before this cycle writes its own detections artifacts.
+	var baselinePaths []string
+	var baseline []detector.Result
+	if r.opts.autoBaseline && !r.cfg.DryRun && len(r.detectors) > 0 {
+		paths, err := latestDetectionsArtifacts(r.cfg.OutputDir)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			results, err := loadDetectionsArtifact(path)
+			if err != nil {
+				return fmt.Errorf("load auto baseline: %w", err)
+			}
+			baseline = append(baseline, results...)
+		}
+		if len(paths) > 0 {
+			baselinePaths = paths
+			fields := map[string]interface{}{"path": paths[len(paths)-1], "paths": paths, "findings": len(baseline)}
+			if err := r.emitter.Emit(events.Event{Type: "baseline-selected", Fields: fields}); err != nil {
+				return err
+			}
+		}
+	}
+
+	batches := batchTargets(r.cfg.Targets, r.opts.batchSize)
+	for i, targets := range batches {
+		suffix := ""
+		if r.opts.batchSize > 0 {
+			suffix = fmt.Sprintf("_batch%d", i+1)
+		}
+
+		batchOutputs, err := r.runBatch(targets, suffix)
+		if err != nil {
+			return err
+		}
+
+		index = append(index, scanBatchIndex{Batch: i + 1, Targets: targets, Artifacts: batchOutputs})
+	}
+
+	if r.cfg.DryRun && len(detectorNames) > 0 {
+		if err := r.emitter.Emit(events.Event{Type: "detectors-skipped", Message: "Detectors require live targets; skipped due to --dry-run"}); err != nil {
+			return err
+		}
+	}
+
+	if r.opts.batchSize > 0 {
+		indexPath := filepath.Join(r.cfg.OutputDir, fmt.Sprintf("index_%s.json", r.timestamp))
+		if err := writeBatchIndex(indexPath, index); err != nil {
+			return err
+		}
+
+		if _, err := r.recordArtifact(indexPath, "index"); err != nil {
+			return err
+		}
+	}
+
+	totals := r.agg.Snapshot()
+	if len(baselinePaths) > 0 {
+		if err := r.reportBaselineDiff(baselinePaths, baseline, totals.Detections); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range r.cfg.SummaryFiles {
+		if err := writeSummary(path, r.cfg, totals, r.meta); err != nil {
+			return err
+		}
+		if err := r.signArtifact(path); err != nil {
+			return err
+		}
+		if _, err := r.encryptArtifact(path); err != nil {
+			return err
+		}
+	}
+
+	if len(r.detectors) > 0 {
+		printSeveritySummary(r.cmd.ErrOrStderr(), totals.Severities, r.opts.ascii)
+	}
+
+	return r.emitter.Emit(events.Event{Type: "scan-finished", Message: "Scan complete", Fields: finishedFields(totals)})
+}
+
+// runBatch runs wpprobe and detectors for one group of targets, recording artifacts and
+// detections in the run aggregator. Artifact names carry suffix so batches never
+// overwrite each other. It returns the artifacts written for this batch.
+func (r *scanRun) runBatch(targets []string, suffix string) ([]string, error) {
+	cfg := r.cfg
+	ctx := r.cmd.Context()
+
+	scanTargets := targets
+	if r.opts.confirmWordPress && !cfg.DryRun {
+		confirmed, err := r.confirmWordPress(ctx, targets)
+		if err != nil {
+			return nil, err
+		}
+		scanTargets = confirmed
+	}
+
+	groups, err := r.wpprobeGroups(ctx, scanTargets)
+	defer func() {
+		for _, group := range groups {
+			os.Remove(group.targetsFile)
+		}
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []string
+	for _, format := range cfg.Formats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format == "" {
+			continue
+		}
+
+		outputPath := filepath.Join(cfg.OutputDir, fmt.Sprintf("scan_%s%s.%s", r.timestamp, suffix, format))
+		if cfg.DryRun {
+			if err := writePlaceholderArtifact(outputPath, format, targets, r.opts.output()); err != nil {
+				return nil, err
+			}
+			recorded, err := r.recordArtifact(outputPath, format)
+			if err != nil {
+				return nil, err
+			}
+			outputs = append(outputs, recorded)
+			continue
+		}
+		if _, native := wpprobeFormats[format]; !native {
+			// wpprobe cannot produce this format; it is rendered from detector results below.
+			continue
+		}
+		if r.wpprobeInput != nil {
+			// --wpprobe-input already supplies wpprobe's results.
+			continue
+		}
+
+		// No groups means every target in this batch was skipped by --confirm-wordpress.
+		for _, group := range groups {
+			groupPath := outputPath
+			if len(groups) > 1 {
+				groupPath = filepath.Join(cfg.OutputDir, fmt.Sprintf("scan_%s%s_%s.%s", r.timestamp, suffix, group.mode, format))
+			}
+			written, err := r.runWPProbe(ctx, format, group, groupPath)
+			if err != nil {
+				return nil, err
+			}
+			if !written {
+				continue
+			}
+			recorded, err := r.recordArtifact(groupPath, format)
+			if err != nil {
+				return nil, err
+			}
+			outputs = append(outputs, recorded)
+		}
+	}
+
+	sets := r.detectorSets(targets)
+	if len(sets) == 0 {
+		return outputs, nil
+	}
+
+	var detectionResults []detector.Result
+	for _, set := range sets {
+		results, err := detector.Run(ctx, set.detectors, set.targets)
+		if err != nil {
+			return nil, err
+		}
+		detectionResults = append(detectionResults, results...)
+	}
+	if r.vulnDB != nil {
+		detectionResults = r.vulnDB.Enrich(detectionResults)
+	}
+	detectionResults = append(detectionResults, detector.CheckExpectedVersions(detectionResults, cfg.ExpectedVersions)...)
+	if r.opts.dedupFindings {
+		detectionResults = detector.Dedup(detectionResults, r.opts.dedupKey)
+	}
+	if r.opts.onlyFindings {
+		detectionResults = detector.FilterFindings(detectionResults, r.opts.findingsMinSeverity)
+	}
+	r.agg.AddDetections(detectionResults...)
+	if r.findings != nil {
+		if err := r.findings.Insert(ctx, r.meta.RunID, detectionResults); err != nil {
+			return nil, fmt.Errorf("export findings to %s: %w", r.opts.dbPath, err)
+		}
+	}
+
+	// Results still reach events, the summary, and other formats without the JSON artifact.
+	if !r.opts.noDetectionsFile {
+		detectionsPath := filepath.Join(cfg.OutputDir, fmt.Sprintf("detections_%s%s.json", r.timestamp, suffix))
+		writeDetections := writeDetectionsArtifact
+		if r.opts.groupBy == "target" {
+			writeDetections = writeGroupedDetectionsArtifact
+		}
+		if err := writeDetections(detectionsPath, detectionResults); err != nil {
+			return nil, err
+		}
+
+		recorded, err := r.recordArtifact(detectionsPath, "detections")
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, recorded)
+	}
+
+	if r.opts.perTargetOutput {
+		written, err := r.writePerTargetArtifacts(detectionResults, suffix)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, written...)
+	}
+
+	// Every requested format is also rendered from the canonical results, so formats
+	// wpprobe cannot produce (yaml, html, sarif) still get a detections artifact.
+	converted, err := convertDetections(cfg.OutputDir, fmt.Sprintf("detections_%s%s", r.timestamp, suffix), cfg.Formats, detectionResults, r.opts.convertWorkers, r.opts.output())
+	if err != nil {
+		return nil, err
+	}
+	for _, artifact := range converted {
+		recorded, err := r.recordArtifact(artifact.Path, artifact.Format)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, recorded)
+	}
+
+	for _, res := range detectionResults {
+		if err := r.emitter.Emit(detectionEvent(res)); err != nil {
+			return nil, err
+		}
+		if r.stream != nil {
+			if err := r.stream.Encode(res); err != nil {
+				return nil, fmt.Errorf("stream detections to %s: %w", r.opts.streamTo, err)
+			}
+		}
+	}
+
+	return outputs, nil
+}
+
+// loadWPProbeInput parses an existing wpprobe JSON artifact for --wpprobe-input. Unlike
+// a scan's own output, a missing file is an error since the user named it.
+func loadWPProbeInput(path string) (wpprobe.Digest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return wpprobe.Digest{}, fmt.Errorf("read --wpprobe-input: %w", err)
+	}
+	reports, err := wpprobe.ParseReports(data)
+	if err != nil {
+		return wpprobe.Digest{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return wpprobe.NewDigest(reports), nil
+}
+
+// wpprobeGroup is a set of targets scanned by one wpprobe invocation per format.
+type wpprobeGroup struct {
+	mode        string
+	targets     []string
+	targetsFile string
+}
+
+// wpprobeGroups splits targets by their effective scan mode (a per-target mode or the
+// configured one), in order of first appearance, and writes each group's targets file.
+// Groups are returned even on error so their files can be removed.
+func (r *scanRun) wpprobeGroups(ctx context.Context, targets []string) ([]wpprobeGroup, error) {
+	var groups []wpprobeGroup
+	index := map[string]int{}
+	for _, target := range targets {
+		mode := r.cfg.Mode
+		if override := r.cfg.TargetOptions[target].Mode; override != "" {
+			mode = override
+		}
+		i, ok := index[mode]
+		if !ok {
+			i = len(groups)
+			index[mode] = i
+			groups = append(groups, wpprobeGroup{mode: mode})
+		}
+		groups[i].targets = append(groups[i].targets, target)
+	}
+
+	for i := range groups {
+		path, err := writeTargetsTempFile(ctx, groups[i].targets)
+		if err != nil {
+			return groups, err
+		}
+		groups[i].targetsFile = path
+	}
+	return groups, nil
+}
+
+// runWPProbe runs wpprobe for one group and format, writing outputPath. It reports
+// whether an artifact was produced: a failure tolerated by --continue-on-wpprobe-error
+// leaves none.
+func (r *scanRun) runWPProbe(ctx context.Context, format string, group wpprobeGroup, outputPath string) (bool, error) {
+	var execErr error
+	if err := r.runner.Scan(ctx, wpprobe.ScanInput{
+		TargetsFile: group.targetsFile,
+		Mode:        group.mode,
+		Threads:     r.cfg.Threads,
+		OutputPath:  outputPath,
+		Stdout:      r.cmd.ErrOrStderr(),
+		Stderr:      r.cmd.ErrOrStderr(),
+		OnExec: func(argv []string) {
+			execErr = r.emitter.Emit(events.Event{Type: "wpprobe-exec", Fields: map[string]interface{}{"argv": argv, "format": format}})
+		},
+	}); err != nil {
+		var exitErr *wpprobe.ExitError
+		if errors.As(err, &exitErr) {
+			if err := r.emitter.Emit(events.Event{Type: "wpprobe-exit", Message: exitErr.Error(), Fields: map[string]interface{}{"code": exitErr.Code, "kind": exitErr.Kind, "format": format}}); err != nil {
+				return false, err
+			}
+		}
+		// Exiting with findings still leaves a complete artifact behind. Crashes and CLI
+		// errors can exit 1 too, so without an artifact the exit counts as a failure.
+		findings := exitErr != nil && exitErr.Kind == wpprobe.ExitKindFindings
+		if findings && !nonEmptyFile(outputPath) {
+			findings = false
+			err = fmt.Errorf("%w, but no artifact was written to %s", err, outputPath)
+		}
+		if !findings {
+			if !r.opts.continueOnWPProbeError {
+				return false, err
+			}
+			// The artifact is missing or partial; record the failure and let detectors run.
+			if err := r.emitter.Emit(events.Event{Type: "wpprobe-failed", Message: err.Error(), Fields: map[string]interface{}{"format": format, "path": outputPath}}); err != nil {
+				return false, err
+			}
+			return false, execErr
+		}
+	}
+	if execErr != nil {
+		return false, execErr
+	}
+
+	if r.opts.embedWPProbe && format == "json" {
+		digest, err := wpprobe.DigestFile(outputPath)
+		if err != nil {
+			// The raw artifact is still written; only the summary digest is missing.
+			if err := r.emitter.Emit(events.Event{Type: "wpprobe-digest-failed", Message: err.Error(), Fields: map[string]interface{}{"path": outputPath}}); err != nil {
+				return false, err
+			}
+		} else {
+			r.agg.AddWPProbeDigest(digest)
+		}
+	}
+	return true, nil
+}
+
+// ensureWithinSandbox rejects a path given by flag that resolves outside the
+// configured sandbox root, like the output directory and summary files.
+func ensureWithinSandbox(cfg config.RuntimeConfig, flag, path string) error {
+	if cfg.SandboxRoot == "" {
+		return nil
+	}
+	if err := config.EnsureWithinRoot(cfg.SandboxRoot, path); err != nil {
+		return fmt.Errorf("%s: %w", flag, err)
+	}
+	return nil
+}
+
+// nonEmptyFile reports whether path is a regular file with content.
+func nonEmptyFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Size() > 0
+}
+
+// detectorSet is a group of targets that run the same detectors.
+type detectorSet struct {
+	detectors []detector.Detector
+	targets   []string
+}
+
+// detectorSets groups targets by their detectors (a per-target set or the configured
+// one), in order of first appearance, leaving out targets with no detectors.
+func (r *scanRun) detectorSets(targets []string) []detectorSet {
+	var sets []detectorSet
+	index := map[string]int{}
+	for _, target := range targets {
+		dets, key := r.detectors, ""
+		if override, ok := r.targetDetectors[target]; ok {
+			dets, key = override, "target:"+strings.Join(r.targetDetectorNames[target], ",")
+		}
+		if len(dets) == 0 {
+			continue
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(sets)
+			index[key] = i
+			sets = append(sets, detectorSet{detectors: dets})
+		}
+		sets[i].targets = append(sets[i].targets, target)
+	}
+	return sets
+}
+
+// reportBaselineDiff emits a new-finding event for each finding absent from the
+// baseline, then a baseline-diff event with the added, removed, and changed counts.
+func (r *scanRun) reportBaselineDiff(paths []string, baseline, current []detector.Result) error {
+	diff := diffDetections(baseline, current)
+	for _, res := range diff.Added {
+		event := detectionEvent(res)
+		event.Type = "new-finding"
+		if err := r.emitter.Emit(event); err != nil {
+			return err
+		}
+	}
+	return r.emitter.Emit(events.Event{Type: "baseline-diff", Message: "Compared findings against the previous run", Fields: map[string]interface{}{
+		"baseline": paths[len(paths)-1],
+		"paths":    paths,
+		"added":    len(diff.Added),
+		"removed":  len(diff.Removed),
+		"changed":  len(diff.Changed),
+	}})
+}
+
+// recordArtifact announces a written artifact, signs it when --sign-key is set,
+// encrypts it when --encrypt-key is set, and adds it to the run totals. It returns
+// the path the artifact ends up at.
+func (r *scanRun) recordArtifact(path, format string) (string, error) {
+	if err := r.emitter.Emit(events.Event{Type: "artifact-written", Fields: map[string]interface{}{"path": path, "format": format}}); err != nil {
+		return "", err
+	}
+	if err := r.signArtifact(path); err != nil {
+		return "", err
+	}
+	path, err := r.encryptArtifact(path)
+	if err != nil {
+		return "", err
+	}
+	r.agg.AddArtifact(path)
+	return path, nil
+}
+
+// encryptArtifact replaces path with its encrypted form when the run has an
+// encryption key, returning the resulting path. Signatures cover the plaintext, so
+// they verify against the output of the decrypt command.
+func (r *scanRun) encryptArtifact(path string) (string, error) {
+	if r.encryptKey == nil {
+		return path, nil
+	}
+	encPath, err := encryptArtifact(path, r.encryptKey)
 	if err != nil {
 		return "", err
 	}
+	if err := r.emitter.Emit(events.Event{Type: "artifact-encrypted", Fields: map[string]interface{}{"path": path, "encrypted": encPath}}); err != nil {
+		return "", err
+	}
+	return encPath, nil
+}
+
+// signArtifact writes a detached signature next to path when the run has a signing key.
+func (r *scanRun) signArtifact(path string) error {
+	if r.signKey == nil {
+		return nil
+	}
+	sigPath, err := signArtifact(path, r.signKey)
+	if err != nil {
+		return err
+	}
+	return r.emitter.Emit(events.Event{Type: "artifact-signed", Fields: map[string]interface{}{"path": path, "signature": sigPath}})
+}
+
+// batchTargets splits targets into consecutive groups of size; size <= 0 yields a single group.
+func batchTargets(targets []string, size int) [][]string {
+	if size <= 0 || size >= len(targets) {
+		return [][]string{targets}
+	}
+
+	var batches [][]string
+	for start := 0; start < len(targets); start += size {
+		end := start + size
+		if end > len(targets) {
+			end = len(targets)
+		}
+		batches = append(batches, targets[start:end])
+	}
+	return batches
+}
 
-	if err := writeTargetsToWriter(file, targets); err != nil {
+// detectorOptions maps runtime configuration onto the options shared by detector factories.
+func detectorOptions(cfg config.RuntimeConfig) detector.DetectorOptions {
+	return detector.DetectorOptions{
+		Confidence: cfg.Confidence,
+	}
+}
+
+// resolveTargetDetectors resolves the per-target detector lists from --targets-jsonl the
+// same way as the global list, validating each so a typo fails before any scanning.
+func resolveTargetDetectors(cfg config.RuntimeConfig, externalSpecs []string) (map[string][]string, error) {
+	resolved := map[string][]string{}
+	for target, options := range cfg.TargetOptions {
+		if len(options.Detectors) == 0 {
+			continue
+		}
+		registry, names, err := detectorRegistry(options.Detectors, externalSpecs)
+		if err != nil {
+			return nil, err
+		}
+		names, _ = detector.RemoveDisabled(names, cfg.DisabledDetectors)
+		if _, err := registry.BuildDetectors(names, detector.DetectorOptions{}); err != nil {
+			return nil, fmt.Errorf("target %s: %w", target, err)
+		}
+		resolved[target] = names
+	}
+	return resolved, nil
+}
+
+// detectorRegistry extends the built-in registry with --external-detector commands and
+// returns the detector names to build, with external detectors enabled after the
+// configured ones.
+func detectorRegistry(names, externalSpecs []string) (detector.Registry, []string, error) {
+	if len(externalSpecs) == 0 {
+		return detector.DefaultRegistry, names, nil
+	}
+
+	registry := detector.DefaultRegistry.Clone()
+	enabled := append([]string(nil), names...)
+	for _, spec := range externalSpecs {
+		name, command, err := detector.ParseExternalDetector(spec, registry)
+		if err != nil {
+			return nil, nil, err
+		}
+		registry[name] = func(opts detector.DetectorOptions) detector.Detector {
+			return detector.NewExternalDetector(name, command, opts.ExternalTimeout)
+		}
+		enabled = append(enabled, name)
+	}
+	return registry, enabled, nil
+}
+
+// confirmWordPress returns the targets that look like WordPress sites, emitting a
+// target-confirmed event with the matched marker for each, and a target-skipped event
+// for the rest so wpprobe is only run where it can find something.
+func (r *scanRun) confirmWordPress(ctx context.Context, targets []string) ([]string, error) {
+	var confirmed []string
+	for _, target := range targets {
+		confirmation, err := detector.ConfirmWordPress(ctx, target, r.detectorOpts)
+		if err == nil && confirmation.Confirmed {
+			confirmed = append(confirmed, target)
+			if err := r.emitter.Emit(events.Event{Type: "target-confirmed", Fields: map[string]interface{}{"target": target, "marker": confirmation.Marker}}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		reason := "not confirmed as WordPress"
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			reason = fmt.Sprintf("confirmation failed: %v", err)
+		}
+		if err := r.emitter.Emit(events.Event{Type: "target-skipped", Message: reason, Fields: map[string]interface{}{"target": target, "stage": "wpprobe"}}); err != nil {
+			return nil, err
+		}
+	}
+	return confirmed, nil
+}
+
+// writeTargetsTempFile materializes targets for wpprobe. The partial file is removed
+// if writing fails or ctx is cancelled.
+func writeTargetsTempFile(ctx context.Context, targets []string) (string, error) {
+	file, err := os.CreateTemp("", targetsTempPattern)
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeTargetsToWriter(ctx, file, targets); err != nil {
 		file.Close()
+		os.Remove(file.Name())
 		return "", err
 	}
 
 	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
 		return "", err
 	}
 
 	return file.Name(), nil
 }
 
-// writeTargetsToWriter writes targets to a writer, one per line.
+// cleanStaleTargetFiles removes targets files in dir last modified more than maxAge
+// before now, left behind by scans that crashed or were killed. It returns the
+// removed paths; files that vanish concurrently are ignored.
+func cleanStaleTargetFiles(dir string, now time.Time, maxAge time.Duration) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, targetsTempPattern))
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, path := range matches {
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if now.Sub(info.ModTime()) < maxAge {
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			removed = append(removed, path)
+		}
+	}
+	return removed, nil
+}
+
+// writeTargetsToWriter writes targets to a writer, one per line, stopping early when ctx is cancelled.
 // This is extracted to make the write logic testable.
-func writeTargetsToWriter(w io.Writer, targets []string) error {
+func writeTargetsToWriter(ctx context.Context, w io.Writer, targets []string) error {
 	for _, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if _, err := fmt.Fprintln(w, target); err != nil {
 			return err
 		}
@@ -176,7 +1231,7 @@ func writeTargetsToWriter(w io.Writer, targets []string) error {
 	return nil
 }
 
-func writePlaceholderArtifact(path, format string, targets []string) error {
+func writePlaceholderArtifact(path, format string, targets []string, opts outputOptions) error {
 	if err := ensureOutputDir(filepath.Dir(path)); err != nil {
 		return err
 	}
@@ -194,46 +1249,130 @@ func writePlaceholderArtifact(path, format string, targets []string) error {
 		}
 		return os.WriteFile(path, append(data, '\n'), 0o600)
 	case "csv":
-		lines := []string{"target,status"}
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		w.UseCRLF = opts.CSVCRLF
+		_ = w.Write([]string{"target", "status"})
 		for _, target := range targets {
-			lines = append(lines, fmt.Sprintf("%s,placeholder", target))
+			_ = w.Write([]string{target, "placeholder"})
 		}
-		content := strings.Join(lines, "\n") + "\n"
-		return os.WriteFile(path, []byte(content), 0o600)
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+		return os.WriteFile(path, buf.Bytes(), 0o600)
+	case "yaml":
+		payload := map[string]interface{}{
+			"generatedAt": time.Now().UTC().Format(time.RFC3339),
+			"targets":     targets,
+			"note":        "dry-run placeholder artifact",
+		}
+		data, err := yaml.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, 0o600)
+	case "html", "sarif", "cyclonedx":
+		// Render an empty report so downstream tooling sees a valid file.
+		write, _ := detectionWriter(format, opts)
+		return write(path, nil)
 	default:
 		return fmt.Errorf("unsupported format %s", format)
 	}
 }
 
-func writeSummary(path string, cfg config.RuntimeConfig, artifacts []string, detections []detector.Result) error {
-	summary := map[string]interface{}{
-		"generatedAt": time.Now().UTC().Format(time.RFC3339),
-		"targets":     cfg.Targets,
-		"mode":        cfg.Mode,
-		"artifacts":   artifacts,
-		"dryRun":      cfg.DryRun,
-		"detectors":   cfg.Detectors,
-		"detections":  detections,
+func writeDetectionsArtifact(path string, results []detector.Result) error {
+	if err := ensureOutputDir(filepath.Dir(path)); err != nil {
+		return err
 	}
 
-	data, err := json.MarshalIndent(summary, "", "  ")
+	data, err := json.MarshalIndent(results, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	if err := ensureOutputDir(filepath.Dir(path)); err != nil {
-		return err
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// writePerTargetArtifacts writes each target's results to
+// <outputDir>/<host>/detections_<timestamp><suffix>.json and indexes the files by
+// target. Targets of one batch sharing a host directory share its file; the timestamp
+// and batch suffix keep later cycles and batches from overwriting it.
+func (r *scanRun) writePerTargetArtifacts(results []detector.Result, suffix string) ([]string, error) {
+	var dirs []string
+	byDir := map[string][]detector.Result{}
+	for _, res := range results {
+		dir := targetDirName(res.Target)
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], res)
 	}
 
-	return os.WriteFile(path, append(data, '\n'), 0o600)
+	var written []string
+	for _, dir := range dirs {
+		path := filepath.Join(r.cfg.OutputDir, dir, fmt.Sprintf("detections_%s%s.json", r.timestamp, suffix))
+		if err := writeDetectionsArtifact(path, byDir[dir]); err != nil {
+			return nil, err
+		}
+		recorded, err := r.recordArtifact(path, "detections")
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range byDir[dir] {
+			r.agg.AddTargetArtifact(res.Target, recorded)
+		}
+		written = append(written, recorded)
+	}
+	return written, nil
 }
 
-func writeDetectionsArtifact(path string, results []detector.Result) error {
+// targetDirName turns a target's host (with any port) into a directory name that is
+// safe on every filesystem: characters other than letters, digits, dots, and hyphens
+// become underscores, and names made only of dots are replaced.
+func targetDirName(target string) string {
+	raw := target
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	host := target
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.ToLower(host))
+	if strings.Trim(name, ".") == "" {
+		return "_"
+	}
+	return name
+}
+
+// writeGroupedDetectionsArtifact writes results as an object keyed by target.
+func writeGroupedDetectionsArtifact(path string, results []detector.Result) error {
 	if err := ensureOutputDir(filepath.Dir(path)); err != nil {
 		return err
 	}
 
-	data, err := json.MarshalIndent(results, "", "  ")
+	data, err := json.MarshalIndent(detector.GroupByTarget(results), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+func writeBatchIndex(path string, index []scanBatchIndex) error {
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"generatedAt": time.Now().UTC().Format(time.RFC3339),
+		"batches":     index,
+	}, "", "  ")
 	if err != nil {
 		return err
 	}
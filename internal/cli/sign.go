@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// signatureSuffix is appended to an artifact path to name its detached signature.
+const signatureSuffix = ".sig"
+
+// loadSigningKey reads a PEM-encoded PKCS#8 ed25519 private key, as written by
+// `openssl genpkey -algorithm ed25519`.
+func loadSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("signing key %s is not a PEM-encoded PKCS#8 private key", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse signing key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is %T, expected ed25519", path, parsed)
+	}
+	return key, nil
+}
+
+// publicKeyFingerprint identifies the verifying key in the summary, formatted like
+// OpenSSH fingerprints: SHA256 of the raw public key, base64 without padding.
+func publicKeyFingerprint(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// signArtifact writes the base64 ed25519 signature of the file at path to
+// path + signatureSuffix and returns the signature path.
+func signArtifact(path string, key ed25519.PrivateKey) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read artifact to sign: %w", err)
+	}
+
+	sigPath := path + signatureSuffix
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)) + "\n"
+	if err := os.WriteFile(sigPath, []byte(sig), 0o600); err != nil {
+		return "", fmt.Errorf("write signature: %w", err)
+	}
+	return sigPath, nil
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/example/wphunter/internal/config"
+)
+
+// writeSigningKey generates an ed25519 key pair and stores the private key as PEM.
+func writeSigningKey(t *testing.T) (string, ed25519.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "sign.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return path, pub
+}
+
+// verifySignature checks the detached signature next to path against pub.
+func verifySignature(t *testing.T, path string, pub ed25519.PublicKey) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read artifact: %v", err)
+	}
+	encoded, err := os.ReadFile(path + signatureSuffix)
+	if err != nil {
+		t.Fatalf("read signature: %v", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	if !ed25519.Verify(pub, data, sig) {
+		t.Fatalf("signature for %s does not verify", filepath.Base(path))
+	}
+}
+
+func TestSignArtifactVerifiesWithPublicKey(t *testing.T) {
+	keyPath, pub := writeSigningKey(t)
+	key, err := loadSigningKey(keyPath)
+	if err != nil {
+		t.Fatalf("load key: %v", err)
+	}
+
+	artifact := filepath.Join(t.TempDir(), "detections.json")
+	if err := os.WriteFile(artifact, []byte(`[{"target":"https://one.test"}]`), 0o600); err != nil {
+		t.Fatalf("write artifact: %v", err)
+	}
+	if _, err := signArtifact(artifact, key); err != nil {
+		t.Fatalf("sign artifact: %v", err)
+	}
+	verifySignature(t, artifact, pub)
+
+	// Tampering with the artifact must break verification.
+	if err := os.WriteFile(artifact, []byte(`[]`), 0o600); err != nil {
+		t.Fatalf("rewrite artifact: %v", err)
+	}
+	data, _ := os.ReadFile(artifact)
+	encoded, _ := os.ReadFile(artifact + signatureSuffix)
+	sig, _ := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+	if ed25519.Verify(pub, data, sig) {
+		t.Fatalf("expected a tampered artifact to fail verification")
+	}
+}
+
+func TestScanCommandSignsArtifacts(t *testing.T) {
+	keyPath, pub := writeSigningKey(t)
+	outputDir := t.TempDir()
+	summaryPath := filepath.Join(outputDir, "summary.json")
+
+	cmd := newScanCmd(&config.Loader{ConfigPath: ""})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"--targets", "https://one.test",
+		"--dry-run",
+		"--detectors", "",
+		"--output-dir", outputDir,
+		"--formats", "json",
+		"--summary-file", summaryPath,
+		"--sign-key", keyPath,
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("scan command failed: %v", err)
+	}
+
+	artifacts, err := filepath.Glob(filepath.Join(outputDir, "scan_*.json"))
+	if err != nil || len(artifacts) != 1 {
+		t.Fatalf("expected one artifact, got %v (%v)", artifacts, err)
+	}
+	verifySignature(t, artifacts[0], pub)
+	verifySignature(t, summaryPath, pub)
+
+	data, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("read summary: %v", err)
+	}
+	var summary scanSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("parse summary: %v", err)
+	}
+	if want := publicKeyFingerprint(pub); summary.Meta.SigningKey != want {
+		t.Fatalf("summary signing key = %q, want %q", summary.Meta.SigningKey, want)
+	}
+}
+
+func TestLoadSigningKeyRejectsNonPEM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(path, []byte("not a key"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	if _, err := loadSigningKey(path); err == nil {
+		t.Fatalf("expected an error for a non-PEM key")
+	}
+}
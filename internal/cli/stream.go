@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// fifoRetryInterval is how often openDetectionStream retries a named pipe that has
+// no reader yet.
+const fifoRetryInterval = 100 * time.Millisecond
+
+// openDetectionStream opens the --stream-to destination. A named pipe is opened
+// write-only without truncation once a reader opens the other end, or fails when ctx
+// ends first; any other path is created or truncated like an artifact.
+func openDetectionStream(ctx context.Context, path string) (*os.File, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeNamedPipe != 0 {
+		return openFIFOWriter(ctx, path)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open stream %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// openFIFOWriter opens the write end of a named pipe. A blocking open would hang until
+// a reader arrives, out of reach of --timeout and interrupts, so the open is
+// non-blocking (failing with ENXIO while there is no reader) and retried until ctx ends.
+func openFIFOWriter(ctx context.Context, path string) (*os.File, error) {
+	for {
+		file, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, syscall.ENXIO) {
+			return nil, fmt.Errorf("open stream fifo %s: %w", path, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("open stream fifo %s: no reader: %w", path, ctx.Err())
+		case <-time.After(fifoRetryInterval):
+		}
+	}
+}
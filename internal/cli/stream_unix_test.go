@@ -0,0 +1,105 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/example/wphunter/internal/config"
+	"github.com/example/wphunter/internal/detector"
+	"github.com/example/wphunter/internal/wpprobe"
+)
+
+func TestScanCommandStreamsDetectionsToFIFO(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<meta name="generator" content="WordPress 6.4.2" />`))
+	}))
+	defer server.Close()
+
+	original := newWPProbeRunner
+	newWPProbeRunner = func() wpprobe.Runner { return &exitingRunner{} }
+	defer func() { newWPProbeRunner = original }()
+
+	fifo := filepath.Join(t.TempDir(), "detections.fifo")
+	if err := syscall.Mkfifo(fifo, 0o600); err != nil {
+		t.Fatalf("mkfifo: %v", err)
+	}
+
+	// The scan blocks opening the FIFO until this reader opens it, and the reader
+	// sees EOF once the scan closes its end.
+	type readResult struct {
+		results []detector.Result
+		err     error
+	}
+	done := make(chan readResult, 1)
+	go func() {
+		file, err := os.Open(fifo)
+		if err != nil {
+			done <- readResult{err: err}
+			return
+		}
+		defer file.Close()
+
+		var results []detector.Result
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var res detector.Result
+			if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+				done <- readResult{err: err}
+				return
+			}
+			results = append(results, res)
+		}
+		done <- readResult{results: results, err: scanner.Err()}
+	}()
+
+	cmd := newScanCmd(&config.Loader{ConfigPath: ""})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--targets", server.URL, "--detectors", "version", "--output-dir", t.TempDir(), "--formats", "json", "--stream-to", fifo})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("scan command failed: %v", err)
+	}
+
+	read := <-done
+	if read.err != nil {
+		t.Fatalf("read fifo: %v", read.err)
+	}
+	if len(read.results) != 1 || read.results[0].Metadata["version"] != "6.4.2" {
+		t.Fatalf("expected the version detection on the fifo, got %+v", read.results)
+	}
+	if info, err := os.Stat(fifo); err != nil || info.Mode()&os.ModeNamedPipe == 0 {
+		t.Fatalf("expected the fifo to be left in place, got %v (%v)", info, err)
+	}
+}
+
+func TestScanCommandStopsWaitingForFIFOReaderAtTimeout(t *testing.T) {
+	fifo := filepath.Join(t.TempDir(), "detections.fifo")
+	if err := syscall.Mkfifo(fifo, 0o600); err != nil {
+		t.Fatalf("mkfifo: %v", err)
+	}
+
+	cmd := newScanCmd(&config.Loader{ConfigPath: ""})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--targets", "https://one.test", "--dry-run", "--output-dir", t.TempDir(), "--stream-to", fifo, "--timeout", "200ms"})
+
+	start := time.Now()
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "no reader") {
+		t.Fatalf("expected the scan to give up on the readerless fifo, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the fifo wait to end at --timeout, took %s", elapsed)
+	}
+}
@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/example/wphunter/internal/config"
+	"github.com/example/wphunter/internal/detector"
+	"github.com/example/wphunter/internal/wpprobe"
+	"gopkg.in/yaml.v3"
+)
+
+// Summary formats, selected from the --summary-file extension.
+const (
+	summaryFormatJSON  = "json"
+	summaryFormatYAML  = "yaml"
+	summaryFormatJUnit = "junit"
+)
+
+// scanMeta records provenance for a scan so artifacts are self-describing in audits.
+type scanMeta struct {
+	RunID     string   `json:"runId" yaml:"runId"`
+	Version   string   `json:"version" yaml:"version"`
+	Hostname  string   `json:"hostname" yaml:"hostname"`
+	StartedAt string   `json:"startedAt" yaml:"startedAt"`
+	Args      []string `json:"args" yaml:"args"`
+	// SigningKey is the fingerprint of the public key verifying artifact signatures.
+	SigningKey string `json:"signingKey,omitempty" yaml:"signingKey,omitempty"`
+}
+
+// sensitiveFlagMarkers flag names whose values are redacted from scanMeta.Args.
+var sensitiveFlagMarkers = []string{"key", "token", "password", "secret"}
+
+// newScanMeta captures a unique run ID, the wphunter version, hostname, start time,
+// and sanitized args.
+func newScanMeta(args []string, startedAt time.Time) scanMeta {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return scanMeta{
+		RunID:     newRunID(startedAt),
+		Version:   version,
+		Hostname:  hostname,
+		StartedAt: startedAt.UTC().Format(time.RFC3339),
+		Args:      sanitizeArgs(args),
+	}
+}
+
+// newRunID returns a random identifier for one scan run, falling back to the start
+// time in nanoseconds if the system random source fails.
+func newRunID(startedAt time.Time) string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%x", startedAt.UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
+
+// sanitizeArgs redacts values of secret-looking flags and credentials embedded in URLs.
+func sanitizeArgs(args []string) []string {
+	sanitized := make([]string, 0, len(args))
+	redactNext := false
+	for _, arg := range args {
+		if redactNext {
+			sanitized = append(sanitized, "REDACTED")
+			redactNext = false
+			continue
+		}
+
+		if strings.HasPrefix(arg, "-") {
+			name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			if isSensitiveFlag(name) {
+				if hasValue {
+					arg = arg[:strings.Index(arg, "=")+1] + "REDACTED"
+				} else {
+					redactNext = true
+				}
+				sanitized = append(sanitized, arg)
+				continue
+			}
+		}
+
+		sanitized = append(sanitized, redactURLCredentials(arg))
+	}
+	return sanitized
+}
+
+func isSensitiveFlag(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range sensitiveFlagMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURLCredentials strips passwords from any user:pass@ URLs inside a
+// (possibly comma-separated) argument value.
+func redactURLCredentials(arg string) string {
+	if !strings.Contains(arg, "@") || !strings.Contains(arg, "://") {
+		return arg
+	}
+
+	parts := strings.Split(arg, ",")
+	for i, part := range parts {
+		prefix := ""
+		if idx := strings.Index(part, "="); idx >= 0 && idx < strings.Index(part, "://") {
+			prefix, part = part[:idx+1], part[idx+1:]
+		}
+		if u, err := url.Parse(part); err == nil && u.User != nil {
+			if _, hasPassword := u.User.Password(); hasPassword {
+				u.User = url.UserPassword(u.User.Username(), "REDACTED")
+				part = u.String()
+			}
+		}
+		parts[i] = prefix + part
+	}
+	return strings.Join(parts, ",")
+}
+
+// scanSummary is the consolidated summary written to every --summary-file.
+type scanSummary struct {
+	Meta        scanMeta          `json:"meta" yaml:"meta"`
+	GeneratedAt string            `json:"generatedAt" yaml:"generatedAt"`
+	Targets     []string          `json:"targets" yaml:"targets"`
+	Mode        string            `json:"mode" yaml:"mode"`
+	Artifacts   []string          `json:"artifacts" yaml:"artifacts"`
+	DryRun      bool              `json:"dryRun" yaml:"dryRun"`
+	Detectors   []string          `json:"detectors" yaml:"detectors"`
+	Detections  []detector.Result `json:"detections" yaml:"detections"`
+	Severities  map[string]int    `json:"severities" yaml:"severities"`
+	// Latency holds p50/p95/max detector durations per detector, to spot slow detectors.
+	Latency map[string]detectorLatency `json:"latency,omitempty" yaml:"latency,omitempty"`
+	// TargetArtifacts maps each target to its --per-target-output artifact.
+	TargetArtifacts map[string]string `json:"targetArtifacts,omitempty" yaml:"targetArtifacts,omitempty"`
+	// ConfigSources names the layer (default, file, env, or flag) that set each setting.
+	ConfigSources map[string]string `json:"configSources" yaml:"configSources"`
+	// WPProbe is the condensed wpprobe output, present with --embed-wpprobe.
+	WPProbe *wpprobe.Digest `json:"wpprobe,omitempty" yaml:"wpprobe,omitempty"`
+}
+
+// summaryFormatFor infers the summary format from the file extension.
+func summaryFormatFor(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return summaryFormatJSON, nil
+	case ".yml", ".yaml":
+		return summaryFormatYAML, nil
+	case ".xml":
+		return summaryFormatJUnit, nil
+	default:
+		return "", fmt.Errorf("unsupported summary file extension for %s (expected .json, .yml, .yaml, or .xml)", path)
+	}
+}
+
+// writeSummary writes the scan summary to path in the format implied by its extension.
+func writeSummary(path string, cfg config.RuntimeConfig, totals scanTotals, meta scanMeta) error {
+	format, err := summaryFormatFor(path)
+	if err != nil {
+		return err
+	}
+
+	summary := scanSummary{
+		Meta:          meta,
+		GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
+		Targets:       cfg.Targets,
+		Mode:          cfg.Mode,
+		Artifacts:     totals.Artifacts,
+		DryRun:        cfg.DryRun,
+		Detectors:     cfg.Detectors,
+		Detections:    totals.Detections,
+		Severities:    totals.Severities,
+		Latency:       detectorLatencies(totals.Detections),
+		WPProbe:       totals.WPProbe,
+		ConfigSources: cfg.Sources,
+	}
+	summary.TargetArtifacts = totals.TargetArtifacts
+	// Empty lists are written as [] rather than null so every format carries the same shape.
+	if summary.Artifacts == nil {
+		summary.Artifacts = []string{}
+	}
+	if summary.Detections == nil {
+		summary.Detections = []detector.Result{}
+	}
+	if summary.ConfigSources == nil {
+		summary.ConfigSources = map[string]string{}
+	}
+
+	var data []byte
+	switch format {
+	case summaryFormatJSON:
+		data, err = json.MarshalIndent(summary, "", "  ")
+		data = append(data, '\n')
+	case summaryFormatYAML:
+		data, err = yaml.Marshal(summary)
+	case summaryFormatJUnit:
+		data, err = marshalJUnitSummary(summary)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := ensureOutputDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+// marshalJUnitSummary renders detections as a JUnit report so CI systems can surface
+// findings: one test case per detection, failing when the severity is not informational.
+func marshalJUnitSummary(summary scanSummary) ([]byte, error) {
+	suite := junitTestSuite{
+		Name:      "wphunter",
+		Tests:     len(summary.Detections),
+		Timestamp: summary.GeneratedAt,
+	}
+
+	for _, res := range summary.Detections {
+		tc := junitTestCase{ClassName: res.Detector, Name: res.Target}
+		if res.Severity != "" && res.Severity != "info" {
+			tc.Failure = &junitFailure{Message: res.Summary, Type: res.Severity}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// tracerName identifies wphunter's spans as their instrumentation scope.
+const tracerName = "github.com/example/wphunter"
+
+// tracerShutdownTimeout bounds how long the final span export may delay exit.
+const tracerShutdownTimeout = 5 * time.Second
+
+// newTracerProvider builds a tracer provider that batches spans to an OTLP/HTTP
+// collector. endpoint is a URL such as http://localhost:4318; a bare host:port uses
+// HTTPS like other OTLP clients.
+func newTracerProvider(ctx context.Context, endpoint string) (*sdktrace.TracerProvider, error) {
+	opt := otlptracehttp.WithEndpoint(endpoint)
+	if strings.Contains(endpoint, "://") {
+		opt = otlptracehttp.WithEndpointURL(endpoint)
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opt)
+	if err != nil {
+		return nil, fmt.Errorf("configure OTLP exporter for %s: %w", endpoint, err)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", "wphunter"),
+		attribute.String("service.version", version),
+	)
+	return sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res)), nil
+}
+
+// shutdownTracerProvider flushes pending spans. Export failures are ignored because
+// tracing is best effort and must not fail a finished scan.
+func shutdownTracerProvider(provider *sdktrace.TracerProvider) {
+	ctx, cancel := context.WithTimeout(context.Background(), tracerShutdownTimeout)
+	defer cancel()
+	_ = provider.Shutdown(ctx)
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/example/wphunter/internal/detector"
+	"github.com/spf13/cobra"
+)
+
+// requiredResultFields are the detector.Result keys that are always serialized.
+var requiredResultFields = []string{"target", "detector", "severity", "summary"}
+
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate <file>",
+		Short: "Check that a detections artifact matches the wphunter result schema",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			violations, err := validateDetections(data)
+			if err != nil {
+				return err
+			}
+
+			for _, violation := range violations {
+				fmt.Fprintln(cmd.OutOrStdout(), violation)
+			}
+
+			if len(violations) > 0 {
+				return fmt.Errorf("%s: %d schema violation(s)", args[0], len(violations))
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: valid detections artifact\n", args[0])
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// validateDetections checks a flat or target-grouped detections artifact and returns
+// one message per violation. An error is returned only when the input is not JSON of
+// either shape.
+func validateDetections(data []byte) ([]string, error) {
+	var flat []map[string]interface{}
+	if err := json.Unmarshal(data, &flat); err == nil {
+		var violations []string
+		for i, entry := range flat {
+			violations = append(violations, validateResultEntry(fmt.Sprintf("entry %d", i), entry, "")...)
+		}
+		return violations, nil
+	}
+
+	var grouped map[string][]map[string]interface{}
+	if err := json.Unmarshal(data, &grouped); err != nil {
+		return nil, fmt.Errorf("input is not a detections artifact: expected an array of results or an object keyed by target: %w", err)
+	}
+
+	targets := make([]string, 0, len(grouped))
+	for target := range grouped {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	var violations []string
+	for _, target := range targets {
+		for i, entry := range grouped[target] {
+			violations = append(violations, validateResultEntry(fmt.Sprintf("%s entry %d", target, i), entry, target)...)
+		}
+	}
+	return violations, nil
+}
+
+func validateResultEntry(label string, entry map[string]interface{}, groupTarget string) []string {
+	var violations []string
+
+	for _, field := range requiredResultFields {
+		value, ok := entry[field]
+		if !ok {
+			violations = append(violations, fmt.Sprintf("%s: missing required field %q", label, field))
+			continue
+		}
+		str, ok := value.(string)
+		if !ok {
+			violations = append(violations, fmt.Sprintf("%s: field %q must be a string", label, field))
+			continue
+		}
+		if field != "summary" && strings.TrimSpace(str) == "" {
+			violations = append(violations, fmt.Sprintf("%s: field %q must not be empty", label, field))
+		}
+	}
+
+	if severity, ok := entry["severity"].(string); ok && severity != "" {
+		if err := detector.ValidateSeverity(severity); err != nil {
+			violations = append(violations, fmt.Sprintf("%s: %v", label, err))
+		}
+	}
+
+	if value, ok := entry["confidence"]; ok {
+		confidence, isNumber := value.(float64)
+		switch {
+		case !isNumber:
+			violations = append(violations, fmt.Sprintf("%s: confidence must be a number", label))
+		case confidence < 0 || confidence > 1:
+			violations = append(violations, fmt.Sprintf("%s: confidence %g is outside [0,1]", label, confidence))
+		}
+	}
+
+	if groupTarget != "" {
+		if target, ok := entry["target"].(string); ok && target != "" && target != groupTarget {
+			violations = append(violations, fmt.Sprintf("%s: target %q does not match its group", label, target))
+		}
+	}
+
+	return violations
+}
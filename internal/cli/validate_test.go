@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/example/wphunter/internal/detector"
+)
+
+func TestValidateCommandAcceptsValidArtifact(t *testing.T) {
+	inputPath := writeDetectionsFixture(t, []detector.Result{
+		{Target: "https://one.test", Detector: "version", Severity: "info", Summary: "WordPress version 6.5.1 detected", Confidence: 0.85},
+		{Target: "https://two.test", Detector: "vcs", Severity: "critical", Summary: "Exposed sensitive files: /.git/config", Confidence: 0.95},
+		{Target: "https://three.test", Detector: "vcs", Severity: "clean", Summary: "No exposed version-control or environment files"},
+	})
+
+	cmd := newValidateCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{inputPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("validate failed on a valid artifact: %v\n%s", err, buf.String())
+	}
+
+	if !strings.Contains(buf.String(), "valid detections artifact") {
+		t.Fatalf("expected success message, got %q", buf.String())
+	}
+}
+
+func TestValidateCommandRejectsOutOfRangeConfidence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "third-party.json")
+	content := `[{"target":"https://one.test","detector":"thirdparty","severity":"severe","summary":"x","confidence":1.5},{"detector":"thirdparty","severity":"info","summary":"y"}]`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write artifact: %v", err)
+	}
+
+	cmd := newValidateCmd()
+	buf := &bytes.Buffer{}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{path})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected validate to fail")
+	}
+
+	if !strings.Contains(err.Error(), "3 schema violation(s)") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{"confidence 1.5 is outside [0,1]", `severity "severe"`, `entry 1: missing required field "target"`} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("expected output to mention %q, got:\n%s", expected, buf.String())
+		}
+	}
+}
+
+func TestValidateDetectionsGroupedArtifact(t *testing.T) {
+	data := []byte(`{"https://one.test":[{"target":"https://two.test","detector":"php","severity":"info","summary":"PHP version not disclosed"}]}`)
+
+	violations, err := validateDetections(data)
+	if err != nil {
+		t.Fatalf("validate grouped: %v", err)
+	}
+
+	if len(violations) != 1 || !strings.Contains(violations[0], "does not match its group") {
+		t.Fatalf("expected group mismatch violation, got %v", violations)
+	}
+
+	if _, err := validateDetections([]byte(`"not an artifact"`)); err == nil {
+		t.Fatal("expected error for non-artifact input")
+	}
+}
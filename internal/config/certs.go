@@ -0,0 +1,32 @@
+package config
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// LoadCertPool returns a copy of the system root pool with the certificates from
+// each PEM file in paths added. It returns nil, nil when paths is empty so callers
+// keep the transport default. Files without a parseable certificate are rejected.
+func LoadCertPool(paths []string) (*x509.CertPool, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read CA certificate %s: %w", path, err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("CA certificate %s contains no valid PEM certificates", path)
+		}
+	}
+	return pool, nil
+}
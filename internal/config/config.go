@@ -1,14 +1,18 @@
 package config
 
 import (
-	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 
+	"github.com/example/wphunter/internal/detector"
+	"github.com/titanous/json5"
 	"gopkg.in/yaml.v3"
 )
 
@@ -30,37 +34,121 @@ var (
 	envDryRunKeys      = []string{"WPHUNTER_DRY_RUN", "WORKER_DRY_RUN"}
 	envSummaryFileKeys = []string{"WPHUNTER_SUMMARY_FILE", "WORKER_SUMMARY_FILE"}
 	envDetectorsKeys   = []string{"WPHUNTER_DETECTORS", "WORKER_DETECTORS"}
+	envSandboxRootKeys = []string{"WPHUNTER_SANDBOX_ROOT", "WORKER_SANDBOX_ROOT"}
+
+	envTargetsFileFormatKeys = []string{"WPHUNTER_TARGETS_FILE_FORMAT", "WORKER_TARGETS_FILE_FORMAT"}
+	envTargetsCSVColumnKeys  = []string{"WPHUNTER_TARGETS_CSV_COLUMN", "WORKER_TARGETS_CSV_COLUMN"}
+	envMaxIdleConnsKeys      = []string{"WPHUNTER_MAX_IDLE_CONNS", "WORKER_MAX_IDLE_CONNS"}
+	envMaxConnsPerHostKeys   = []string{"WPHUNTER_MAX_CONNS_PER_HOST", "WORKER_MAX_CONNS_PER_HOST"}
+	envDisabledDetectorsKeys = []string{"WPHUNTER_DISABLED_DETECTORS", "WORKER_DISABLED_DETECTORS"}
+	envResolveKeys           = []string{"WPHUNTER_RESOLVE", "WORKER_RESOLVE"}
+	envCACertsKeys           = []string{"WPHUNTER_CA_CERTS", "WORKER_CA_CERTS"}
+	envUseXDGKeys            = []string{"WPHUNTER_USE_XDG", "WORKER_USE_XDG"}
+	envTargetPrefixKeys      = []string{"WPHUNTER_TARGET_PREFIX", "WORKER_TARGET_PREFIX"}
+	envTargetSuffixKeys      = []string{"WPHUNTER_TARGET_SUFFIX", "WORKER_TARGET_SUFFIX"}
 )
 
 // Loader merges configuration coming from files, environment variables, and CLI flags.
 type Loader struct {
 	ConfigPath string
+	// AllowSystemPaths disables the system-file denylist applied to targets files.
+	// Null-byte and length checks still apply. Only enable this when the targets
+	// file legitimately lives under a protected prefix such as /dev/shm.
+	AllowSystemPaths bool
+}
+
+// targetsFileOptions controls how targets files are read.
+type targetsFileOptions struct {
+	AllowSystemPaths bool
+	// Format is one of the TargetsFileFormat* constants; empty means lines.
+	Format string
+	// Column names the CSV column or JSON object field holding the target URL.
+	Column string
 }
 
 // RuntimeConfig contains the fully merged settings required by worker sub-commands.
 type RuntimeConfig struct {
-	Targets     []string
-	Mode        string
-	Threads     int
-	OutputDir   string
-	Formats     []string
-	Detectors   []string
-	DryRun      bool
-	SummaryFile string
+	Targets   []string
+	Mode      string
+	Threads   int
+	OutputDir string
+	Formats   []string
+	Detectors []string
+	DryRun    bool
+	// DisabledDetectors are removed from the detector set even when requested, so
+	// shared runners can forbid detectors by policy. Every layer adds to the list.
+	DisabledDetectors []string
+	// SummaryFiles lists summary outputs; each file's format follows its extension.
+	SummaryFiles []string
+	// SandboxRoot, when set, confines the output directory and summary files to this directory.
+	SandboxRoot string
+	// Confidence overrides detector confidence values keyed by signal name.
+	Confidence map[string]float64
+	// SSHTunnel routes detector traffic through an SSH jump host (user@host[:port]).
+	SSHTunnel string
+	SSHKey    string
+	// Resolve pins detector connections for a host to an IP ("host:ip"), like curl's
+	// --resolve, while keeping the Host header and TLS SNI of the target URL.
+	Resolve []string
+	// CACerts lists PEM files whose certificates are trusted by detector requests in
+	// addition to the system roots, for sites signed by a private CA.
+	CACerts []string
+	// ExpectedVersions maps targets to the WordPress version an inventory CSV expects;
+	// detected versions that differ are reported as drift.
+	ExpectedVersions map[string]string
+	// TargetOptions holds per-target mode and detector overrides from a JSON Lines
+	// targets file, keyed by target.
+	TargetOptions map[string]TargetOptions
+	// TargetPrefix and TargetSuffix are applied to every target once all layers are
+	// merged; see ApplyTargetAffixes.
+	TargetPrefix string
+	TargetSuffix string
+	// MaxIdleConns and MaxConnsPerHost tune the detector HTTP transport's connection
+	// pool. Zero derives a default from Threads.
+	MaxIdleConns    int
+	MaxConnsPerHost int
+	// Sources records which layer last set each setting, keyed by its config file
+	// name (confidence signals as "confidence.<signal>"), with one of the Source* values.
+	Sources map[string]string
 }
 
+// Config layers recorded in RuntimeConfig.Sources, lowest precedence first.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
 // Overrides captures values coming from env vars or CLI flags.
 type Overrides struct {
 	Targets     []string
 	TargetsFile string
-	Mode        string
-	Threads     int
-	ThreadsSet  bool
-	OutputDir   string
-	Formats     []string
-	Detectors   []string
-	DryRun      *bool
-	SummaryFile string
+	// TargetsFileFormat and TargetsCSVColumn select the parser for TargetsFile.
+	TargetsFileFormat string
+	TargetsCSVColumn  string
+	TargetPrefix      string
+	TargetSuffix      string
+	Mode              string
+	Threads           int
+	ThreadsSet        bool
+	OutputDir         string
+	Formats           []string
+	Detectors         []string
+	// DetectorsFile names a file of detectors, one per line, merged ahead of Detectors.
+	DetectorsFile     string
+	DisabledDetectors []string
+	DryRun            *bool
+	UseXDG            *bool
+	SummaryFiles      []string
+	SandboxRoot       string
+	Confidence        map[string]float64
+	SSHTunnel         string
+	SSHKey            string
+	Resolve           []string
+	CACerts           []string
+	MaxIdleConns      int
+	MaxConnsPerHost   int
 }
 
 // DefaultRuntimeConfig returns the baseline configuration when no overrides are provided.
@@ -71,9 +159,22 @@ func DefaultRuntimeConfig() RuntimeConfig {
 		OutputDir: "scan-results",
 		Formats:   []string{"json", "csv"},
 		Detectors: []string{"version"},
+		Sources: map[string]string{
+			"mode":      SourceDefault,
+			"threads":   SourceDefault,
+			"outputDir": SourceDefault,
+			"formats":   SourceDefault,
+			"detectors": SourceDefault,
+		},
 	}
 }
 
+// sourcedOverrides is one configuration layer and the Source* value it is recorded as.
+type sourcedOverrides struct {
+	Overrides
+	source string
+}
+
 // Load resolves the final runtime configuration.
 func (l Loader) Load(override Overrides) (RuntimeConfig, error) {
 	cfg := DefaultRuntimeConfig()
@@ -82,23 +183,47 @@ func (l Loader) Load(override Overrides) (RuntimeConfig, error) {
 		path = DefaultConfigPath
 	}
 
+	var layers []sourcedOverrides
 	if fileExists(path) {
 		fileOv, err := loadFromFile(path)
 		if err != nil {
 			return cfg, err
 		}
-		if err := cfg.apply(fileOv); err != nil {
-			return cfg, err
+		layers = append(layers, sourcedOverrides{fileOv, SourceFile})
+	}
+	layers = append(layers, sourcedOverrides{overridesFromEnv(), SourceEnv}, sourcedOverrides{override, SourceFlag})
+
+	// The targets file parser is resolved across all layers first so a format
+	// given on the command line also applies to a targets file from the config.
+	// Likewise the XDG opt-in only swaps the default; any layer's outputDir still wins.
+	fileOpts := targetsFileOptions{AllowSystemPaths: l.AllowSystemPaths}
+	useXDG, xdgSource := false, ""
+	for _, layer := range layers {
+		if layer.TargetsFileFormat != "" {
+			fileOpts.Format = layer.TargetsFileFormat
+		}
+		if layer.TargetsCSVColumn != "" {
+			fileOpts.Column = layer.TargetsCSVColumn
+		}
+		if layer.UseXDG != nil {
+			useXDG, xdgSource = *layer.UseXDG, layer.source
 		}
 	}
-
-	if err := cfg.apply(overridesFromEnv()); err != nil {
-		return cfg, err
+	if useXDG {
+		dir, err := XDGOutputDir()
+		if err != nil {
+			return cfg, err
+		}
+		cfg.OutputDir = dir
+		cfg.Sources["outputDir"] = xdgSource
 	}
 
-	if err := cfg.apply(override); err != nil {
-		return cfg, err
+	for _, layer := range layers {
+		if err := cfg.apply(layer.Overrides, fileOpts, layer.source); err != nil {
+			return cfg, err
+		}
 	}
+	cfg.applyTargetAffixes()
 
 	return cfg, nil
 }
@@ -121,52 +246,175 @@ func (c RuntimeConfig) Validate() error {
 		return errors.New("at least one output format must be specified")
 	}
 
+	for _, format := range c.Formats {
+		if !isSupportedFormat(format) {
+			return fmt.Errorf("unsupported output format %q (valid formats: %s)", format, strings.Join(SupportedFormats, ", "))
+		}
+	}
+
 	if c.OutputDir == "" {
 		return errors.New("output directory cannot be empty")
 	}
 
+	if _, err := ParseResolveOverrides(c.Resolve); err != nil {
+		return err
+	}
+
+	if _, err := LoadCertPool(c.CACerts); err != nil {
+		return err
+	}
+
+	if c.MaxIdleConns < 0 || c.MaxConnsPerHost < 0 {
+		return errors.New("connection pool limits must not be negative")
+	}
+
+	for key, value := range c.Confidence {
+		if !isConfidenceKey(key) {
+			return fmt.Errorf("unknown confidence key %q (valid keys: %s)", key, strings.Join(detector.ConfidenceKeys, ", "))
+		}
+		if value < 0 || value > 1 {
+			return fmt.Errorf("confidence for %s must be between 0 and 1 (got %g)", key, value)
+		}
+	}
+
+	if c.SandboxRoot != "" {
+		if err := EnsureWithinRoot(c.SandboxRoot, c.OutputDir); err != nil {
+			return err
+		}
+		for _, path := range c.SummaryFiles {
+			if err := EnsureWithinRoot(c.SandboxRoot, path); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
-func (c *RuntimeConfig) apply(src Overrides) error {
+// apply overlays src onto c, recording source for every setting it changes.
+func (c *RuntimeConfig) apply(src Overrides, fileOpts targetsFileOptions, source string) error {
+	if c.Sources == nil {
+		c.Sources = map[string]string{}
+	}
+
 	if len(src.Targets) > 0 {
 		c.Targets = cleanList(src.Targets)
+		c.Sources["targets"] = source
 	}
 
 	if src.TargetsFile != "" {
-		values, err := readTargetsFile(src.TargetsFile)
+		contents, err := loadTargetsFile(src.TargetsFile, fileOpts)
 		if err != nil {
 			return err
 		}
-		c.Targets = values
+		c.Targets = contents.Targets
+		c.ExpectedVersions = contents.ExpectedVersions
+		c.TargetOptions = contents.TargetOptions
+		c.Sources["targets"] = source
+	}
+
+	if src.TargetPrefix != "" {
+		c.TargetPrefix = src.TargetPrefix
+		c.Sources["targetPrefix"] = source
+	}
+
+	if src.TargetSuffix != "" {
+		c.TargetSuffix = src.TargetSuffix
+		c.Sources["targetSuffix"] = source
 	}
 
 	if src.Mode != "" {
 		c.Mode = src.Mode
+		c.Sources["mode"] = source
 	}
 
 	if src.ThreadsSet {
 		c.Threads = src.Threads
+		c.Sources["threads"] = source
 	}
 
 	if src.OutputDir != "" {
 		c.OutputDir = src.OutputDir
+		c.Sources["outputDir"] = source
 	}
 
 	if len(src.Formats) > 0 {
 		c.Formats = cleanList(src.Formats)
+		c.Sources["formats"] = source
+	}
+
+	detectors := src.Detectors
+	if src.DetectorsFile != "" {
+		names, err := readDetectorsFile(src.DetectorsFile, fileOpts.AllowSystemPaths)
+		if err != nil {
+			return err
+		}
+		detectors = append(names, detectors...)
+	}
+	if len(detectors) > 0 {
+		c.Detectors = cleanList(detectors)
+		c.Sources["detectors"] = source
 	}
 
-	if len(src.Detectors) > 0 {
-		c.Detectors = cleanList(src.Detectors)
+	// Disabled detectors accumulate across layers so a later layer cannot lift a
+	// runner's policy by supplying a list of its own.
+	if len(src.DisabledDetectors) > 0 {
+		c.DisabledDetectors = unionList(c.DisabledDetectors, cleanList(src.DisabledDetectors))
+		c.Sources["disabledDetectors"] = source
 	}
 
 	if src.DryRun != nil {
 		c.DryRun = *src.DryRun
+		c.Sources["dryRun"] = source
+	}
+
+	if len(src.SummaryFiles) > 0 {
+		c.SummaryFiles = cleanList(src.SummaryFiles)
+		c.Sources["summaryFile"] = source
+	}
+
+	if src.SandboxRoot != "" {
+		c.SandboxRoot = src.SandboxRoot
+		c.Sources["sandboxRoot"] = source
+	}
+
+	if src.SSHTunnel != "" {
+		c.SSHTunnel = src.SSHTunnel
+		c.Sources["sshTunnel"] = source
 	}
 
-	if src.SummaryFile != "" {
-		c.SummaryFile = src.SummaryFile
+	if src.SSHKey != "" {
+		c.SSHKey = src.SSHKey
+		c.Sources["sshKey"] = source
+	}
+
+	if len(src.Resolve) > 0 {
+		c.Resolve = cleanList(src.Resolve)
+		c.Sources["resolve"] = source
+	}
+
+	if len(src.CACerts) > 0 {
+		c.CACerts = cleanList(src.CACerts)
+		c.Sources["caCerts"] = source
+	}
+
+	if src.MaxIdleConns != 0 {
+		c.MaxIdleConns = src.MaxIdleConns
+		c.Sources["maxIdleConns"] = source
+	}
+
+	if src.MaxConnsPerHost != 0 {
+		c.MaxConnsPerHost = src.MaxConnsPerHost
+		c.Sources["maxConnsPerHost"] = source
+	}
+
+	// Confidence values are merged per key so layers can calibrate individual signals.
+	for key, value := range src.Confidence {
+		if c.Confidence == nil {
+			c.Confidence = map[string]float64{}
+		}
+		c.Confidence[key] = value
+		c.Sources["confidence."+key] = source
 	}
 
 	return nil
@@ -179,34 +427,70 @@ func loadFromFile(path string) (Overrides, error) {
 	}
 
 	type rawConfig struct {
-		Targets     targetList `yaml:"targets"`
-		TargetsFile string     `yaml:"targetsFile"`
-		Mode        string     `yaml:"mode"`
-		Threads     *int       `yaml:"threads"`
-		OutputDir   string     `yaml:"outputDir"`
-		Formats     []string   `yaml:"formats"`
-		Detectors   []string   `yaml:"detectors"`
-		DryRun      *bool      `yaml:"dryRun"`
-		SummaryFile string     `yaml:"summaryFile"`
+		Targets           targetList         `yaml:"targets"`
+		TargetsFile       string             `yaml:"targetsFile"`
+		TargetsFileFormat string             `yaml:"targetsFileFormat"`
+		TargetsCSVColumn  string             `yaml:"targetsCsvColumn"`
+		TargetPrefix      string             `yaml:"targetPrefix"`
+		TargetSuffix      string             `yaml:"targetSuffix"`
+		Mode              string             `yaml:"mode"`
+		Threads           *threadsValue      `yaml:"threads"`
+		OutputDir         string             `yaml:"outputDir"`
+		Formats           []string           `yaml:"formats"`
+		Detectors         []string           `yaml:"detectors"`
+		DetectorsFile     string             `yaml:"detectorsFile"`
+		DisabledDetectors []string           `yaml:"disabledDetectors"`
+		DryRun            *bool              `yaml:"dryRun"`
+		UseXDG            *bool              `yaml:"useXDG"`
+		SummaryFile       targetList         `yaml:"summaryFile"`
+		SandboxRoot       string             `yaml:"sandboxRoot"`
+		Confidence        map[string]float64 `yaml:"confidence"`
+		SSHTunnel         string             `yaml:"sshTunnel"`
+		SSHKey            string             `yaml:"sshKey"`
+		Resolve           []string           `yaml:"resolve"`
+		CACerts           []string           `yaml:"caCerts"`
+		MaxIdleConns      int                `yaml:"maxIdleConns"`
+		MaxConnsPerHost   int                `yaml:"maxConnsPerHost"`
+	}
+
+	if isJSONConfig(path) {
+		if data, err = json5ToJSON(data); err != nil {
+			return Overrides{}, fmt.Errorf("parse %s: %w", path, err)
+		}
 	}
 
+	// JSON is valid YAML, so both formats decode into rawConfig the same way.
 	var raw rawConfig
 	if err := yaml.Unmarshal(data, &raw); err != nil {
 		return Overrides{}, err
 	}
 
 	over := Overrides{
-		Targets:     raw.Targets,
-		TargetsFile: raw.TargetsFile,
-		Mode:        raw.Mode,
-		OutputDir:   raw.OutputDir,
-		Formats:     raw.Formats,
-		Detectors:   raw.Detectors,
-		SummaryFile: raw.SummaryFile,
+		Targets:           raw.Targets,
+		TargetsFile:       raw.TargetsFile,
+		TargetsFileFormat: raw.TargetsFileFormat,
+		TargetsCSVColumn:  raw.TargetsCSVColumn,
+		TargetPrefix:      raw.TargetPrefix,
+		TargetSuffix:      raw.TargetSuffix,
+		Mode:              raw.Mode,
+		OutputDir:         raw.OutputDir,
+		Formats:           raw.Formats,
+		Detectors:         raw.Detectors,
+		DetectorsFile:     raw.DetectorsFile,
+		DisabledDetectors: raw.DisabledDetectors,
+		SummaryFiles:      raw.SummaryFile,
+		SandboxRoot:       raw.SandboxRoot,
+		Confidence:        raw.Confidence,
+		SSHTunnel:         raw.SSHTunnel,
+		SSHKey:            raw.SSHKey,
+		Resolve:           raw.Resolve,
+		CACerts:           raw.CACerts,
+		MaxIdleConns:      raw.MaxIdleConns,
+		MaxConnsPerHost:   raw.MaxConnsPerHost,
 	}
 
 	if raw.Threads != nil {
-		over.Threads = *raw.Threads
+		over.Threads = int(*raw.Threads)
 		over.ThreadsSet = true
 	}
 
@@ -214,9 +498,33 @@ func loadFromFile(path string) (Overrides, error) {
 		over.DryRun = raw.DryRun
 	}
 
+	if raw.UseXDG != nil {
+		over.UseXDG = raw.UseXDG
+	}
+
 	return over, nil
 }
 
+// isJSONConfig reports whether path names a JSON or JSON5 config file.
+func isJSONConfig(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".json5":
+		return true
+	default:
+		return false
+	}
+}
+
+// json5ToJSON parses JSON5 (JSON with comments, trailing commas, and unquoted keys)
+// and re-encodes it as plain JSON.
+func json5ToJSON(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := json5.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
 func overridesFromEnv() Overrides {
 	ov := Overrides{}
 
@@ -228,12 +536,28 @@ func overridesFromEnv() Overrides {
 		ov.TargetsFile = value
 	}
 
+	if value := lookupEnv(envTargetsFileFormatKeys); value != "" {
+		ov.TargetsFileFormat = value
+	}
+
+	if value := lookupEnv(envTargetsCSVColumnKeys); value != "" {
+		ov.TargetsCSVColumn = value
+	}
+
+	if value := lookupEnv(envTargetPrefixKeys); value != "" {
+		ov.TargetPrefix = value
+	}
+
+	if value := lookupEnv(envTargetSuffixKeys); value != "" {
+		ov.TargetSuffix = value
+	}
+
 	if value := lookupEnv(envModeKeys); value != "" {
 		ov.Mode = value
 	}
 
 	if value := lookupEnv(envThreadsKeys); value != "" {
-		if parsed, err := strconv.Atoi(value); err == nil {
+		if parsed, err := ParseThreads(value); err == nil {
 			ov.Threads = parsed
 			ov.ThreadsSet = true
 		}
@@ -252,22 +576,107 @@ func overridesFromEnv() Overrides {
 		ov.DryRun = &parsed
 	}
 
+	if value := lookupEnv(envUseXDGKeys); value != "" {
+		parsed := strings.EqualFold(value, "true") || value == "1"
+		ov.UseXDG = &parsed
+	}
+
 	if value := lookupEnv(envSummaryFileKeys); value != "" {
-		ov.SummaryFile = value
+		ov.SummaryFiles = ParseTargetsList(value)
 	}
 
 	if value := lookupEnv(envDetectorsKeys); value != "" {
 		ov.Detectors = ParseDetectors(value)
 	}
 
+	if value := lookupEnv(envDisabledDetectorsKeys); value != "" {
+		ov.DisabledDetectors = ParseDetectors(value)
+	}
+
+	if value := lookupEnv(envSandboxRootKeys); value != "" {
+		ov.SandboxRoot = value
+	}
+
+	if value := lookupEnv(envResolveKeys); value != "" {
+		ov.Resolve = ParseTargetsList(value)
+	}
+
+	if value := lookupEnv(envCACertsKeys); value != "" {
+		ov.CACerts = ParseTargetsList(value)
+	}
+
+	if value := lookupEnv(envMaxIdleConnsKeys); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			ov.MaxIdleConns = parsed
+		}
+	}
+
+	if value := lookupEnv(envMaxConnsPerHostKeys); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			ov.MaxConnsPerHost = parsed
+		}
+	}
+
 	return ov
 }
 
+// XDGOutputDir returns the per-user output directory used with UseXDG:
+// $XDG_DATA_HOME/wphunter, or $HOME/.local/share/wphunter when XDG_DATA_HOME is unset.
+// Relative XDG_DATA_HOME values are ignored, as the XDG spec requires.
+func XDGOutputDir() (string, error) {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dataHome) {
+		return filepath.Join(dataHome, "wphunter"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve XDG output directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "wphunter"), nil
+}
+
+// ParseResolveOverrides parses "host:ip" entries into a lowercase host to IP map.
+// IPv6 addresses may be given bare or in brackets.
+func ParseResolveOverrides(specs []string) (map[string]string, error) {
+	overrides := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		host, ip, ok := strings.Cut(strings.TrimSpace(spec), ":")
+		ip = strings.TrimSuffix(strings.TrimPrefix(ip, "["), "]")
+		if !ok || host == "" || net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("invalid resolve override %q: expected host:ip", spec)
+		}
+		overrides[strings.ToLower(host)] = ip
+	}
+	return overrides, nil
+}
+
 // ParseTargetsList turns comma or newline separated input into individual targets.
 func ParseTargetsList(input string) []string {
 	return splitOnDelimiters(input, []rune{',', '\n', '\r'})
 }
 
+// SupportedFormats lists the output formats accepted in Formats. json and csv are
+// produced by wpprobe; every format except json also renders detector findings.
+var SupportedFormats = []string{"json", "csv", "yaml", "html", "sarif", "cyclonedx"}
+
+func isConfidenceKey(key string) bool {
+	for _, known := range detector.ConfidenceKeys {
+		if key == known {
+			return true
+		}
+	}
+	return false
+}
+
+func isSupportedFormat(format string) bool {
+	format = strings.ToLower(strings.TrimSpace(format))
+	for _, supported := range SupportedFormats {
+		if format == supported {
+			return true
+		}
+	}
+	return false
+}
+
 // ParseFormats splits comma separated format strings.
 func ParseFormats(input string) []string {
 	return splitOnDelimiters(input, []rune{',', '\n', '\r', ' '})
@@ -312,10 +721,44 @@ func cleanList(values []string) []string {
 	return out
 }
 
-func readTargetsFile(path string) ([]string, error) {
+// unionList appends the values of extra missing from base, comparing case-insensitively.
+func unionList(base, extra []string) []string {
+	out := append([]string(nil), base...)
+	for _, v := range extra {
+		found := false
+		for _, existing := range out {
+			if strings.EqualFold(existing, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// readDetectorsFile reads detector names one per line, with the same path checks and
+// blank-line and # comment handling as a lines targets file.
+func readDetectorsFile(path string, allowSystemPaths bool) ([]string, error) {
+	names, err := readTargetsFile(path, targetsFileOptions{Format: TargetsFileFormatLines, AllowSystemPaths: allowSystemPaths})
+	if err != nil {
+		return nil, fmt.Errorf("read detectors file: %w", err)
+	}
+	return names, nil
+}
+
+func readTargetsFile(path string, opts targetsFileOptions) ([]string, error) {
+	contents, err := loadTargetsFile(path, opts)
+	return contents.Targets, err
+}
+
+// loadTargetsFile validates path and parses it according to opts.Format.
+func loadTargetsFile(path string, opts targetsFileOptions) (targetsFileContents, error) {
 	// Validate path to prevent path traversal attacks
 	if err := validateFilePath(path); err != nil {
-		return nil, err
+		return targetsFileContents{}, err
 	}
 
 	cleanedPath := filepath.Clean(path)
@@ -323,40 +766,39 @@ func readTargetsFile(path string) ([]string, error) {
 	// Check if cleaned path still contains .. components before making absolute
 	// This catches cases where .. cannot be resolved (traversal beyond root)
 	if strings.Contains(cleanedPath, "..") {
-		return nil, fmt.Errorf("path traversal detected: %s", path)
+		return targetsFileContents{}, fmt.Errorf("path traversal detected: %s", path)
 	}
 
 	absPath, err := filepath.Abs(cleanedPath)
 	if err != nil {
-		return nil, fmt.Errorf("invalid path: %w", err)
+		return targetsFileContents{}, fmt.Errorf("invalid path: %w", err)
 	}
 
 	// Additional safety: check for common system files that shouldn't be accessed
-	if isSystemFile(absPath) {
-		return nil, fmt.Errorf("access to system file denied: %s", path)
+	if !opts.AllowSystemPaths && isSystemFile(absPath) {
+		return targetsFileContents{}, fmt.Errorf("access to system file denied: %s (use --allow-system-paths to override)", path)
 	}
 
 	file, err := os.Open(absPath)
 	if err != nil {
-		return nil, err
+		return targetsFileContents{}, err
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	var targets []string
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		targets = append(targets, line)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
+	switch strings.ToLower(opts.Format) {
+	case "", TargetsFileFormatLines:
+		targets, err := parseTargetsLines(file)
+		return targetsFileContents{Targets: targets}, err
+	case TargetsFileFormatCSV:
+		return parseTargetsCSV(file, opts.column())
+	case TargetsFileFormatJSON:
+		targets, err := parseTargetsJSON(file, opts.column())
+		return targetsFileContents{Targets: targets}, err
+	case TargetsFileFormatJSONL:
+		return parseTargetsJSONL(file, opts.column())
+	default:
+		return targetsFileContents{}, fmt.Errorf("unsupported targets file format %q (expected %s, %s, %s, or %s)", opts.Format, TargetsFileFormatLines, TargetsFileFormatCSV, TargetsFileFormatJSON, TargetsFileFormatJSONL)
 	}
-
-	return targets, nil
 }
 
 // validateFilePath checks for common path traversal and security issues.
@@ -378,6 +820,32 @@ func validateFilePath(path string) error {
 	return nil
 }
 
+// EnsureWithinRoot returns an error when path, once made absolute and cleaned,
+// resolves outside root. It applies the same traversal protection used for
+// targets files to the paths wphunter writes to.
+func EnsureWithinRoot(root, path string) error {
+	if err := validateFilePath(path); err != nil {
+		return err
+	}
+
+	absRoot, err := filepath.Abs(filepath.Clean(root))
+	if err != nil {
+		return fmt.Errorf("invalid sandbox root: %w", err)
+	}
+
+	absPath, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path %s escapes sandbox root %s", path, root)
+	}
+
+	return nil
+}
+
 // isSystemFile checks if the path points to a sensitive system file.
 func isSystemFile(absPath string) bool {
 	systemPaths := []string{
@@ -417,6 +885,7 @@ func lookupEnv(keys []string) string {
 }
 
 // targetList enables YAML fields that can be specified as a scalar or sequence.
+// It backs both targets and summaryFile.
 type targetList []string
 
 func (t *targetList) UnmarshalYAML(value *yaml.Node) error {
@@ -434,3 +903,48 @@ func (t *targetList) UnmarshalYAML(value *yaml.Node) error {
 	}
 	return nil
 }
+
+// ThreadsAuto is the threads value that sizes concurrency from the host CPU count.
+const ThreadsAuto = "auto"
+
+// AutoThreads returns the thread count used for ThreadsAuto: four per CPU, capped at MaxThreads.
+func AutoThreads() int {
+	threads := runtime.NumCPU() * 4
+	if threads > MaxThreads {
+		threads = MaxThreads
+	}
+	if threads < 1 {
+		threads = 1
+	}
+	return threads
+}
+
+// ParseThreads converts a threads value into a count, resolving ThreadsAuto.
+// Range checks are left to Validate.
+func ParseThreads(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, ThreadsAuto) {
+		return AutoThreads(), nil
+	}
+
+	threads, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid threads value %q (expected a number or %q)", value, ThreadsAuto)
+	}
+	return threads, nil
+}
+
+// threadsValue lets the YAML threads field hold either an integer or "auto".
+type threadsValue int
+
+func (t *threadsValue) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("unsupported YAML type for threads")
+	}
+	threads, err := ParseThreads(value.Value)
+	if err != nil {
+		return err
+	}
+	*t = threadsValue(threads)
+	return nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -147,7 +148,7 @@ func TestReadTargetsFile_PathTraversal(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			targets, err := readTargetsFile(tt.path)
+			targets, err := readTargetsFile(tt.path, targetsFileOptions{})
 			if tt.shouldFail {
 				if err == nil {
 					t.Errorf("%s: expected error for path traversal, got targets: %v", tt.description, targets)
@@ -182,7 +183,7 @@ func TestReadTargetsFile_SymbolicLinks(t *testing.T) {
 	}
 
 	// Test that symlink works (should resolve and read the file)
-	targets, err := readTargetsFile(symlinkPath)
+	targets, err := readTargetsFile(symlinkPath, targetsFileOptions{})
 	if err != nil {
 		t.Fatalf("reading symlink should succeed: %v", err)
 	}
@@ -197,7 +198,7 @@ func TestReadTargetsFile_SymbolicLinks(t *testing.T) {
 	}
 
 	// Test that broken symlink fails appropriately
-	_, err = readTargetsFile(brokenSymlink)
+	_, err = readTargetsFile(brokenSymlink, targetsFileOptions{})
 	if err == nil {
 		t.Error("reading broken symlink should fail")
 	}
@@ -217,7 +218,7 @@ func TestReadTargetsFile_SymbolicLinks(t *testing.T) {
 
 	// Test that external symlink works (filepath.Clean doesn't prevent symlink resolution)
 	// This is expected behavior - symlinks can point outside, but filepath.Clean sanitizes the path string
-	targets, err = readTargetsFile(externalSymlink)
+	targets, err = readTargetsFile(externalSymlink, targetsFileOptions{})
 	if err != nil {
 		t.Logf("note: external symlink read failed (may be expected): %v", err)
 	} else if len(targets) != 1 {
@@ -278,7 +279,7 @@ func TestReadTargetsFile_MalformedPaths(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			targets, err := readTargetsFile(tt.path)
+			targets, err := readTargetsFile(tt.path, targetsFileOptions{})
 			if tt.shouldFail {
 				if err == nil {
 					t.Errorf("%s: expected error for malformed path, got targets: %v", tt.description, targets)
@@ -365,7 +366,7 @@ func TestReadTargetsFile_EdgeCases(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			path := tt.setup()
-			targets, err := readTargetsFile(path)
+			targets, err := readTargetsFile(path, targetsFileOptions{})
 			if tt.shouldFail {
 				if err == nil {
 					t.Errorf("%s: expected error, got targets: %v", tt.description, targets)
@@ -388,3 +389,539 @@ func TestReadTargetsFile_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureWithinRoot(t *testing.T) {
+	root := t.TempDir()
+
+	tests := []struct {
+		name       string
+		path       string
+		shouldFail bool
+	}{
+		{name: "root itself", path: root},
+		{name: "nested output dir", path: filepath.Join(root, "scan-results")},
+		{name: "dot dot that stays inside", path: filepath.Join(root, "a", "..", "summary.json")},
+		{name: "escaping parent", path: filepath.Join(root, "..", "outside"), shouldFail: true},
+		{name: "sibling with shared prefix", path: root + "-evil", shouldFail: true},
+		{name: "absolute elsewhere", path: "/tmp/elsewhere", shouldFail: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EnsureWithinRoot(root, tt.path)
+			if tt.shouldFail && err == nil {
+				t.Fatalf("expected %s to be rejected", tt.path)
+			}
+			if !tt.shouldFail && err != nil {
+				t.Fatalf("expected %s to be allowed: %v", tt.path, err)
+			}
+		})
+	}
+}
+
+func TestValidateSandboxRoot(t *testing.T) {
+	root := t.TempDir()
+	cfg := DefaultRuntimeConfig()
+	cfg.Targets = []string{"https://one.test"}
+	cfg.SandboxRoot = root
+
+	cfg.OutputDir = filepath.Join(root, "scan-results")
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("in-root output dir should validate: %v", err)
+	}
+
+	cfg.SummaryFiles = []string{filepath.Join(root, "summary.json"), filepath.Join(root, "..", "summary.json")}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "escapes sandbox root") {
+		t.Fatalf("expected escaping summary file to be rejected, got %v", err)
+	}
+}
+
+func TestLoaderLoadConfidenceCalibration(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "wphunter.config.yml")
+	if err := os.WriteFile(configPath, []byte("targets: https://one.test\nconfidence:\n  version_generator: 0.9\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Loader{ConfigPath: configPath}.Load(Overrides{})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	if cfg.Confidence["version_generator"] != 0.9 {
+		t.Fatalf("expected confidence override, got %#v", cfg.Confidence)
+	}
+
+	cfg.Confidence["version_generator"] = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected out-of-range confidence to be rejected")
+	}
+
+	cfg.Confidence = map[string]float64{"version_generater": 0.9}
+	err = cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "version_generater") || !strings.Contains(err.Error(), "version_generator") {
+		t.Fatalf("expected the misspelled key to be rejected with the valid keys listed, got %v", err)
+	}
+}
+
+func TestLoaderUseXDGOutputDir(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	missing := filepath.Join(t.TempDir(), "missing.yml")
+	useXDG := true
+
+	cfg, err := Loader{ConfigPath: missing}.Load(Overrides{UseXDG: &useXDG})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if want := filepath.Join(dataHome, "wphunter"); cfg.OutputDir != want {
+		t.Fatalf("OutputDir = %q, want %q", cfg.OutputDir, want)
+	}
+
+	cfg, err = Loader{ConfigPath: missing}.Load(Overrides{UseXDG: &useXDG, OutputDir: "explicit"})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.OutputDir != "explicit" {
+		t.Fatalf("expected an explicit output dir to win over XDG, got %q", cfg.OutputDir)
+	}
+
+	cfg, err = Loader{ConfigPath: missing}.Load(Overrides{})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.OutputDir != "scan-results" {
+		t.Fatalf("expected the default output dir without the opt-in, got %q", cfg.OutputDir)
+	}
+}
+
+func TestLoaderAllowSystemPaths(t *testing.T) {
+	file, err := os.CreateTemp("/dev/shm", "wphunter-targets-*.txt")
+	if err != nil {
+		t.Skipf("/dev/shm not available: %v", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString("https://shm.test\n"); err != nil {
+		t.Fatalf("write targets: %v", err)
+	}
+	file.Close()
+
+	over := Overrides{TargetsFile: file.Name()}
+
+	if _, err := (Loader{}).Load(over); err == nil || !strings.Contains(err.Error(), "access to system file denied") {
+		t.Fatalf("expected /dev/shm to be denied by default, got %v", err)
+	}
+
+	cfg, err := Loader{AllowSystemPaths: true}.Load(over)
+	if err != nil {
+		t.Fatalf("expected /dev/shm to be readable with AllowSystemPaths: %v", err)
+	}
+
+	if len(cfg.Targets) != 1 || cfg.Targets[0] != "https://shm.test" {
+		t.Fatalf("unexpected targets: %v", cfg.Targets)
+	}
+
+	if _, err := (Loader{AllowSystemPaths: true}).Load(Overrides{TargetsFile: file.Name() + "\x00"}); err == nil {
+		t.Fatal("null-byte check must still apply when system paths are allowed")
+	}
+}
+
+func TestReadTargetsFileCSV(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cmdb.csv")
+	content := "name,Site URL,owner\nshop,https://shop.test,alice\nblog, https://blog.test ,bob\nretired,,carol\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+
+	targets, err := readTargetsFile(path, targetsFileOptions{Format: TargetsFileFormatCSV, Column: "site url"})
+	if err != nil {
+		t.Fatalf("read csv targets: %v", err)
+	}
+
+	expected := []string{"https://shop.test", "https://blog.test"}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Fatalf("expected %v, got %v", expected, targets)
+	}
+
+	if _, err := readTargetsFile(path, targetsFileOptions{Format: TargetsFileFormatCSV}); err == nil {
+		t.Fatal("expected error for missing default url column")
+	}
+}
+
+func TestLoadTargetsFileCSVExpectedVersions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "inventory.csv")
+	content := "url,ExpectedVersion\nhttps://one.test,6.5.1\nhttps://two.test,\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+
+	contents, err := loadTargetsFile(path, targetsFileOptions{Format: TargetsFileFormatCSV})
+	if err != nil {
+		t.Fatalf("read csv targets: %v", err)
+	}
+
+	if !reflect.DeepEqual(contents.Targets, []string{"https://one.test", "https://two.test"}) {
+		t.Fatalf("unexpected targets: %v", contents.Targets)
+	}
+	if !reflect.DeepEqual(contents.ExpectedVersions, map[string]string{"https://one.test": "6.5.1"}) {
+		t.Fatalf("unexpected expected versions: %v", contents.ExpectedVersions)
+	}
+}
+
+func TestApplyTargetAffixes(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		prefix, suffix string
+		want           string
+	}{
+		{name: "bare host gets scheme and suffix", target: "example.com", suffix: "/wp/", want: "https://example.com/wp/"},
+		{name: "prefix and suffix", target: "example.com", prefix: "www.", suffix: "wp", want: "https://www.example.com/wp"},
+		{name: "existing scheme is kept", target: "http://example.com/", suffix: "/wp/", want: "http://example.com/wp/"},
+		{name: "scheme in prefix replaces scheme", target: "https://example.com", prefix: "http://", want: "http://example.com"},
+		{name: "trailing slashes are not duplicated", target: "example.com///", suffix: "//wp//", want: "https://example.com/wp/"},
+		{name: "suffix already present", target: "https://example.com/wp/", suffix: "/wp/", want: "https://example.com/wp/"},
+		{name: "prefix already present", target: "https://www.example.com", prefix: "www.", want: "https://www.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ApplyTargetAffixes(tt.target, tt.prefix, tt.suffix)
+			if got != tt.want {
+				t.Fatalf("ApplyTargetAffixes(%q, %q, %q) = %q, want %q", tt.target, tt.prefix, tt.suffix, got, tt.want)
+			}
+			if again := ApplyTargetAffixes(got, tt.prefix, tt.suffix); again != got {
+				t.Fatalf("expected affixes to be idempotent, second pass gave %q", again)
+			}
+		})
+	}
+}
+
+func TestLoaderAppliesTargetAffixes(t *testing.T) {
+	loader := Loader{ConfigPath: filepath.Join(t.TempDir(), "missing.yml")}
+	cfg, err := loader.Load(Overrides{
+		Targets:      []string{"example.com", "https://blog.test/"},
+		TargetPrefix: "www.",
+		TargetSuffix: "/wp/",
+	})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	expected := []string{"https://www.example.com/wp/", "https://www.blog.test/wp/"}
+	if !reflect.DeepEqual(cfg.Targets, expected) {
+		t.Fatalf("expected %v, got %v", expected, cfg.Targets)
+	}
+}
+
+func TestApplyTargetAffixesMergesCollidingTargets(t *testing.T) {
+	cfg := RuntimeConfig{
+		Targets:          []string{"a.test", "https://a.test", "b.test"},
+		ExpectedVersions: map[string]string{"a.test": "6.4", "https://a.test": "6.5"},
+		TargetOptions:    map[string]TargetOptions{"a.test": {Detectors: []string{"version"}}, "https://a.test": {Detectors: []string{"vcs"}}},
+		TargetPrefix:     "https://",
+	}
+	cfg.applyTargetAffixes()
+
+	if expected := []string{"https://a.test", "https://b.test"}; !reflect.DeepEqual(cfg.Targets, expected) {
+		t.Fatalf("expected %v, got %v", expected, cfg.Targets)
+	}
+	if expected := map[string]string{"https://a.test": "6.4"}; !reflect.DeepEqual(cfg.ExpectedVersions, expected) {
+		t.Fatalf("expected the first target's version to win, got %v", cfg.ExpectedVersions)
+	}
+	if got := cfg.TargetOptions["https://a.test"].Detectors; len(cfg.TargetOptions) != 1 || !reflect.DeepEqual(got, []string{"version"}) {
+		t.Fatalf("expected the first target's options to win, got %v", cfg.TargetOptions)
+	}
+}
+
+func TestReadTargetsFileJSON(t *testing.T) {
+	dir := t.TempDir()
+
+	arrayPath := filepath.Join(dir, "targets.json")
+	if err := os.WriteFile(arrayPath, []byte(`["https://one.test", " ", "# skipped", "https://two.test"]`), 0o600); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+
+	targets, err := readTargetsFile(arrayPath, targetsFileOptions{Format: TargetsFileFormatJSON})
+	if err != nil {
+		t.Fatalf("read json targets: %v", err)
+	}
+
+	expected := []string{"https://one.test", "https://two.test"}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Fatalf("expected %v, got %v", expected, targets)
+	}
+
+	objectPath := filepath.Join(dir, "objects.json")
+	if err := os.WriteFile(objectPath, []byte(`[{"host":"https://three.test"},{"other":"x"}]`), 0o600); err != nil {
+		t.Fatalf("write json objects: %v", err)
+	}
+
+	targets, err = readTargetsFile(objectPath, targetsFileOptions{Format: TargetsFileFormatJSON, Column: "host"})
+	if err != nil {
+		t.Fatalf("read json object targets: %v", err)
+	}
+
+	if !reflect.DeepEqual(targets, []string{"https://three.test"}) {
+		t.Fatalf("unexpected targets from objects: %v", targets)
+	}
+}
+
+func TestLoaderTargetsJSONLPerTargetOptions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "targets.jsonl")
+	lines := `{"url":"https://one.test","mode":"stealthy","detectors":["version"]}
+# comment
+{"url":"https://two.test"}
+`
+	if err := os.WriteFile(path, []byte(lines), 0o600); err != nil {
+		t.Fatalf("write jsonl: %v", err)
+	}
+
+	loader := Loader{}
+	cfg, err := loader.Load(Overrides{TargetsFile: path, TargetsFileFormat: TargetsFileFormatJSONL})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.Targets, []string{"https://one.test", "https://two.test"}) {
+		t.Fatalf("unexpected targets: %v", cfg.Targets)
+	}
+	expected := map[string]TargetOptions{"https://one.test": {Mode: "stealthy", Detectors: []string{"version"}}}
+	if !reflect.DeepEqual(cfg.TargetOptions, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, cfg.TargetOptions)
+	}
+
+	if err := os.WriteFile(path, []byte("not json\n"), 0o600); err != nil {
+		t.Fatalf("write jsonl: %v", err)
+	}
+	if _, err := loader.Load(Overrides{TargetsFile: path, TargetsFileFormat: TargetsFileFormatJSONL}); err == nil {
+		t.Fatal("expected error for a malformed jsonl line")
+	}
+}
+
+func TestLoaderDetectorsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "detectors.txt")
+	if err := os.WriteFile(path, []byte("# curated\nversion\n  hosting  \n\n"), 0o600); err != nil {
+		t.Fatalf("write detectors file: %v", err)
+	}
+
+	loader := Loader{}
+	cfg, err := loader.Load(Overrides{Targets: []string{"https://example.com"}, DetectorsFile: path, Detectors: []string{"php"}})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	expected := []string{"version", "hosting", "php"}
+	if !reflect.DeepEqual(cfg.Detectors, expected) {
+		t.Fatalf("expected %v, got %v", expected, cfg.Detectors)
+	}
+	if cfg.Sources["detectors"] != SourceFlag {
+		t.Fatalf("expected detectors sourced from flags, got %q", cfg.Sources["detectors"])
+	}
+
+	if _, err := loader.Load(Overrides{Targets: []string{"https://example.com"}, DetectorsFile: filepath.Join(dir, "missing.txt")}); err == nil {
+		t.Fatal("expected error for a missing detectors file")
+	}
+}
+
+func TestLoaderTargetsFileFormatFromOverrides(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "targets.csv")
+	if err := os.WriteFile(csvPath, []byte("url\nhttps://csv.test\n"), 0o600); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+
+	configPath := filepath.Join(dir, "wphunter.config.yml")
+	if err := os.WriteFile(configPath, []byte("targetsFile: "+csvPath+"\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	loader := Loader{ConfigPath: configPath}
+	cfg, err := loader.Load(Overrides{TargetsFileFormat: TargetsFileFormatCSV})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.Targets, []string{"https://csv.test"}) {
+		t.Fatalf("unexpected targets: %v", cfg.Targets)
+	}
+
+	if _, err := loader.Load(Overrides{TargetsFileFormat: "xml"}); err == nil {
+		t.Fatal("expected error for unsupported targets file format")
+	}
+}
+
+func TestParseThreadsAuto(t *testing.T) {
+	threads, err := ParseThreads("auto")
+	if err != nil {
+		t.Fatalf("parse auto: %v", err)
+	}
+	if threads < 1 || threads > MaxThreads {
+		t.Fatalf("auto resolved to %d, want within [1, %d]", threads, MaxThreads)
+	}
+
+	if threads, err := ParseThreads("12"); err != nil || threads != 12 {
+		t.Fatalf("expected integer threads to parse, got %d, %v", threads, err)
+	}
+
+	if _, err := ParseThreads("many"); err == nil {
+		t.Fatal("expected invalid threads value to be rejected")
+	}
+}
+
+func TestLoaderThreadsAutoFromFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "wphunter.config.yml")
+	if err := os.WriteFile(configPath, []byte("targets: https://one.test\nthreads: auto\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Loader{ConfigPath: configPath}.Load(Overrides{})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	if cfg.Threads != AutoThreads() {
+		t.Fatalf("expected threads %d, got %d", AutoThreads(), cfg.Threads)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("auto threads failed validation: %v", err)
+	}
+}
+
+func TestValidateFormats(t *testing.T) {
+	cfg := DefaultRuntimeConfig()
+	cfg.Targets = []string{"https://one.test"}
+
+	cfg.Formats = []string{"json", "CSV", "yaml", "html", "sarif"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("known formats should validate: %v", err)
+	}
+
+	cfg.Formats = []string{"json", "jsonn"}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), `unsupported output format "jsonn"`) || !strings.Contains(err.Error(), "json, csv, yaml, html, sarif") {
+		t.Fatalf("expected unknown format to be rejected with the valid list, got %v", err)
+	}
+}
+
+func TestParseResolveOverrides(t *testing.T) {
+	overrides, err := ParseResolveOverrides([]string{"Shop.test:203.0.113.7", "v6.test:[2001:db8::1]"})
+	if err != nil {
+		t.Fatalf("parse overrides: %v", err)
+	}
+	if overrides["shop.test"] != "203.0.113.7" || overrides["v6.test"] != "2001:db8::1" {
+		t.Fatalf("unexpected overrides: %v", overrides)
+	}
+
+	for _, spec := range []string{"shop.test", "shop.test:not-an-ip", ":203.0.113.7"} {
+		if _, err := ParseResolveOverrides([]string{spec}); err == nil {
+			t.Fatalf("expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestValidateRejectsInvalidCACert(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("write ca: %v", err)
+	}
+
+	cfg := DefaultRuntimeConfig()
+	cfg.Targets = []string{"https://one.test"}
+	cfg.CACerts = []string{path}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "no valid PEM certificates") {
+		t.Fatalf("expected invalid PEM to be rejected, got %v", err)
+	}
+
+	cfg.CACerts = []string{filepath.Join(t.TempDir(), "missing.pem")}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected a missing CA file to be rejected")
+	}
+}
+
+func TestLoaderJSON5Config(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "wphunter.config.json5")
+	content := `{
+  // Comments and trailing commas are allowed.
+  targets: ["https://one.test", "https://two.test"],
+  mode: "stealthy",
+  threads: 12,
+  formats: ["json", "sarif",],
+  /* Detectors to run */
+  detectors: ["version", "rest"],
+  confidence: {version_generator: 0.7},
+}
+`
+	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Loader{ConfigPath: configPath}.Load(Overrides{Mode: "hybrid"})
+	if err != nil {
+		t.Fatalf("load json5 config: %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.Targets, []string{"https://one.test", "https://two.test"}) {
+		t.Fatalf("unexpected targets: %v", cfg.Targets)
+	}
+	if cfg.Threads != 12 || !reflect.DeepEqual(cfg.Formats, []string{"json", "sarif"}) || !reflect.DeepEqual(cfg.Detectors, []string{"version", "rest"}) {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if cfg.Confidence["version_generator"] != 0.7 {
+		t.Fatalf("unexpected confidence: %v", cfg.Confidence)
+	}
+	if cfg.Mode != "hybrid" {
+		t.Fatalf("expected flag override to win over the config file, got mode %q", cfg.Mode)
+	}
+}
+
+func TestLoaderRecordsConfigSources(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "wphunter.config.yml")
+	content := "targets:\n  - https://file.test\nthreads: 4\nmode: stealthy\n"
+	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv(envThreadsKeys[0], "12")
+
+	cfg, err := Loader{ConfigPath: configPath}.Load(Overrides{Formats: []string{"json"}})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	want := map[string]string{
+		"targets":   SourceFile,
+		"mode":      SourceFile,
+		"threads":   SourceEnv,
+		"formats":   SourceFlag,
+		"outputDir": SourceDefault,
+		"detectors": SourceDefault,
+	}
+	for key, source := range want {
+		if cfg.Sources[key] != source {
+			t.Fatalf("source of %s = %q, want %q (all: %v)", key, cfg.Sources[key], source, cfg.Sources)
+		}
+	}
+	if cfg.Threads != 12 {
+		t.Fatalf("expected the env threads value to win, got %d", cfg.Threads)
+	}
+}
+
+func TestLoaderUnionsDisabledDetectorsAcrossLayers(t *testing.T) {
+	t.Setenv(envDisabledDetectorsKeys[0], "bruteforce")
+
+	cfg, err := Loader{}.Load(Overrides{DisabledDetectors: []string{"foo", "Bruteforce"}})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	if want := []string{"bruteforce", "foo"}; !reflect.DeepEqual(cfg.DisabledDetectors, want) {
+		t.Fatalf("expected the flag to add to the env policy, got %v, want %v", cfg.DisabledDetectors, want)
+	}
+}
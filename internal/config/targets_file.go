@@ -0,0 +1,291 @@
+package config
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Supported targets file formats.
+const (
+	TargetsFileFormatLines = "lines"
+	TargetsFileFormatCSV   = "csv"
+	TargetsFileFormatJSON  = "json"
+	// TargetsFileFormatJSONL reads one JSON object per line, with optional per-target
+	// mode and detectors; see TargetOptions.
+	TargetsFileFormatJSONL = "jsonl"
+	// DefaultTargetsColumn is the CSV column / JSON field read when none is configured.
+	DefaultTargetsColumn = "url"
+	// ExpectedVersionColumn is the optional CSV column holding each target's expected
+	// WordPress version, used to flag version drift.
+	ExpectedVersionColumn = "expectedVersion"
+)
+
+// targetsFileContents is what a targets file yields: the targets and, for CSV
+// inventories, the expected WordPress version per target. JSON Lines files may also
+// carry per-target options.
+type targetsFileContents struct {
+	Targets          []string
+	ExpectedVersions map[string]string
+	TargetOptions    map[string]TargetOptions
+}
+
+// TargetOptions override global settings for a single target. Empty fields inherit
+// the merged configuration.
+type TargetOptions struct {
+	Mode      string   `json:"mode,omitempty"`
+	Detectors []string `json:"detectors,omitempty"`
+}
+
+func (o targetsFileOptions) column() string {
+	if o.Column == "" {
+		return DefaultTargetsColumn
+	}
+	return o.Column
+}
+
+// cleanTarget applies the cleaning rules shared by every targets file format:
+// surrounding whitespace is trimmed and empty values or # comments are dropped.
+func cleanTarget(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.HasPrefix(value, "#") {
+		return "", false
+	}
+	return value, true
+}
+
+func parseTargetsLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	var targets []string
+	for scanner.Scan() {
+		if target, ok := cleanTarget(scanner.Text()); ok {
+			targets = append(targets, target)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// parseTargetsCSV reads the named column from a CSV file with a header row. When the
+// header also has an ExpectedVersionColumn, non-empty values are kept per target.
+func parseTargetsCSV(r io.Reader, column string) (targetsFileContents, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var contents targetsFileContents
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return contents, nil
+	}
+	if err != nil {
+		return contents, fmt.Errorf("read targets csv header: %w", err)
+	}
+
+	index, expectedIndex := -1, -1
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if index < 0 && strings.EqualFold(name, column) {
+			index = i
+		}
+		if expectedIndex < 0 && strings.EqualFold(name, ExpectedVersionColumn) {
+			expectedIndex = i
+		}
+	}
+	if index < 0 {
+		return contents, fmt.Errorf("targets csv has no %q column (use --targets-csv-column)", column)
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return contents, fmt.Errorf("read targets csv: %w", err)
+		}
+		if index >= len(record) {
+			continue
+		}
+		target, ok := cleanTarget(record[index])
+		if !ok {
+			continue
+		}
+		contents.Targets = append(contents.Targets, target)
+
+		if expectedIndex >= 0 && expectedIndex < len(record) {
+			if expected := strings.TrimSpace(record[expectedIndex]); expected != "" {
+				if contents.ExpectedVersions == nil {
+					contents.ExpectedVersions = map[string]string{}
+				}
+				contents.ExpectedVersions[target] = expected
+			}
+		}
+	}
+
+	return contents, nil
+}
+
+// parseTargetsJSON accepts an array of strings or an array of objects, in
+// which case the target is read from the named field.
+func parseTargetsJSON(r io.Reader, field string) ([]string, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("parse targets json: expected an array: %w", err)
+	}
+
+	var targets []string
+	for i, item := range raw {
+		var value string
+		if err := json.Unmarshal(item, &value); err != nil {
+			var obj map[string]interface{}
+			if err := json.Unmarshal(item, &obj); err != nil {
+				return nil, fmt.Errorf("parse targets json: entry %d must be a string or object", i)
+			}
+			fieldValue, ok := obj[field].(string)
+			if !ok {
+				continue
+			}
+			value = fieldValue
+		}
+		if target, ok := cleanTarget(value); ok {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets, nil
+}
+
+// parseTargetsJSONL reads one object per line, taking the target from the named field
+// and optional "mode" and "detectors" overrides. Blank lines and # comments are skipped.
+func parseTargetsJSONL(r io.Reader, field string) (targetsFileContents, error) {
+	var contents targetsFileContents
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		var entry map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return contents, fmt.Errorf("parse targets jsonl: line %d must be a JSON object: %w", line, err)
+		}
+		var value string
+		if raw, ok := entry[field]; ok {
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return contents, fmt.Errorf("parse targets jsonl: line %d: %q must be a string", line, field)
+			}
+		}
+		target, ok := cleanTarget(value)
+		if !ok {
+			continue
+		}
+
+		var opts TargetOptions
+		if err := json.Unmarshal([]byte(text), &opts); err != nil {
+			return contents, fmt.Errorf("parse targets jsonl: line %d: %w", line, err)
+		}
+		opts.Mode = strings.TrimSpace(opts.Mode)
+		opts.Detectors = cleanList(opts.Detectors)
+
+		contents.Targets = append(contents.Targets, target)
+		if opts.Mode != "" || len(opts.Detectors) > 0 {
+			if contents.TargetOptions == nil {
+				contents.TargetOptions = map[string]TargetOptions{}
+			}
+			contents.TargetOptions[target] = opts
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return contents, err
+	}
+	return contents, nil
+}
+
+// ApplyTargetAffixes normalizes target to an absolute URL (https:// when it has no
+// scheme) and then adds prefix in front of the host and suffix after the path. A
+// prefix carrying a scheme, such as "http://www.", replaces the target's scheme
+// instead of adding a second one. Affixes the target already has are not added
+// again, and the suffix is joined with a single slash, so applying the same
+// affixes twice yields the same URL.
+func ApplyTargetAffixes(target, prefix, suffix string) string {
+	scheme, rest := "https", target
+	if i := strings.Index(target, "://"); i >= 0 {
+		scheme, rest = target[:i], target[i+len("://"):]
+	}
+
+	if i := strings.Index(prefix, "://"); i >= 0 {
+		scheme, prefix = prefix[:i], prefix[i+len("://"):]
+	}
+	if prefix != "" && !strings.HasPrefix(rest, prefix) {
+		rest = prefix + rest
+	}
+
+	if suffix != "" {
+		path := strings.Trim(suffix, "/")
+		base := strings.TrimRight(rest, "/")
+		if path != "" && !strings.HasSuffix(base, "/"+path) {
+			base += "/" + path
+		}
+		if strings.HasSuffix(suffix, "/") {
+			base += "/"
+		}
+		rest = base
+	}
+
+	return scheme + "://" + rest
+}
+
+// applyTargetAffixes rewrites the merged targets with the configured prefix and
+// suffix, keeping expected versions and per-target options keyed by the rewritten
+// target. Targets that become equal are merged; the first one keeps its place and
+// its entries.
+func (c *RuntimeConfig) applyTargetAffixes() {
+	if c.TargetPrefix == "" && c.TargetSuffix == "" {
+		return
+	}
+
+	targets := make([]string, 0, len(c.Targets))
+	var expectedVersions map[string]string
+	if c.ExpectedVersions != nil {
+		expectedVersions = map[string]string{}
+	}
+	var targetOptions map[string]TargetOptions
+	if c.TargetOptions != nil {
+		targetOptions = map[string]TargetOptions{}
+	}
+
+	seen := map[string]struct{}{}
+	for _, target := range c.Targets {
+		transformed := ApplyTargetAffixes(target, c.TargetPrefix, c.TargetSuffix)
+		if _, dup := seen[transformed]; dup {
+			continue
+		}
+		seen[transformed] = struct{}{}
+		targets = append(targets, transformed)
+
+		if expected, ok := c.ExpectedVersions[target]; ok {
+			expectedVersions[transformed] = expected
+		}
+		if opts, ok := c.TargetOptions[target]; ok {
+			targetOptions[transformed] = opts
+		}
+	}
+
+	c.Targets = targets
+	c.ExpectedVersions = expectedVersions
+	c.TargetOptions = targetOptions
+}
@@ -0,0 +1,102 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// AdminToolConfidence is reported when a probed page carries a tool-specific login marker.
+const AdminToolConfidence = 0.9
+
+var (
+	phpMyAdminMarker = regexp.MustCompile(`(?i)<title>[^<]*phpmyadmin|name="pma_username"`)
+	adminerMarker    = regexp.MustCompile(`(?i)<title>[^<]*adminer|name="auth\[driver\]"`)
+)
+
+// adminToolProbe describes a database admin tool location and its page marker.
+type adminToolProbe struct {
+	Tool   string
+	Path   string
+	Marker *regexp.Regexp
+}
+
+var adminToolProbes = []adminToolProbe{
+	{Tool: "phpMyAdmin", Path: "/phpmyadmin/", Marker: phpMyAdminMarker},
+	{Tool: "phpMyAdmin", Path: "/pma/", Marker: phpMyAdminMarker},
+	{Tool: "Adminer", Path: "/adminer.php", Marker: adminerMarker},
+}
+
+// AdminToolsDetector looks for publicly reachable database administration tools.
+type AdminToolsDetector struct {
+	client       *http.Client
+	maxBodyBytes int64
+	explain      bool
+}
+
+// NewAdminToolsDetector builds a detector with an optional custom HTTP client.
+func NewAdminToolsDetector(client *http.Client) *AdminToolsDetector {
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	return &AdminToolsDetector{client: client, maxBodyBytes: DefaultMaxBodyBytes}
+}
+
+func newAdminToolsDetectorFromOptions(opts DetectorOptions) *AdminToolsDetector {
+	d := NewAdminToolsDetector(opts.Client)
+	d.explain = opts.Explain
+	return d
+}
+
+// Name implements Detector.
+func (d *AdminToolsDetector) Name() string {
+	return "admintools"
+}
+
+// Detect probes each known admin tool path and reports those serving the tool's login page.
+func (d *AdminToolsDetector) Detect(ctx context.Context, target string) (Result, error) {
+	baseline, err := newSoft404(ctx, d.client, target, d.maxBodyBytes)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var found []map[string]string
+	var labels []string
+	for _, probe := range adminToolProbes {
+		url := joinTargetPath(target, probe.Path)
+		resp, err := fetch(ctx, d.client, url, d.maxBodyBytes)
+		if err != nil {
+			return Result{}, err
+		}
+
+		if resp.StatusCode != http.StatusOK || baseline.matches(probe.Path, resp) {
+			continue
+		}
+
+		if probe.Marker.Match(resp.Body) {
+			found = append(found, map[string]string{"tool": probe.Tool, "url": url})
+			labels = append(labels, fmt.Sprintf("%s (%s)", probe.Tool, url))
+		}
+	}
+
+	if len(found) == 0 {
+		return withExplanation(Result{
+			Target:   target,
+			Detector: d.Name(),
+			Severity: "info",
+			Summary:  "No exposed database admin tools found",
+		}, d.explain, "requested %d known admin tool paths; none served a tool login page", len(adminToolProbes)), nil
+	}
+
+	return withExplanation(Result{
+		Target:     target,
+		Detector:   d.Name(),
+		Severity:   "critical",
+		Summary:    fmt.Sprintf("Exposed database admin tools: %s", strings.Join(labels, ", ")),
+		Metadata:   map[string]interface{}{"tools": found},
+		Confidence: AdminToolConfidence,
+		Tags:       []string{TagOWASPMisconfiguration},
+	}, d.explain, "login page markers matched at %s, which differ from the soft-404 page", strings.Join(labels, ", ")), nil
+}
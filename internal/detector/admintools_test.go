@@ -0,0 +1,54 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminToolsDetectorFindsAdminer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/adminer.php" {
+			_, _ = w.Write([]byte(`<html><head><title>Login - Adminer</title></head><body><select name="auth[driver]"></select></body></html>`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	res, err := NewAdminToolsDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Severity != "critical" {
+		t.Fatalf("expected critical severity, got %s", res.Severity)
+	}
+
+	tools, ok := res.Metadata["tools"].([]map[string]string)
+	if !ok || len(tools) != 1 {
+		t.Fatalf("expected one tool in metadata, got %v", res.Metadata)
+	}
+
+	if tools[0]["tool"] != "Adminer" || tools[0]["url"] != ts.URL+"/adminer.php" {
+		t.Fatalf("unexpected tool entry: %v", tools[0])
+	}
+}
+
+func TestAdminToolsDetectorIgnoresSoft404(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`<html><head><title>phpMyAdmin and Adminer hosting</title></head></html>`))
+	}))
+	defer ts.Close()
+
+	res, err := NewAdminToolsDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Severity != "info" {
+		t.Fatalf("expected soft-404 responses to be ignored, got %+v", res)
+	}
+}
@@ -0,0 +1,174 @@
+package detector
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheTTL is how long a cached response is reused within a scan.
+	DefaultCacheTTL = 30 * time.Second
+	// maxCachedBodyBytes caps the body size kept in memory per cached response. It
+	// matches the limit detectors read, so the cache never buffers more than they use.
+	maxCachedBodyBytes = DefaultMaxBodyBytes
+)
+
+// CachingTransport is an http.RoundTripper that reuses GET responses keyed by
+// method and full URL, so detectors that request the same page of the same
+// target only hit it once. Only 2xx, 3xx, and 404 responses are cached, so rate
+// limits and server errors left over after retries are not replayed for the TTL.
+// Entries expire after TTL. Expired 200 responses that
+// carry an ETag or Last-Modified are revalidated with If-None-Match or
+// If-Modified-Since instead of refetched, and a 304 replays the cached body, so
+// watch cycles and re-scans of unchanged pages skip the download. Use one
+// transport per scan.
+type CachingTransport struct {
+	Base http.RoundTripper
+	TTL  time.Duration
+
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	statusCode int
+	status     string
+	proto      string
+	header     http.Header
+	body       []byte
+	expires    time.Time
+}
+
+// NewCachingTransport wraps base (http.DefaultTransport when nil) with an in-memory cache.
+func NewCachingTransport(base http.RoundTripper, ttl time.Duration) *CachingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &CachingTransport{Base: base, TTL: ttl, now: time.Now, entries: map[string]cachedResponse{}}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests carrying their own validators expect to see a 304 themselves.
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" ||
+		req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return t.Base.RoundTrip(req)
+	}
+
+	key := req.Method + " " + req.URL.String()
+	entry, fresh, ok := t.lookup(key)
+	if fresh {
+		return entry.response(req), nil
+	}
+
+	outReq := req
+	if ok {
+		outReq = req.Clone(req.Context())
+		if etag := entry.header.Get("ETag"); etag != "" {
+			outReq.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.header.Get("Last-Modified"); modified != "" {
+			outReq.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		entry.expires = t.now().Add(t.TTL)
+		t.store(key, entry)
+		return entry.response(req), nil
+	}
+
+	if !cacheableStatus(resp.StatusCode) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBodyBytes+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	if len(body) > maxCachedBodyBytes {
+		// Too large to keep around; hand back the buffered prefix followed by the rest.
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	entry = cachedResponse{
+		statusCode: resp.StatusCode,
+		status:     resp.Status,
+		proto:      resp.Proto,
+		header:     resp.Header.Clone(),
+		body:       body,
+		expires:    t.now().Add(t.TTL),
+	}
+	t.store(key, entry)
+
+	return entry.response(req), nil
+}
+
+// lookup returns the entry for key and whether it is still fresh. Expired entries
+// are kept, and returned as stale, only when they can be revalidated.
+func (t *CachingTransport) lookup(key string) (entry cachedResponse, fresh, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok = t.entries[key]
+	if !ok {
+		return cachedResponse{}, false, false
+	}
+	if t.now().Before(entry.expires) {
+		return entry, true, true
+	}
+	if !entry.revalidatable() {
+		delete(t.entries, key)
+		return cachedResponse{}, false, false
+	}
+	return entry, false, true
+}
+
+func (t *CachingTransport) store(key string, entry cachedResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries[key] = entry
+}
+
+// cacheableStatus reports whether a response with code may be reused.
+func cacheableStatus(code int) bool {
+	return (code >= 200 && code < 400) || code == http.StatusNotFound
+}
+
+// revalidatable reports whether a conditional request can confirm the entry is current.
+func (c cachedResponse) revalidatable() bool {
+	return c.statusCode == http.StatusOK && (c.header.Get("ETag") != "" || c.header.Get("Last-Modified") != "")
+}
+
+// response builds a fresh *http.Response so callers can read and close it independently.
+func (c cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		StatusCode:    c.statusCode,
+		Status:        c.status,
+		Proto:         c.proto,
+		Header:        c.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,123 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// CacheSignalConfidence reflects that cache headers and plugin comments are reliable
+// but can be stripped by an intermediate proxy or a minifier.
+const CacheSignalConfidence = 0.8
+
+// cacheHeaderSignature identifies a caching layer by a response header.
+type cacheHeaderSignature struct {
+	Layer  string
+	Header string
+}
+
+var cacheHeaderSignatures = []cacheHeaderSignature{
+	{Layer: "LiteSpeed Cache", Header: "X-LiteSpeed-Cache"},
+	{Layer: "Cloudflare", Header: "CF-Cache-Status"},
+	{Layer: "Nginx", Header: "X-Nginx-Cache"},
+	{Layer: "Varnish", Header: "X-Varnish"},
+	{Layer: "Proxy cache", Header: "X-Cache"},
+}
+
+// cacheCommentSignature identifies a page caching plugin by a marker in an HTML comment.
+type cacheCommentSignature struct {
+	Layer  string
+	Marker string
+}
+
+var cacheCommentSignatures = []cacheCommentSignature{
+	{Layer: "WP Super Cache", Marker: "wp super cache"},
+	{Layer: "WP Super Cache", Marker: "wp-super-cache"},
+	{Layer: "W3 Total Cache", Marker: "w3 total cache"},
+	{Layer: "WP Rocket", Marker: "wp rocket"},
+	{Layer: "WP Fastest Cache", Marker: "wp fastest cache"},
+}
+
+var htmlCommentRegex = regexp.MustCompile(`(?s)<!--(.*?)-->`)
+
+// CacheDetector identifies caching layers in front of or inside WordPress. Cached
+// responses can be stale, which matters when interpreting other findings.
+type CacheDetector struct {
+	client       *http.Client
+	maxBodyBytes int64
+	explain      bool
+}
+
+// NewCacheDetector builds a detector with an optional custom HTTP client.
+func NewCacheDetector(client *http.Client) *CacheDetector {
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	return &CacheDetector{client: client, maxBodyBytes: DefaultMaxBodyBytes}
+}
+
+func newCacheDetectorFromOptions(opts DetectorOptions) *CacheDetector {
+	d := NewCacheDetector(opts.Client)
+	d.explain = opts.Explain
+	return d
+}
+
+// Name implements Detector.
+func (d *CacheDetector) Name() string {
+	return "cache"
+}
+
+// Detect fetches the target root document and matches its headers and HTML comments
+// against known caching layers, reporting every layer found.
+func (d *CacheDetector) Detect(ctx context.Context, target string) (Result, error) {
+	resp, err := fetch(ctx, d.client, normalizeTargetURL(target), d.maxBodyBytes)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var layers, evidence []string
+	seen := map[string]struct{}{}
+	add := func(layer, signal string) {
+		if _, dup := seen[layer]; dup {
+			return
+		}
+		seen[layer] = struct{}{}
+		layers = append(layers, layer)
+		evidence = append(evidence, signal)
+	}
+
+	for _, sig := range cacheHeaderSignatures {
+		if value := resp.Header.Get(sig.Header); value != "" {
+			add(sig.Layer, fmt.Sprintf("header %s: %s", sig.Header, value))
+		}
+	}
+	for _, match := range htmlCommentRegex.FindAllSubmatch(resp.Body, -1) {
+		comment := strings.ToLower(string(match[1]))
+		for _, sig := range cacheCommentSignatures {
+			if strings.Contains(comment, sig.Marker) {
+				add(sig.Layer, "HTML comment mentioning "+sig.Layer)
+			}
+		}
+	}
+
+	if len(layers) == 0 {
+		return withExplanation(Result{
+			Target:   target,
+			Detector: d.Name(),
+			Severity: "info",
+			Summary:  "No caching layer identified",
+			Metadata: map[string]interface{}{"caches": []string{}},
+		}, d.explain, "no response header or HTML comment matched a known caching layer"), nil
+	}
+
+	return withExplanation(Result{
+		Target:     target,
+		Detector:   d.Name(),
+		Severity:   "info",
+		Summary:    fmt.Sprintf("Caching layers detected: %s", strings.Join(layers, ", ")),
+		Metadata:   map[string]interface{}{"caches": layers, "signals": evidence},
+		Confidence: CacheSignalConfidence,
+	}, d.explain, "matched %s", strings.Join(evidence, "; ")), nil
+}
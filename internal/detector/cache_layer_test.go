@@ -0,0 +1,61 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCacheDetector(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		value      string
+		body       string
+		wantCaches []string
+	}{
+		{
+			name:       "litespeed header",
+			header:     "X-LiteSpeed-Cache",
+			value:      "hit",
+			body:       "<html></html>",
+			wantCaches: []string{"LiteSpeed Cache"},
+		},
+		{
+			name:       "wp super cache comment",
+			body:       "<html></html>\n<!-- Dynamic page generated in 0.123 seconds. -->\n<!-- Cached page generated by WP-Super-Cache on 2024-05-01 12:00:00 -->",
+			wantCaches: []string{"WP Super Cache"},
+		},
+		{
+			name:       "no cache",
+			body:       "<html><!-- theme footer --></html>",
+			wantCaches: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set(tt.header, tt.value)
+				}
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+
+			res, err := NewCacheDetector(ts.Client()).Detect(context.Background(), ts.URL)
+			if err != nil {
+				t.Fatalf("detect failed: %v", err)
+			}
+
+			if res.Severity != "info" {
+				t.Fatalf("expected info severity, got %s", res.Severity)
+			}
+			if got := res.Metadata["caches"]; !reflect.DeepEqual(got, tt.wantCaches) {
+				t.Fatalf("caches = %v, want %v", got, tt.wantCaches)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package detector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachingTransportSharesHomepageAcrossDetectors(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			atomic.AddInt32(&hits, 1)
+		}
+		w.Header().Set("X-Powered-By", "PHP/8.2.1")
+		_, _ = w.Write([]byte(`<meta name="generator" content="WordPress 6.4.2" />`))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewCachingTransport(nil, time.Minute)}
+	dets := []Detector{NewVersionDetector(client), NewPHPDetector(client)}
+
+	results, err := Run(context.Background(), dets, []string{server.URL})
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+
+	for _, res := range results {
+		if res.Err != nil {
+			t.Fatalf("detector %s failed: %v", res.Detector, res.Err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected homepage to be fetched once, got %d requests", got)
+	}
+}
+
+func TestCachingTransportKeysByURLAndExpires(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	transport := NewCachingTransport(nil, time.Second)
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+
+	get := func(path string) string {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("get %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	if body := get("/a"); body != "/a" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if body := get("/b"); body != "/b" {
+		t.Fatalf("cache must not mix URLs, got %q", body)
+	}
+	get("/a")
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Fatalf("expected 2 upstream requests, got %d", got)
+	}
+
+	now = now.Add(2 * time.Second)
+	get("/a")
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Fatalf("expected expired entry to be refetched, got %d requests", got)
+	}
+}
+
+func TestCachingTransportSkipsErrorsAndOversizedBodies(t *testing.T) {
+	large := strings.Repeat("x", maxCachedBodyBytes+1)
+	hits := map[string]int{}
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/busy":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/large":
+			_, _ = w.Write([]byte(large))
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewCachingTransport(nil, time.Minute)}
+	for i := 0; i < 2; i++ {
+		for _, path := range []string{"/busy", "/limited", "/missing", "/large"} {
+			resp, err := client.Get(server.URL + path)
+			if err != nil {
+				t.Fatalf("get %s: %v", path, err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if path == "/large" && len(body) != len(large) {
+				t.Fatalf("expected the full oversized body, got %d bytes", len(body))
+			}
+		}
+	}
+
+	expected := map[string]int{"/busy": 2, "/limited": 2, "/missing": 1, "/large": 2}
+	if !reflect.DeepEqual(hits, expected) {
+		t.Fatalf("expected only the 404 to be cached, got upstream hits %v", hits)
+	}
+}
+
+func TestCachingTransportRevalidatesWithETag(t *testing.T) {
+	var full, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"home-v1"`)
+		if r.Header.Get("If-None-Match") == `"home-v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&full, 1)
+		_, _ = w.Write([]byte(`<meta name="generator" content="WordPress 6.4.2" />`))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	transport := NewCachingTransport(nil, time.Second)
+	transport.now = func() time.Time { return now }
+	detector := NewVersionDetector(&http.Client{Transport: transport})
+
+	first, err := detector.Detect(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("first detect: %v", err)
+	}
+
+	// A later watch cycle: the entry has expired and must be revalidated, not refetched.
+	now = now.Add(time.Minute)
+	second, err := detector.Detect(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("second detect: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&notModified); got != 1 {
+		t.Fatalf("expected one conditional request answered with 304, got %d", got)
+	}
+	if got := atomic.LoadInt32(&full); got != 1 {
+		t.Fatalf("expected the homepage body to be downloaded once, got %d", got)
+	}
+	if second.Metadata[MetaVersion] != "6.4.2" || second.Metadata[MetaVersion] != first.Metadata[MetaVersion] {
+		t.Fatalf("expected the cached version to be reused, got %v then %v", first.Metadata, second.Metadata)
+	}
+}
+
+func TestCachingTransportPassesThroughCallerValidators(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewCachingTransport(nil, time.Minute)}
+	if resp, err := client.Get(server.URL); err == nil {
+		resp.Body.Close()
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("If-None-Match", `"v1"`)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("conditional get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("expected the caller's own 304, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,82 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+)
+
+// Confirmation markers recorded when a target is identified as WordPress.
+const (
+	MarkerGenerator = "generator"
+	MarkerWPEmoji   = "wp-emoji"
+	MarkerWPEmbed   = "wp-embed"
+	MarkerLoginPage = "wp-login"
+)
+
+// wordPressMarker is a homepage fragment that only WordPress sites typically serve.
+type wordPressMarker struct {
+	Name    string
+	Pattern []byte
+}
+
+// wordPressMarkers are checked in order. The core scripts come first so hardened
+// sites that strip the generator tag still report the specific asset that matched.
+var wordPressMarkers = []wordPressMarker{
+	{Name: MarkerWPEmoji, Pattern: []byte("wp-emoji-release.min.js")},
+	{Name: MarkerWPEmbed, Pattern: []byte("/wp-includes/js/wp-embed.min.js")},
+	{Name: "/wp-content/", Pattern: []byte("/wp-content/")},
+	{Name: "/wp-includes/", Pattern: []byte("/wp-includes/")},
+	{Name: "wp-json", Pattern: []byte("wp-json")},
+}
+
+// Confirmation is the outcome of ConfirmWordPress.
+type Confirmation struct {
+	Confirmed bool
+	// Marker names the signal that identified WordPress; empty when not confirmed.
+	Marker string
+}
+
+// ConfirmWordPress reports whether target looks like a WordPress site. It checks the
+// homepage for a WordPress generator tag, core scripts, or asset paths and falls back
+// to the login page, so sites that hide their version are still recognized. Like the
+// version detector, it fetches the homepage at opts.HomePath and matches the generator
+// with opts.VersionPattern when set, using opts.Client for requests.
+func ConfirmWordPress(ctx context.Context, target string, opts DetectorOptions) (Confirmation, error) {
+	client := opts.Client
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	homeURL := normalizeTargetURL(target)
+	if opts.HomePath != "" && opts.HomePath != DefaultHomePath {
+		homeURL = joinTargetPath(target, opts.HomePath)
+	}
+	generator := versionRegex
+	if opts.VersionPattern != nil {
+		generator = opts.VersionPattern
+	}
+
+	home, err := fetch(ctx, client, homeURL, DefaultMaxBodyBytes)
+	if err != nil {
+		return Confirmation{}, err
+	}
+
+	if generator.Match(home.Body) {
+		return Confirmation{Confirmed: true, Marker: MarkerGenerator}, nil
+	}
+	for _, marker := range wordPressMarkers {
+		if bytes.Contains(home.Body, marker.Pattern) {
+			return Confirmation{Confirmed: true, Marker: marker.Name}, nil
+		}
+	}
+
+	login, err := fetch(ctx, client, joinTargetPath(target, "/wp-login.php"), DefaultMaxBodyBytes)
+	if err != nil {
+		return Confirmation{}, err
+	}
+
+	if login.StatusCode == http.StatusOK && bytes.Contains(login.Body, []byte("user_login")) {
+		return Confirmation{Confirmed: true, Marker: MarkerLoginPage}, nil
+	}
+	return Confirmation{}, nil
+}
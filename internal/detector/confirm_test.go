@@ -0,0 +1,106 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestConfirmWordPress(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		opts     DetectorOptions
+		expected bool
+		marker   string
+	}{
+		{
+			name: "generator tag",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`<meta name="generator" content="WordPress 6.4.2" />`))
+			},
+			expected: true,
+			marker:   MarkerGenerator,
+		},
+		{
+			name: "wp-embed script only",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/" {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(`<html><body><script src="https://cdn.example.test/wp-includes/js/wp-embed.min.js?ver=6.5"></script></body></html>`))
+			},
+			expected: true,
+			marker:   MarkerWPEmbed,
+		},
+		{
+			name: "login page only",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/wp-login.php" {
+					_, _ = w.Write([]byte(`<input type="text" name="log" id="user_login">`))
+					return
+				}
+				_, _ = w.Write([]byte("<html>hidden</html>"))
+			},
+			expected: true,
+			marker:   MarkerLoginPage,
+		},
+		{
+			name: "generator under home path",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/blog/" {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(`<meta name="generator" content="WordPress 6.4.2" />`))
+			},
+			opts:     DetectorOptions{HomePath: "/blog/"},
+			expected: true,
+			marker:   MarkerGenerator,
+		},
+		{
+			name: "custom version pattern",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/" {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(`<meta name="generator" content="Acme Press 6.4.2" />`))
+			},
+			opts:     DetectorOptions{VersionPattern: regexp.MustCompile(`Acme Press ([0-9.]+)`)},
+			expected: true,
+			marker:   MarkerGenerator,
+		},
+		{
+			name: "static site",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/" {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte("<html>static</html>"))
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			opts := tt.opts
+			opts.Client = server.Client()
+			got, err := ConfirmWordPress(context.Background(), server.URL, opts)
+			if err != nil {
+				t.Fatalf("confirm returned error: %v", err)
+			}
+			if got.Confirmed != tt.expected || got.Marker != tt.marker {
+				t.Fatalf("expected confirmed=%v marker=%q, got %+v", tt.expected, tt.marker, got)
+			}
+		})
+	}
+}
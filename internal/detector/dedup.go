@@ -0,0 +1,61 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultDedupKey lists the Result fields that identify duplicate findings.
+var DefaultDedupKey = []string{"target", "severity", "summary"}
+
+// dedupFields maps dedup key names to the Result value they select.
+var dedupFields = map[string]func(Result) string{
+	"target":   func(r Result) string { return r.Target },
+	"detector": func(r Result) string { return r.Detector },
+	"severity": func(r Result) string { return r.Severity },
+	"summary":  func(r Result) string { return r.Summary },
+}
+
+// ValidateDedupKey reports an error when key is empty or names an unknown field.
+func ValidateDedupKey(key []string) error {
+	if len(key) == 0 {
+		return fmt.Errorf("dedup key must name at least one field")
+	}
+	for _, field := range key {
+		if _, ok := dedupFields[field]; !ok {
+			return fmt.Errorf("unsupported dedup key field %q (supported: target, detector, severity, summary)", field)
+		}
+	}
+	return nil
+}
+
+// Dedup collapses results that share the same key fields, keeping the one with the
+// highest confidence at the position of the first occurrence. Callers should check
+// key with ValidateDedupKey; unknown fields are ignored.
+func Dedup(results []Result, key []string) []Result {
+	if len(results) < 2 {
+		return results
+	}
+
+	index := map[string]int{}
+	deduped := make([]Result, 0, len(results))
+	for _, res := range results {
+		parts := make([]string, 0, len(key))
+		for _, field := range key {
+			if value, ok := dedupFields[field]; ok {
+				parts = append(parts, value(res))
+			}
+		}
+		id := strings.Join(parts, "\x00")
+
+		if i, seen := index[id]; seen {
+			if res.Confidence > deduped[i].Confidence {
+				deduped[i] = res
+			}
+			continue
+		}
+		index[id] = len(deduped)
+		deduped = append(deduped, res)
+	}
+	return deduped
+}
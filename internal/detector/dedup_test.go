@@ -0,0 +1,33 @@
+package detector
+
+import "testing"
+
+func TestDedupKeepsHighestConfidence(t *testing.T) {
+	results := []Result{
+		{Target: "https://one.test", Detector: "version", Severity: "info", Summary: "WordPress version 6.5.1 detected", Confidence: 0.6},
+		{Target: "https://one.test", Detector: "php", Severity: "warning", Summary: "PHP 7.4 is end-of-life"},
+		{Target: "https://one.test", Detector: "readme", Severity: "info", Summary: "WordPress version 6.5.1 detected", Confidence: 0.9},
+	}
+
+	deduped := Dedup(results, DefaultDedupKey)
+	if len(deduped) != 2 {
+		t.Fatalf("expected 2 results after dedup, got %d: %+v", len(deduped), deduped)
+	}
+
+	if deduped[0].Confidence != 0.9 || deduped[0].Detector != "readme" {
+		t.Fatalf("expected the higher-confidence duplicate to be kept first, got %+v", deduped[0])
+	}
+
+	if kept := Dedup(results, []string{"detector", "summary"}); len(kept) != 3 {
+		t.Fatalf("expected detector-scoped key to keep all results, got %d", len(kept))
+	}
+}
+
+func TestValidateDedupKey(t *testing.T) {
+	if err := ValidateDedupKey(DefaultDedupKey); err != nil {
+		t.Fatalf("default key rejected: %v", err)
+	}
+	if err := ValidateDedupKey([]string{"target", "confidence"}); err == nil {
+		t.Fatal("expected unknown field to be rejected")
+	}
+}
@@ -1,15 +1,48 @@
 package detector
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+)
 
 // Result represents a single detector finding for a target.
 type Result struct {
-	Target     string                 `json:"target"`
-	Detector   string                 `json:"detector"`
-	Severity   string                 `json:"severity"`
-	Summary    string                 `json:"summary"`
-	Metadata   map[string]interface{} `json:"metadata,omitempty"`
-	Confidence float64                `json:"confidence,omitempty"`
+	Target     string                 `json:"target" yaml:"target"`
+	Detector   string                 `json:"detector" yaml:"detector"`
+	Severity   string                 `json:"severity" yaml:"severity"`
+	Summary    string                 `json:"summary" yaml:"summary"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Confidence float64                `json:"confidence,omitempty" yaml:"confidence,omitempty"`
+	// Tags map the finding to compliance controls or routing labels (e.g. "owasp-a05").
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// DetectedAt records when the detector produced the result; set by Run. It is
+	// omitted from JSON and YAML when unset (see MarshalJSON).
+	DetectedAt time.Time `json:"detectedAt,omitempty" yaml:"detectedAt,omitempty"`
+	// DurationMs is how long the detector took for this target; set by Run.
+	DurationMs int64 `json:"durationMs,omitempty" yaml:"durationMs,omitempty"`
+	// ErrorKind classifies a failed detector run (see ClassifyError); empty on success.
+	ErrorKind string `json:"errorKind,omitempty" yaml:"errorKind,omitempty"`
+	// Err holds the underlying detector error for programmatic callers. It is not serialized.
+	Err error `json:"-" yaml:"-"`
+}
+
+// MarshalJSON encodes r, leaving out a zero DetectedAt. encoding/json ignores
+// omitempty on struct types such as time.Time, so results built outside Run would
+// otherwise carry "0001-01-01T00:00:00Z".
+func (r Result) MarshalJSON() ([]byte, error) {
+	type plain Result
+	out := struct {
+		plain
+		DetectedAt *time.Time `json:"detectedAt,omitempty"`
+	}{plain: plain(r)}
+	if !r.DetectedAt.IsZero() {
+		out.DetectedAt = &r.DetectedAt
+	}
+	return json.Marshal(out)
 }
 
 // Detector is implemented by modules that can analyze a target.
@@ -17,3 +50,73 @@ type Detector interface {
 	Name() string
 	Detect(ctx context.Context, target string) (Result, error)
 }
+
+// Compliance tags attached to findings (OWASP Top 10 2021 categories).
+const (
+	TagOWASPMisconfiguration = "owasp-a05"
+	TagOWASPOutdated         = "owasp-a06"
+)
+
+// Confidence keys identify calibratable signals in DetectorOptions.Confidence.
+const (
+	ConfidenceVersionGenerator = "version_generator"
+	ConfidenceVersionReadme    = "version_readme"
+	ConfidenceVersionAsset     = "version_asset"
+	ConfidenceVCSExposure      = "vcs_exposure"
+)
+
+// ConfidenceKeys lists every calibratable signal, so configuration can reject unknown keys.
+var ConfidenceKeys = []string{ConfidenceVersionGenerator, ConfidenceVersionReadme, ConfidenceVersionAsset, ConfidenceVCSExposure}
+
+// DetectorOptions carries user-tunable settings passed to detector factories.
+type DetectorOptions struct {
+	// Client is shared by HTTP-based detectors; each detector builds a default client when nil.
+	Client *http.Client
+	// Confidence overrides built-in confidence values keyed by signal name.
+	Confidence map[string]float64
+	// Explain asks detectors to describe their evidence in Metadata["explanation"].
+	Explain bool
+	// StatusWarn lists the homepage status ranges the status detector reports as
+	// warnings; empty uses DefaultStatusWarnRanges.
+	StatusWarn []StatusRange
+	// HomePath is the entry path the version detector fetches on each target
+	// (e.g. /index.php); empty uses DefaultHomePath.
+	HomePath string
+	// VersionPattern replaces the generator version regex for localized or customized
+	// installs; its first capture group holds the version. Nil uses the built-in
+	// pattern. Build it with ParseVersionPattern.
+	VersionPattern *regexp.Regexp
+	// MinVersion is the lowest WordPress version the version detector accepts; older
+	// versions are reported at OutdatedSeverity. Empty disables the check.
+	MinVersion string
+	// OutdatedSeverity is the severity for versions below MinVersion; empty uses
+	// DefaultOutdatedSeverity.
+	OutdatedSeverity string
+	// HeadFirst makes the version detector send a HEAD before fetching the homepage
+	// and skip targets that do not serve HTML.
+	HeadFirst bool
+	// ExternalTimeout bounds each external detector command; zero uses
+	// DefaultExternalTimeout.
+	ExternalTimeout time.Duration
+}
+
+// withExplanation records a human-readable rationale in Metadata["explanation"] when
+// enabled, so users can see which evidence and method produced a finding.
+func withExplanation(res Result, enabled bool, format string, args ...interface{}) Result {
+	if !enabled {
+		return res
+	}
+	if res.Metadata == nil {
+		res.Metadata = map[string]interface{}{}
+	}
+	res.Metadata[MetaExplanation] = fmt.Sprintf(format, args...)
+	return res
+}
+
+// ConfidenceFor returns the calibrated confidence for key, or fallback when it is not configured.
+func (o DetectorOptions) ConfidenceFor(key string, fallback float64) float64 {
+	if value, ok := o.Confidence[key]; ok {
+		return value
+	}
+	return fallback
+}
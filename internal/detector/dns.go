@@ -0,0 +1,225 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultDNSCacheTTL bounds how long a resolved host is reused. It is short so a
+// scan still follows DNS changes, yet covers the burst of requests detectors send.
+const DefaultDNSCacheTTL = time.Minute
+
+// dnsFallbackDelay is how long DialContext dials the first address family alone
+// before racing the other, matching net.Dialer's Happy Eyeballs default.
+const dnsFallbackDelay = 300 * time.Millisecond
+
+// dnsLookupTimeout bounds a shared lookup, which runs detached from the caller that
+// started it.
+const dnsLookupTimeout = 10 * time.Second
+
+// HostResolver looks up the addresses of a host; *net.Resolver implements it.
+type HostResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// DialFunc matches http.Transport.DialContext.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// DNSCache resolves each host once per TTL and shares the answer across goroutines.
+// Concurrent lookups of the same host wait for a single resolution. Failed lookups
+// are never cached, so a transient resolver error does not stick for the TTL.
+type DNSCache struct {
+	resolver      HostResolver
+	ttl           time.Duration
+	fallbackDelay time.Duration
+	now           func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*dnsEntry
+}
+
+// dnsEntry is one host's resolution; done is closed once addrs and err are set.
+type dnsEntry struct {
+	done    chan struct{}
+	addrs   []net.IPAddr
+	err     error
+	expires time.Time
+}
+
+// NewDNSCache wraps resolver (net.DefaultResolver when nil). A non-positive ttl
+// falls back to DefaultDNSCacheTTL.
+func NewDNSCache(resolver HostResolver, ttl time.Duration) *DNSCache {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	if ttl <= 0 {
+		ttl = DefaultDNSCacheTTL
+	}
+	return &DNSCache{resolver: resolver, ttl: ttl, fallbackDelay: dnsFallbackDelay, now: time.Now, entries: map[string]*dnsEntry{}}
+}
+
+// LookupIPAddr returns the cached addresses of host, resolving it when missing or
+// expired. The resolution itself is shared by every caller, so it runs detached from
+// ctx: a cancelled caller stops waiting without failing the others.
+func (c *DNSCache) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	if ok {
+		select {
+		case <-entry.done:
+			if !c.now().Before(entry.expires) {
+				ok = false
+			}
+		default:
+			// Another goroutine is resolving the host; wait for its answer below.
+		}
+	}
+	if !ok {
+		entry = &dnsEntry{done: make(chan struct{})}
+		c.entries[host] = entry
+		go c.resolve(context.WithoutCancel(ctx), host, entry)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-entry.done:
+		return entry.addrs, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// resolve looks host up within dnsLookupTimeout, fills entry, and drops it from the
+// cache again when the lookup failed.
+func (c *DNSCache) resolve(ctx context.Context, host string, entry *dnsEntry) {
+	ctx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+	defer cancel()
+
+	entry.addrs, entry.err = c.resolver.LookupIPAddr(ctx, host)
+	entry.expires = c.now().Add(c.ttl)
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.entries[host] == entry {
+			delete(c.entries, host)
+		}
+		c.mu.Unlock()
+	}
+	close(entry.done)
+}
+
+// DialContext wraps dial so host names are resolved through the cache; IP literals
+// are dialed directly. Like net.Dialer, addresses of the resolver's first family are
+// tried in order, and when the host also has addresses of the other family those are
+// raced after a short delay (Happy Eyeballs), so a blackholed IPv6 route falls back
+// to IPv4 instead of spending the whole request timeout.
+func (c *DNSCache) DialContext(dial DialFunc) DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+
+		addrs, err := c.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		if len(addrs) == 0 {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+
+		primaries, fallbacks := splitAddrFamilies(addrs)
+		if len(fallbacks) == 0 {
+			return dialSerial(ctx, dial, network, port, primaries)
+		}
+		return c.dialParallel(ctx, dial, network, port, primaries, fallbacks)
+	}
+}
+
+// splitAddrFamilies partitions addrs into those of the first address's family and
+// the rest, keeping the resolver's order within each.
+func splitAddrFamilies(addrs []net.IPAddr) (primaries, fallbacks []net.IPAddr) {
+	firstIsV4 := addrs[0].IP.To4() != nil
+	for _, ip := range addrs {
+		if (ip.IP.To4() != nil) == firstIsV4 {
+			primaries = append(primaries, ip)
+		} else {
+			fallbacks = append(fallbacks, ip)
+		}
+	}
+	return primaries, fallbacks
+}
+
+// dialSerial tries each address in order until one connects.
+func dialSerial(ctx context.Context, dial DialFunc, network, port string, addrs []net.IPAddr) (net.Conn, error) {
+	var errs []error
+	for _, ip := range addrs {
+		conn, err := dial(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// dialParallel dials primaries, starting fallbacks after c.fallbackDelay or as soon
+// as the primaries fail. The first connection wins; the other race is cancelled and
+// any connection it still makes is closed.
+func (c *DNSCache) dialParallel(ctx context.Context, dial DialFunc, network, port string, primaries, fallbacks []net.IPAddr) (net.Conn, error) {
+	type dialResult struct {
+		conn    net.Conn
+		err     error
+		primary bool
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	returned := make(chan struct{})
+	defer close(returned)
+
+	results := make(chan dialResult)
+	race := func(primary bool, addrs []net.IPAddr) {
+		conn, err := dialSerial(ctx, dial, network, port, addrs)
+		select {
+		case results <- dialResult{conn: conn, err: err, primary: primary}:
+		case <-returned:
+			if conn != nil {
+				conn.Close()
+			}
+		}
+	}
+
+	go race(true, primaries)
+	fallbackTimer := time.NewTimer(c.fallbackDelay)
+	defer fallbackTimer.Stop()
+
+	var primaryErr, fallbackErr error
+	for {
+		select {
+		case <-fallbackTimer.C:
+			go race(false, fallbacks)
+		case res := <-results:
+			if res.err == nil {
+				return res.conn, nil
+			}
+			if res.primary {
+				primaryErr = res.err
+				// Start the fallbacks right away unless they are already running.
+				if fallbackTimer.Stop() {
+					fallbackTimer.Reset(0)
+				}
+			} else {
+				fallbackErr = res.err
+			}
+			if primaryErr != nil && fallbackErr != nil {
+				return nil, errors.Join(primaryErr, fallbackErr)
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingResolver resolves every host to loopback, failing the first failFirst lookups.
+type countingResolver struct {
+	calls     int32
+	failFirst int32
+}
+
+func (r *countingResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if n := atomic.AddInt32(&r.calls, 1); n <= r.failFirst {
+		return nil, &net.DNSError{Err: "temporary failure", Name: host, IsTemporary: true}
+	}
+	return []net.IPAddr{{IP: net.IPv4(127, 0, 0, 1)}}, nil
+}
+
+func TestDNSCacheResolvesEachHostOnce(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+
+	resolver := &countingResolver{}
+	cache := NewDNSCache(resolver, time.Minute)
+	client := &http.Client{Transport: &http.Transport{
+		DialContext:       cache.DialContext((&net.Dialer{}).DialContext),
+		DisableKeepAlives: true,
+	}}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get("http://wp.example.test:" + port + "/")
+			if err != nil {
+				errs <- err
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("get: %v", err)
+	}
+
+	if calls := atomic.LoadInt32(&resolver.calls); calls != 1 {
+		t.Fatalf("expected one resolution for the host, got %d", calls)
+	}
+}
+
+func TestDNSCacheDoesNotCacheFailures(t *testing.T) {
+	resolver := &countingResolver{failFirst: 1}
+	cache := NewDNSCache(resolver, time.Minute)
+
+	if _, err := cache.LookupIPAddr(context.Background(), "wp.example.test"); err == nil {
+		t.Fatalf("expected the first lookup to fail")
+	}
+	addrs, err := cache.LookupIPAddr(context.Background(), "wp.example.test")
+	if err != nil || len(addrs) != 1 {
+		t.Fatalf("expected the retry to resolve, got %v (%v)", addrs, err)
+	}
+	if calls := atomic.LoadInt32(&resolver.calls); calls != 2 {
+		t.Fatalf("expected the failure to be retried, got %d lookups", calls)
+	}
+}
+
+func TestDNSCacheExpiresAfterTTL(t *testing.T) {
+	resolver := &countingResolver{}
+	cache := NewDNSCache(resolver, time.Minute)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.LookupIPAddr(context.Background(), "wp.example.test"); err != nil {
+			t.Fatalf("lookup: %v", err)
+		}
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := cache.LookupIPAddr(context.Background(), "wp.example.test"); err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+
+	if calls := atomic.LoadInt32(&resolver.calls); calls != 2 {
+		t.Fatalf("expected a fresh lookup only after the TTL, got %d lookups", calls)
+	}
+}
+
+func TestDNSCacheDialsIPLiteralsDirectly(t *testing.T) {
+	resolver := &countingResolver{}
+	dialed := ""
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = addr
+		return nil, errors.New("dial disabled in test")
+	}
+
+	_, _ = NewDNSCache(resolver, 0).DialContext(dial)(context.Background(), "tcp", "10.0.0.1:443")
+	if dialed != "10.0.0.1:443" || atomic.LoadInt32(&resolver.calls) != 0 {
+		t.Fatalf("expected a direct dial without lookup, dialed %q after %d lookups", dialed, resolver.calls)
+	}
+}
+
+// staticResolver answers every lookup with addrs.
+type staticResolver []net.IPAddr
+
+func (r staticResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return r, nil
+}
+
+func TestDNSCacheFallsBackToIPv4WhenIPv6Hangs(t *testing.T) {
+	resolver := staticResolver{{IP: net.ParseIP("2001:db8::1")}, {IP: net.IPv4(192, 0, 2, 1)}}
+	cache := NewDNSCache(resolver, time.Minute)
+	cache.fallbackDelay = 10 * time.Millisecond
+
+	ipv6Cancelled := make(chan struct{})
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr == "[2001:db8::1]:443" {
+			// A blackholed route: the dial only ends when it is cancelled.
+			<-ctx.Done()
+			close(ipv6Cancelled)
+			return nil, ctx.Err()
+		}
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := cache.DialContext(dial)(ctx, "tcp", "wp.example.test:443")
+	if err != nil {
+		t.Fatalf("expected the IPv4 fallback to connect, got %v", err)
+	}
+	conn.Close()
+
+	select {
+	case <-ipv6Cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the losing IPv6 dial to be cancelled")
+	}
+}
+
+// blockingResolver holds every lookup until release is closed.
+type blockingResolver struct {
+	calls   int32
+	release chan struct{}
+}
+
+func (r *blockingResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	atomic.AddInt32(&r.calls, 1)
+	select {
+	case <-r.release:
+		return []net.IPAddr{{IP: net.IPv4(127, 0, 0, 1)}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestDNSCacheLookupSurvivesCancelledFirstCaller(t *testing.T) {
+	resolver := &blockingResolver{release: make(chan struct{})}
+	cache := NewDNSCache(resolver, time.Minute)
+
+	first, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cache.LookupIPAddr(first, "wp.example.test"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancelled caller to stop waiting, got %v", err)
+	}
+
+	// The second caller joins the lookup the cancelled one started.
+	close(resolver.release)
+	addrs, err := cache.LookupIPAddr(context.Background(), "wp.example.test")
+	if err != nil || len(addrs) != 1 {
+		t.Fatalf("expected the shared lookup to resolve, got %v (%v)", addrs, err)
+	}
+	if calls := atomic.LoadInt32(&resolver.calls); calls != 1 {
+		t.Fatalf("expected a single resolution, got %d", calls)
+	}
+}
@@ -0,0 +1,46 @@
+package detector
+
+import (
+	"fmt"
+	"time"
+)
+
+// VersionDriftDetector names the results CheckExpectedVersions produces.
+const VersionDriftDetector = "version-drift"
+
+// CheckExpectedVersions compares the versions reported by the version detector with
+// the versions an inventory expects per target. Each mismatch yields a warning result
+// carrying both versions; matching and unlisted targets yield nothing.
+func CheckExpectedVersions(results []Result, expected map[string]string) []Result {
+	if len(expected) == 0 {
+		return nil
+	}
+
+	var drift []Result
+	for _, res := range results {
+		if res.Detector != "version" || res.Err != nil {
+			continue
+		}
+		want, ok := expected[res.Target]
+		if !ok {
+			continue
+		}
+		detected, _ := res.Metadata[MetaVersion].(string)
+		if detected == "" || CompareVersions(detected, want) == 0 {
+			continue
+		}
+		drift = append(drift, Result{
+			Target:     res.Target,
+			Detector:   VersionDriftDetector,
+			Severity:   "warning",
+			Summary:    fmt.Sprintf("WordPress version %s detected, expected %s", detected, want),
+			Confidence: res.Confidence,
+			Metadata: map[string]interface{}{
+				"detectedVersion": detected,
+				"expectedVersion": want,
+			},
+			DetectedAt: time.Now().UTC(),
+		})
+	}
+	return drift
+}
@@ -0,0 +1,27 @@
+package detector
+
+import "testing"
+
+func TestCheckExpectedVersionsFlagsMismatch(t *testing.T) {
+	results := []Result{
+		{Target: "https://match.test", Detector: "version", Severity: "info", Metadata: map[string]interface{}{"version": "6.5.1"}},
+		{Target: "https://drift.test", Detector: "version", Severity: "info", Metadata: map[string]interface{}{"version": "6.2"}},
+	}
+	expected := map[string]string{
+		"https://match.test": "6.5.1",
+		"https://drift.test": "6.5.1",
+	}
+
+	drift := CheckExpectedVersions(results, expected)
+	if len(drift) != 1 {
+		t.Fatalf("expected one drift result, got %d: %+v", len(drift), drift)
+	}
+
+	got := drift[0]
+	if got.Target != "https://drift.test" || got.Severity != "warning" || got.Detector != VersionDriftDetector {
+		t.Fatalf("unexpected drift result: %+v", got)
+	}
+	if got.Metadata["detectedVersion"] != "6.2" || got.Metadata["expectedVersion"] != "6.5.1" {
+		t.Fatalf("expected both versions in metadata, got %+v", got.Metadata)
+	}
+}
@@ -0,0 +1,114 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"unicode/utf8"
+)
+
+// Error kinds recorded on results produced from failed detector runs.
+const (
+	ErrorKindTimeout    = "timeout"
+	ErrorKindConnection = "connection"
+	ErrorKindHTTPStatus = "http-status"
+	ErrorKindParse      = "parse"
+	ErrorKindRedirect   = "redirect"
+	ErrorKindPanic      = "panic"
+	ErrorKindUnknown    = "unknown"
+
+	// ErrorKindTargetTimeout marks detectors cut off or skipped by a per-target timeout.
+	ErrorKindTargetTimeout = "target-timeout"
+)
+
+// StatusError reports an HTTP status code a detector cannot work with.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+}
+
+// ParseError reports a response that did not contain the data a detector looks for.
+type ParseError struct {
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return e.Msg
+}
+
+// maxPanicValueLen bounds how much of a recovered panic value is kept on a result.
+const maxPanicValueLen = 512
+
+// PanicError reports a detector that panicked; Run recovers it so other detectors
+// and targets keep their results.
+type PanicError struct {
+	// Value is the recovered value formatted with %v and truncated.
+	Value string
+}
+
+func (e *PanicError) Error() string {
+	return "detector panic: " + e.Value
+}
+
+// newPanicError formats a recovered value. fmt reports panics raised while
+// formatting (e.g. from a String method) inline, so this never panics itself.
+func newPanicError(recovered interface{}) *PanicError {
+	value := fmt.Sprintf("%v", recovered)
+	if len(value) > maxPanicValueLen {
+		// Cut on a rune boundary so JSON and SARIF output stay valid UTF-8.
+		cut := maxPanicValueLen
+		for cut > 0 && !utf8.RuneStart(value[cut]) {
+			cut--
+		}
+		value = value[:cut] + "..."
+	}
+	return &PanicError{Value: value}
+}
+
+// ClassifyError maps a detector error onto one of the ErrorKind constants.
+func ClassifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return ErrorKindHTTPStatus
+	}
+
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return ErrorKindParse
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		return ErrorKindPanic
+	}
+
+	var redirectErr *RedirectError
+	if errors.As(err, &redirectErr) {
+		return ErrorKindRedirect
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorKindTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorKindTimeout
+	}
+
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) {
+		return ErrorKindConnection
+	}
+
+	return ErrorKindUnknown
+}
@@ -0,0 +1,117 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// maxExternalOutputBytes bounds how much stdout an external detector may produce.
+const maxExternalOutputBytes = 1 << 20
+
+// DefaultExternalTimeout bounds each external detector invocation when no timeout is configured.
+const DefaultExternalTimeout = 30 * time.Second
+
+// ExternalDetector runs a user-supplied command once per target, passing the target
+// URL as its only argument, and decodes a Result from the command's JSON stdout.
+type ExternalDetector struct {
+	name    string
+	command string
+	timeout time.Duration
+}
+
+// NewExternalDetector builds a detector named name that runs command, killing it
+// after timeout (DefaultExternalTimeout when non-positive).
+func NewExternalDetector(name, command string, timeout time.Duration) *ExternalDetector {
+	if timeout <= 0 {
+		timeout = DefaultExternalTimeout
+	}
+	return &ExternalDetector{name: name, command: command, timeout: timeout}
+}
+
+// ParseExternalDetector splits a "name:/path/to/cmd" spec. The name must not collide
+// with a detector already in registry.
+func ParseExternalDetector(spec string, registry Registry) (name, command string, err error) {
+	name, command, ok := strings.Cut(spec, ":")
+	name = strings.TrimSpace(name)
+	command = strings.TrimSpace(command)
+	if !ok || name == "" || command == "" {
+		return "", "", fmt.Errorf("invalid external detector %q (expected name:/path/to/cmd)", spec)
+	}
+	if _, exists := registry[name]; exists {
+		return "", "", fmt.Errorf("external detector %q conflicts with an existing detector", name)
+	}
+	return name, command, nil
+}
+
+// Name implements Detector.
+func (d *ExternalDetector) Name() string {
+	return d.name
+}
+
+// Detect runs the command for target. A nonzero exit, a run past the detector's
+// timeout, or output that is not a Result JSON object is returned as an error, so Run
+// records it as an error result.
+func (d *ExternalDetector) Detect(ctx context.Context, target string) (Result, error) {
+	cmdCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(cmdCtx, d.command, target)
+	cmd.Stdout = &limitedBuffer{buf: &stdout, remaining: maxExternalOutputBytes}
+	cmd.Stderr = &limitedBuffer{buf: &stderr, remaining: 4096}
+	// Children of a killed command may keep its output pipes open; stop waiting on them.
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return Result{}, ctx.Err()
+		}
+		if cmdCtx.Err() != nil {
+			return Result{}, fmt.Errorf("external detector %s timed out after %s: %w", d.name, d.timeout, cmdCtx.Err())
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return Result{}, fmt.Errorf("external detector %s exited with code %d: %s", d.name, exitErr.ExitCode(), strings.TrimSpace(stderr.String()))
+		}
+		return Result{}, fmt.Errorf("external detector %s: %w", d.name, err)
+	}
+
+	var res Result
+	if err := json.NewDecoder(&stdout).Decode(&res); err != nil {
+		return Result{}, &ParseError{Msg: fmt.Sprintf("external detector %s wrote malformed result JSON: %v", d.name, err)}
+	}
+	if res.Severity == "" || res.Summary == "" {
+		return Result{}, &ParseError{Msg: fmt.Sprintf("external detector %s result is missing severity or summary", d.name)}
+	}
+
+	// The command cannot speak for other targets or detectors.
+	res.Target = target
+	res.Detector = d.name
+	return res, nil
+}
+
+// limitedBuffer keeps at most remaining bytes and silently discards the rest, so a
+// chatty command cannot exhaust memory.
+type limitedBuffer struct {
+	buf       *bytes.Buffer
+	remaining int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if b.remaining <= 0 {
+		return n, nil
+	}
+	if len(p) > b.remaining {
+		p = p[:b.remaining]
+	}
+	b.remaining -= len(p)
+	b.buf.Write(p)
+	return n, nil
+}
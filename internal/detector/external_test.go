@@ -0,0 +1,94 @@
+package detector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported on windows")
+	}
+	path := filepath.Join(t.TempDir(), "detector.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o700); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	return path
+}
+
+func TestExternalDetectorDecodesResult(t *testing.T) {
+	script := writeScript(t, `echo '{"target":"ignored","severity":"warning","summary":"custom check on '"$1"'","metadata":{"rule":"x-1"},"confidence":0.7}'`)
+
+	res, err := NewExternalDetector("custom", script, 0).Detect(context.Background(), "https://one.test")
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Target != "https://one.test" || res.Detector != "custom" || res.Severity != "warning" || res.Confidence != 0.7 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if res.Summary != "custom check on https://one.test" || res.Metadata["rule"] != "x-1" {
+		t.Fatalf("expected target on argv and metadata passed through, got %+v", res)
+	}
+}
+
+func TestExternalDetectorFailuresBecomeErrorResults(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		kind     string
+		contains string
+	}{
+		{name: "nonzero exit", body: "echo 'boom' >&2\nexit 3\n", kind: ErrorKindUnknown, contains: "exited with code 3: boom"},
+		{name: "malformed json", body: "echo 'not json'\n", kind: ErrorKindParse, contains: "malformed result JSON"},
+		{name: "missing fields", body: `echo '{"metadata":{}}'` + "\n", kind: ErrorKindParse, contains: "missing severity or summary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			det := NewExternalDetector("custom", writeScript(t, tt.body), 0)
+			results, err := Run(context.Background(), []Detector{det}, []string{"https://one.test"})
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if len(results) != 1 || results[0].ErrorKind != tt.kind || !strings.Contains(results[0].Summary, tt.contains) {
+				t.Fatalf("expected %s error result containing %q, got %+v", tt.kind, tt.contains, results)
+			}
+		})
+	}
+}
+
+func TestExternalDetectorTimesOut(t *testing.T) {
+	det := NewExternalDetector("custom", writeScript(t, "sleep 10\n"), 100*time.Millisecond)
+
+	start := time.Now()
+	results, err := Run(context.Background(), []Detector{det}, []string{"https://one.test"})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the command to be killed at its timeout, took %s", elapsed)
+	}
+	if len(results) != 1 || results[0].ErrorKind != ErrorKindTimeout || !strings.Contains(results[0].Summary, "timed out after 100ms") {
+		t.Fatalf("expected a timeout error result, got %+v", results)
+	}
+}
+
+func TestParseExternalDetector(t *testing.T) {
+	name, command, err := ParseExternalDetector("custom:/opt/checks/custom.sh", DefaultRegistry)
+	if err != nil || name != "custom" || command != "/opt/checks/custom.sh" {
+		t.Fatalf("unexpected parse result %q %q %v", name, command, err)
+	}
+
+	for _, spec := range []string{"custom", ":/bin/true", "version:/bin/true"} {
+		if _, _, err := ParseExternalDetector(spec, DefaultRegistry); err == nil {
+			t.Fatalf("expected %q to be rejected", spec)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// hardeningSignal is one posture check contributing to the hardening score. hardened
+// reports whether the probe response shows the corresponding weakness closed off.
+// A path of DefaultHomePath probes the detector's configured homepage instead.
+type hardeningSignal struct {
+	name     string
+	path     string
+	hardened func(d *HardeningDetector, resp httpResponse) bool
+}
+
+// hardeningSignals are probed in order; each carries the same weight in the score.
+var hardeningSignals = []hardeningSignal{
+	{
+		name:     "generatorStripped",
+		path:     DefaultHomePath,
+		hardened: func(d *HardeningDetector, resp httpResponse) bool { return !d.versionPattern.Match(resp.Body) },
+	},
+	{
+		name: "readmeBlocked",
+		path: "/readme.html",
+		hardened: func(_ *HardeningDetector, resp httpResponse) bool {
+			return resp.StatusCode != http.StatusOK || !bytes.Contains(bytes.ToLower(resp.Body), []byte("wordpress"))
+		},
+	},
+	{
+		// An enabled endpoint answers GET with "XML-RPC server accepts POST requests only."
+		name: "xmlrpcDisabled",
+		path: "/xmlrpc.php",
+		hardened: func(_ *HardeningDetector, resp httpResponse) bool {
+			return !bytes.Contains(resp.Body, []byte("XML-RPC server accepts POST requests only"))
+		},
+	},
+	{
+		name: "restUsersBlocked",
+		path: "/wp-json/wp/v2/users",
+		hardened: func(_ *HardeningDetector, resp httpResponse) bool {
+			return resp.StatusCode != http.StatusOK || !bytes.HasPrefix(bytes.TrimSpace(resp.Body), []byte("["))
+		},
+	},
+}
+
+// HardeningDetector summarizes how much a WordPress site hides about itself as a
+// score from 0 (nothing hidden) to 100 (every probed signal hardened).
+type HardeningDetector struct {
+	client       *http.Client
+	maxBodyBytes int64
+	explain      bool
+	// homePath and versionPattern match the version detector's settings, so a
+	// generator it finds is not scored as stripped.
+	homePath       string
+	versionPattern *regexp.Regexp
+}
+
+// NewHardeningDetector builds a detector with an optional custom HTTP client.
+func NewHardeningDetector(client *http.Client) *HardeningDetector {
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	return &HardeningDetector{client: client, maxBodyBytes: DefaultMaxBodyBytes, homePath: DefaultHomePath, versionPattern: versionRegex}
+}
+
+func newHardeningDetectorFromOptions(opts DetectorOptions) *HardeningDetector {
+	d := NewHardeningDetector(opts.Client)
+	d.explain = opts.Explain
+	if opts.HomePath != "" {
+		d.homePath = opts.HomePath
+	}
+	if opts.VersionPattern != nil {
+		d.versionPattern = opts.VersionPattern
+	}
+	return d
+}
+
+// Name implements Detector.
+func (d *HardeningDetector) Name() string {
+	return "hardening"
+}
+
+// Detect probes each hardening signal and reports the share that is hardened. The
+// per-signal outcome is kept in Metadata["signals"].
+func (d *HardeningDetector) Detect(ctx context.Context, target string) (Result, error) {
+	signals := make(map[string]bool, len(hardeningSignals))
+	var hardened, exposed []string
+	for _, signal := range hardeningSignals {
+		path := signal.path
+		if path == DefaultHomePath {
+			path = d.homePath
+		}
+		resp, err := fetch(ctx, d.client, joinTargetPath(target, path), d.maxBodyBytes)
+		if err != nil {
+			return Result{}, err
+		}
+		ok := signal.hardened(d, resp)
+		signals[signal.name] = ok
+		if ok {
+			hardened = append(hardened, signal.name)
+		} else {
+			exposed = append(exposed, signal.name)
+		}
+	}
+
+	score := len(hardened) * 100 / len(hardeningSignals)
+	return withExplanation(Result{
+		Target:   target,
+		Detector: d.Name(),
+		Severity: "info",
+		Summary:  fmt.Sprintf("WordPress hardening score %d/100", score),
+		Metadata: map[string]interface{}{"score": score, "signals": signals},
+	}, d.explain, "hardened: %s; exposed: %s", joinOrNone(hardened), joinOrNone(exposed)), nil
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,73 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHardeningDetectorScoresHardenedSite(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			// Generator tag stripped, but WordPress assets still give the site away.
+			_, _ = w.Write([]byte(`<html><link rel="stylesheet" href="/wp-content/themes/site/style.css"></html>`))
+		case "/xmlrpc.php":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_, _ = w.Write([]byte("XML-RPC server accepts POST requests only."))
+		case "/wp-json/wp/v2/users":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"rest_user_cannot_view","message":"Sorry, you are not allowed to list users."}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	res, err := NewHardeningDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Severity != "info" || res.Metadata["score"] != 75 {
+		t.Fatalf("expected info result with score 75, got %+v", res)
+	}
+
+	signals, ok := res.Metadata["signals"].(map[string]bool)
+	if !ok {
+		t.Fatalf("expected per-signal breakdown, got %T", res.Metadata["signals"])
+	}
+	want := map[string]bool{"generatorStripped": true, "readmeBlocked": true, "xmlrpcDisabled": false, "restUsersBlocked": true}
+	for name, hardened := range want {
+		if signals[name] != hardened {
+			t.Fatalf("signal %s = %v, want %v (all: %v)", name, signals[name], hardened, signals)
+		}
+	}
+}
+
+func TestHardeningDetectorUsesHomePathAndVersionPattern(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/blog/" {
+			_, _ = w.Write([]byte(`<meta name="generator" content="WordPress-Version: 6.5.1" />`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	pattern, err := ParseVersionPattern(`WordPress-Version:\s*([0-9.]+)`)
+	if err != nil {
+		t.Fatalf("parse pattern: %v", err)
+	}
+	d := newHardeningDetectorFromOptions(DetectorOptions{Client: ts.Client(), HomePath: "/blog/", VersionPattern: pattern})
+	res, err := d.Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	signals := res.Metadata["signals"].(map[string]bool)
+	if signals["generatorStripped"] {
+		t.Fatalf("expected the localized generator on the configured homepage to count as exposed, got %v", signals)
+	}
+}
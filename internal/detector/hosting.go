@@ -0,0 +1,93 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HostingHeaderConfidence reflects that provider headers are reliable but can be
+// removed or forwarded by an intermediate proxy.
+const HostingHeaderConfidence = 0.8
+
+// hostingSignature identifies a managed WordPress host by a response header. When
+// Contains is empty the header's presence alone is enough.
+type hostingSignature struct {
+	Provider string
+	Header   string
+	Contains string
+}
+
+var hostingSignatures = []hostingSignature{
+	{Provider: "WP Engine", Header: "X-Powered-By", Contains: "wp engine"},
+	{Provider: "WP Engine", Header: "Wpe-Backend"},
+	{Provider: "Kinsta", Header: "X-Kinsta-Cache"},
+	{Provider: "Kinsta", Header: "Ki-Cache-Type"},
+	{Provider: "Pantheon", Header: "X-Pantheon-Styx-Hostname"},
+	{Provider: "Pantheon", Header: "X-Styx-Req-Id"},
+	{Provider: "Flywheel", Header: "X-Fw-Hash"},
+	{Provider: "Flywheel", Header: "X-Fw-Serve"},
+	{Provider: "WordPress VIP", Header: "X-Powered-By", Contains: "wordpress vip"},
+	{Provider: "WordPress VIP", Header: "X-Powered-By", Contains: "wordpress.com vip"},
+	{Provider: "Pressable", Header: "X-Powered-By", Contains: "pressable"},
+}
+
+// HostingDetector identifies managed WordPress hosting providers from response headers.
+type HostingDetector struct {
+	client       *http.Client
+	maxBodyBytes int64
+	explain      bool
+}
+
+// NewHostingDetector builds a detector with an optional custom HTTP client.
+func NewHostingDetector(client *http.Client) *HostingDetector {
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	return &HostingDetector{client: client, maxBodyBytes: DefaultMaxBodyBytes}
+}
+
+func newHostingDetectorFromOptions(opts DetectorOptions) *HostingDetector {
+	d := NewHostingDetector(opts.Client)
+	d.explain = opts.Explain
+	return d
+}
+
+// Name implements Detector.
+func (d *HostingDetector) Name() string {
+	return "hosting"
+}
+
+// Detect fetches the target root document and matches its headers against known providers.
+func (d *HostingDetector) Detect(ctx context.Context, target string) (Result, error) {
+	resp, err := fetch(ctx, d.client, normalizeTargetURL(target), d.maxBodyBytes)
+	if err != nil {
+		return Result{}, err
+	}
+
+	for _, sig := range hostingSignatures {
+		values := resp.Header.Values(sig.Header)
+		for _, value := range values {
+			if sig.Contains != "" && !strings.Contains(strings.ToLower(value), sig.Contains) {
+				continue
+			}
+			return withExplanation(Result{
+				Target:     target,
+				Detector:   d.Name(),
+				Severity:   "info",
+				Summary:    fmt.Sprintf("Hosted on %s", sig.Provider),
+				Metadata:   map[string]interface{}{"hosting": sig.Provider, "header": sig.Header},
+				Confidence: HostingHeaderConfidence,
+			}, d.explain, "response header %s: %q matches the %s signature", sig.Header, value, sig.Provider), nil
+		}
+	}
+
+	return withExplanation(Result{
+		Target:   target,
+		Detector: d.Name(),
+		Severity: "info",
+		Summary:  "Hosting provider not identified",
+		Metadata: map[string]interface{}{"hosting": "unknown"},
+	}, d.explain, "no response header matched a known managed-hosting signature"), nil
+}
@@ -0,0 +1,43 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostingDetectorIdentifiesWPEngine(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Powered-By", "WP Engine")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer ts.Close()
+
+	res, err := NewHostingDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Severity != "info" || res.Metadata["hosting"] != "WP Engine" {
+		t.Fatalf("expected WP Engine hosting, got %+v", res)
+	}
+}
+
+func TestHostingDetectorReportsUnknownForGenericServer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx")
+		w.Header().Set("X-Powered-By", "PHP/8.2.1")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer ts.Close()
+
+	res, err := NewHostingDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Metadata["hosting"] != "unknown" {
+		t.Fatalf("expected unknown hosting, got %+v", res)
+	}
+}
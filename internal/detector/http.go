@@ -0,0 +1,109 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultHTTPTimeout bounds each detector HTTP request when no custom client is supplied.
+const DefaultHTTPTimeout = 10 * time.Second
+
+// httpResponse captures the parts of an HTTP response that detectors inspect.
+type httpResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func defaultHTTPClient() *http.Client {
+	return &http.Client{Timeout: DefaultHTTPTimeout, CheckRedirect: CheckRedirect(DefaultMaxRedirects)}
+}
+
+// fetch issues a GET request and reads at most maxBytes of the response body.
+func fetch(ctx context.Context, client *http.Client, url string, maxBytes int64) (httpResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return httpResponse{}, err
+	}
+	return send(client, req, maxBytes)
+}
+
+// post sends body with the given Content-Type and reads at most maxBytes of the
+// response body. Detectors probing endpoints such as /xmlrpc.php or REST routes use
+// it so POST requests share the scan's client, timeout, and body limit.
+func post(ctx context.Context, client *http.Client, url, contentType string, body []byte, maxBytes int64) (httpResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return httpResponse{}, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return send(client, req, maxBytes)
+}
+
+// send performs req and captures the response, reading at most maxBytes of the body.
+// The client's timeout also covers the body read, so a server that streams a chunked
+// body slowly is cut off at the deadline instead of holding the connection until
+// maxBytes arrive.
+func send(client *http.Client, req *http.Request, maxBytes int64) (httpResponse, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return httpResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes))
+	if err != nil {
+		return httpResponse{}, err
+	}
+
+	return httpResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+// joinTargetPath appends an absolute path to the normalized target URL without doubling slashes.
+func joinTargetPath(target, path string) string {
+	base := strings.TrimRight(normalizeTargetURL(target), "/")
+	return base + "/" + strings.TrimLeft(path, "/")
+}
+
+// soft404 remembers how a target answers requests for paths that cannot exist.
+// Many hosts return 200 with a generic page instead of a real 404, which would
+// otherwise turn every probe into a false positive.
+type soft404 struct {
+	path string
+	resp httpResponse
+	ok   bool
+}
+
+// newSoft404 requests a random, non-existent path on the target to capture its baseline.
+func newSoft404(ctx context.Context, client *http.Client, target string, maxBytes int64) (*soft404, error) {
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	path := "/wphunter-" + hex.EncodeToString(token)
+	resp, err := fetch(ctx, client, joinTargetPath(target, path), maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &soft404{path: path, resp: resp, ok: resp.StatusCode == http.StatusOK}, nil
+}
+
+// matches reports whether a probe response for path is indistinguishable from the baseline page.
+func (s *soft404) matches(path string, resp httpResponse) bool {
+	if s == nil || !s.ok || resp.StatusCode != s.resp.StatusCode {
+		return false
+	}
+
+	// Error pages frequently echo the requested path, so strip it before comparing.
+	baseline := bytes.ReplaceAll(s.resp.Body, []byte(s.path), nil)
+	candidate := bytes.ReplaceAll(resp.Body, []byte(path), nil)
+	return bytes.Equal(baseline, candidate)
+}
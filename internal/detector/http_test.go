@@ -0,0 +1,65 @@
+package detector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPostSendsBodyAndContentType(t *testing.T) {
+	var gotMethod, gotType, gotBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotType = r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+		_, _ = w.Write([]byte("<methodResponse>0123456789</methodResponse>"))
+	}))
+	defer ts.Close()
+
+	body := `<?xml version="1.0"?><methodCall><methodName>system.listMethods</methodName></methodCall>`
+	resp, err := post(context.Background(), ts.Client(), joinTargetPath(ts.URL, "/xmlrpc.php"), "text/xml", []byte(body), 20)
+	if err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+
+	if gotMethod != http.MethodPost || gotType != "text/xml" || gotBody != body {
+		t.Fatalf("server received %s %q with body %q", gotMethod, gotType, gotBody)
+	}
+	if resp.StatusCode != http.StatusOK || len(resp.Body) != 20 {
+		t.Fatalf("expected response body capped at 20 bytes, got %d bytes (status %d)", len(resp.Body), resp.StatusCode)
+	}
+}
+
+func TestFetchAbortsSlowDripBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Stream a chunked body one byte at a time, never finishing on its own.
+		flusher := w.(http.Flusher)
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(20 * time.Millisecond):
+				_, _ = w.Write([]byte("x"))
+				flusher.Flush()
+			}
+		}
+	}))
+	defer ts.Close()
+
+	client := &http.Client{Timeout: 200 * time.Millisecond}
+	start := time.Now()
+	_, err := fetch(context.Background(), client, ts.URL, 1<<20)
+	if err == nil {
+		t.Fatal("expected the slow body read to time out")
+	}
+	if kind := ClassifyError(err); kind != ErrorKindTimeout {
+		t.Fatalf("expected a timeout error, got %q (%v)", kind, err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected the read to stop near the timeout, took %s", elapsed)
+	}
+}
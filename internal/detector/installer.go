@@ -0,0 +1,104 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+)
+
+// InstallerConfidence is reported when install.php serves the setup form itself.
+const InstallerConfidence = 0.95
+
+// installerPath is the WordPress installer, reachable before wp-config.php points at a
+// populated database.
+const installerPath = "/wp-admin/install.php"
+
+var (
+	// installerFormMarker matches the language picker (step 0) and the site setup form
+	// (step 1), both of which let a visitor finish the installation.
+	installerFormMarker = regexp.MustCompile(`(?i)<form[^>]+id="setup"|name="weblog_title"|id="language-continue"`)
+	// installerDoneMarker matches the page shown once WordPress is installed.
+	installerDoneMarker = regexp.MustCompile(`(?i)already installed`)
+)
+
+// InstallerDetector flags WordPress installers left open to the internet. An
+// unfinished installation lets anyone create the admin account and take over the site.
+type InstallerDetector struct {
+	client       *http.Client
+	maxBodyBytes int64
+	explain      bool
+}
+
+// NewInstallerDetector builds a detector with an optional custom HTTP client.
+func NewInstallerDetector(client *http.Client) *InstallerDetector {
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	return &InstallerDetector{client: client, maxBodyBytes: DefaultMaxBodyBytes}
+}
+
+func newInstallerDetectorFromOptions(opts DetectorOptions) *InstallerDetector {
+	d := NewInstallerDetector(opts.Client)
+	d.explain = opts.Explain
+	return d
+}
+
+// Name implements Detector.
+func (d *InstallerDetector) Name() string {
+	return "installer"
+}
+
+// Detect requests install.php and reports a critical finding when it serves the
+// installation form rather than the "already installed" page.
+func (d *InstallerDetector) Detect(ctx context.Context, target string) (Result, error) {
+	baseline, err := newSoft404(ctx, d.client, target, d.maxBodyBytes)
+	if err != nil {
+		return Result{}, err
+	}
+
+	url := joinTargetPath(target, installerPath)
+	resp, err := fetch(ctx, d.client, url, d.maxBodyBytes)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// A page that was reached but carries neither marker is "unknown", distinct from
+	// a missing or soft-404 install.php.
+	state := "unknown"
+	switch {
+	case resp.StatusCode != http.StatusOK || baseline.matches(installerPath, resp):
+		state = "not-found"
+	case installerDoneMarker.Match(resp.Body):
+		state = "installed"
+	case installerFormMarker.Match(resp.Body):
+		state = "open"
+	}
+
+	if state != "open" {
+		summary := "WordPress installer is not exposed"
+		if state == "installed" {
+			summary = "WordPress installer reports the site is already installed"
+		}
+		explanation := "%s answered HTTP %d without the installation form"
+		if state == "not-found" {
+			explanation = "%s answered HTTP %d with a missing or soft-404 page"
+		}
+		return withExplanation(Result{
+			Target:   target,
+			Detector: d.Name(),
+			Severity: "info",
+			Summary:  summary,
+			Metadata: map[string]interface{}{"state": state, "url": url},
+		}, d.explain, explanation, url, resp.StatusCode), nil
+	}
+
+	return withExplanation(Result{
+		Target:     target,
+		Detector:   d.Name(),
+		Severity:   "critical",
+		Summary:    "WordPress installer is exposed; anyone can finish setup and take over the site",
+		Metadata:   map[string]interface{}{"state": state, "url": url},
+		Confidence: InstallerConfidence,
+		Tags:       []string{TagOWASPMisconfiguration},
+	}, d.explain, "%s served the installation setup form, which differs from the soft-404 page", url), nil
+}
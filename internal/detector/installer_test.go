@@ -0,0 +1,77 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInstallerDetector(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantSeverity string
+		wantState    string
+	}{
+		{
+			name:         "active installer",
+			body:         `<html><body><form id="setup" method="post" action="install.php?step=2"><input name="weblog_title" type="text"></form></body></html>`,
+			wantSeverity: "critical",
+			wantState:    "open",
+		},
+		{
+			name:         "already installed",
+			body:         `<html><body><h1>Already Installed</h1><p>You appear to have already installed WordPress.</p></body></html>`,
+			wantSeverity: "info",
+			wantState:    "installed",
+		},
+		{
+			name:         "reached without markers",
+			body:         `<html><body><h1>Maintenance</h1><p>Back soon.</p></body></html>`,
+			wantSeverity: "info",
+			wantState:    "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == installerPath {
+					_, _ = w.Write([]byte(tt.body))
+					return
+				}
+				http.NotFound(w, r)
+			}))
+			defer ts.Close()
+
+			res, err := NewInstallerDetector(ts.Client()).Detect(context.Background(), ts.URL)
+			if err != nil {
+				t.Fatalf("detect failed: %v", err)
+			}
+
+			if res.Severity != tt.wantSeverity {
+				t.Fatalf("expected %s severity, got %+v", tt.wantSeverity, res)
+			}
+			if res.Metadata["state"] != tt.wantState {
+				t.Fatalf("expected state %q, got %v", tt.wantState, res.Metadata["state"])
+			}
+		})
+	}
+}
+
+func TestInstallerDetectorIgnoresSoft404(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><form id="setup"></form></body></html>`))
+	}))
+	defer ts.Close()
+
+	res, err := NewInstallerDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Severity != "info" || res.Metadata["state"] != "not-found" {
+		t.Fatalf("expected soft-404 responses to be ignored as not-found, got %+v", res)
+	}
+}
@@ -0,0 +1,84 @@
+package detector
+
+import "strings"
+
+// Canonical metadata keys shared by detectors and downstream consumers.
+const (
+	MetaVersion       = "version"
+	MetaSource        = "source"
+	MetaSources       = "sources"
+	MetaSlug          = "slug"
+	MetaCVE           = "cve"
+	MetaCVESeverity   = "cveSeverity"
+	MetaExplanation   = "explanation"
+	MetaRedirectChain = "redirectChain"
+	MetaPanic         = "panic"
+)
+
+// Canonical values for MetaSource.
+const (
+	// SourceGeneratorMeta marks versions read from a <meta name="generator"> tag.
+	SourceGeneratorMeta = "meta-generator"
+	// SourceReadme marks versions read from /readme.html.
+	SourceReadme = "readme"
+	// SourceAssetVersion marks versions read from ?ver= on core assets.
+	SourceAssetVersion = "asset-version"
+)
+
+// metadataKeyAliases maps spellings seen in detector output (including external
+// detectors) onto the canonical keys. Lookups are case-insensitive.
+var metadataKeyAliases = map[string]string{
+	"version":        MetaVersion,
+	"source":         MetaSource,
+	"sources":        MetaSources,
+	"src":            MetaSource,
+	"slug":           MetaSlug,
+	"cve":            MetaCVE,
+	"cves":           MetaCVE,
+	"cveseverity":    MetaCVESeverity,
+	"cve_severity":   MetaCVESeverity,
+	"explanation":    MetaExplanation,
+	"redirectchain":  MetaRedirectChain,
+	"redirect_chain": MetaRedirectChain,
+	"panic":          MetaPanic,
+}
+
+// sourceAliases maps alternative MetaSource values onto the canonical ones.
+var sourceAliases = map[string]string{
+	"meta-generator": SourceGeneratorMeta,
+	"meta_generator": SourceGeneratorMeta,
+	"generator-meta": SourceGeneratorMeta,
+	"generator_meta": SourceGeneratorMeta,
+	"generator":      SourceGeneratorMeta,
+}
+
+// NormalizeMetadata rewrites known metadata keys and source values to their
+// canonical form so artifacts stay consistent across detectors. Unknown keys are
+// kept as they are; a canonical key wins over an alias carrying the same meaning.
+func NormalizeMetadata(res Result) Result {
+	if len(res.Metadata) == 0 {
+		return res
+	}
+
+	normalized := make(map[string]interface{}, len(res.Metadata))
+	for key, value := range res.Metadata {
+		canonical, ok := metadataKeyAliases[strings.ToLower(key)]
+		if !ok {
+			normalized[key] = value
+			continue
+		}
+		if _, exists := normalized[canonical]; exists && key != canonical {
+			continue
+		}
+		normalized[canonical] = value
+	}
+
+	if source, ok := normalized[MetaSource].(string); ok {
+		if canonical, known := sourceAliases[strings.ToLower(strings.TrimSpace(source))]; known {
+			normalized[MetaSource] = canonical
+		}
+	}
+
+	res.Metadata = normalized
+	return res
+}
@@ -0,0 +1,41 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNormalizeMetadataCanonicalizesKeysAndSource(t *testing.T) {
+	res := NormalizeMetadata(Result{Metadata: map[string]interface{}{
+		"Version": "6.5.1",
+		"src":     "generator_meta",
+		"custom":  true,
+	}})
+
+	if res.Metadata[MetaVersion] != "6.5.1" || res.Metadata[MetaSource] != SourceGeneratorMeta {
+		t.Fatalf("expected canonical version and source, got %v", res.Metadata)
+	}
+	if _, ok := res.Metadata["src"]; ok {
+		t.Fatalf("expected alias key to be replaced, got %v", res.Metadata)
+	}
+	if res.Metadata["custom"] != true {
+		t.Fatalf("expected unknown keys to be kept, got %v", res.Metadata)
+	}
+}
+
+func TestRunNormalizesVersionDetectorSource(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<meta name="generator" content="WordPress 6.5.1" />`))
+	}))
+	defer ts.Close()
+
+	results, err := Run(context.Background(), []Detector{NewVersionDetector(ts.Client())}, []string{ts.URL})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("run: %v (%d results)", err, len(results))
+	}
+	if results[0].Metadata[MetaSource] != SourceGeneratorMeta {
+		t.Fatalf("expected source %q, got %v", SourceGeneratorMeta, results[0].Metadata)
+	}
+}
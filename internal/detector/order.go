@@ -0,0 +1,91 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DependentDetector is implemented by detectors that must run after others, for
+// example a correlation pass that consumes enumeration results. Dependencies
+// returns the names of the prerequisite detectors.
+type DependentDetector interface {
+	Detector
+	Dependencies() []string
+}
+
+type priorResultsKey struct{}
+
+// PriorResults returns the results already produced for the current target by
+// detectors that ran earlier in Run. Dependents use it to read their prerequisites' output.
+func PriorResults(ctx context.Context) []Result {
+	results, _ := ctx.Value(priorResultsKey{}).([]Result)
+	return results
+}
+
+func withPriorResults(ctx context.Context, results []Result) context.Context {
+	return context.WithValue(ctx, priorResultsKey{}, results)
+}
+
+// OrderDetectors sorts detectors so every detector runs after its dependencies.
+// Detectors without ordering constraints keep their relative order. Missing
+// dependencies and cycles are rejected.
+func OrderDetectors(detectors []Detector) ([]Detector, error) {
+	byName := make(map[string]int, len(detectors))
+	for i, det := range detectors {
+		byName[det.Name()] = i
+	}
+
+	deps := make([][]int, len(detectors))
+	for i, det := range detectors {
+		dependent, ok := det.(DependentDetector)
+		if !ok {
+			continue
+		}
+		for _, name := range dependent.Dependencies() {
+			j, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("detector %s depends on %s, which is not enabled", det.Name(), name)
+			}
+			deps[i] = append(deps[i], j)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(detectors))
+	ordered := make([]Detector, 0, len(detectors))
+	var path []string
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("detector dependency cycle: %s -> %s", strings.Join(path, " -> "), detectors[i].Name())
+		}
+
+		state[i] = visiting
+		path = append(path, detectors[i].Name())
+		for _, j := range deps[i] {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = done
+		ordered = append(ordered, detectors[i])
+		return nil
+	}
+
+	for i := range detectors {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,82 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// PHPEndOfLifeBelow is the first PHP release still considered supported; older versions are flagged.
+const PHPEndOfLifeBelow = "8.0"
+
+// PHPHeaderConfidence reflects that version headers are accurate when present but easily spoofed.
+const PHPHeaderConfidence = 0.9
+
+var phpVersionRegex = regexp.MustCompile(`PHP/([0-9]+\.[0-9]+(\.[0-9]+)?)`)
+
+// PHPDetector reports the PHP version disclosed by the target's response headers.
+type PHPDetector struct {
+	client       *http.Client
+	maxBodyBytes int64
+	explain      bool
+}
+
+// NewPHPDetector builds a detector with an optional custom HTTP client.
+func NewPHPDetector(client *http.Client) *PHPDetector {
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	return &PHPDetector{client: client, maxBodyBytes: DefaultMaxBodyBytes}
+}
+
+func newPHPDetectorFromOptions(opts DetectorOptions) *PHPDetector {
+	d := NewPHPDetector(opts.Client)
+	d.explain = opts.Explain
+	return d
+}
+
+// Name implements Detector.
+func (d *PHPDetector) Name() string {
+	return "php"
+}
+
+// Detect fetches the target root document and inspects X-Powered-By and Server headers.
+func (d *PHPDetector) Detect(ctx context.Context, target string) (Result, error) {
+	resp, err := fetch(ctx, d.client, normalizeTargetURL(target), d.maxBodyBytes)
+	if err != nil {
+		return Result{}, err
+	}
+
+	for _, header := range []string{"X-Powered-By", "Server"} {
+		raw := resp.Header.Get(header)
+		matches := phpVersionRegex.FindStringSubmatch(raw)
+		if len(matches) < 2 {
+			continue
+		}
+
+		version := matches[1]
+		res := Result{
+			Target:     target,
+			Detector:   d.Name(),
+			Severity:   "info",
+			Summary:    fmt.Sprintf("PHP version %s detected", version),
+			Metadata:   map[string]interface{}{MetaVersion: version, MetaSource: header, "header": raw},
+			Confidence: PHPHeaderConfidence,
+		}
+		if CompareVersions(version, PHPEndOfLifeBelow) < 0 {
+			res.Severity = "warning"
+			res.Summary = fmt.Sprintf("End-of-life PHP version %s detected", version)
+			res.Tags = []string{TagOWASPOutdated}
+		}
+		return withExplanation(res, d.explain, "parsed `%s` from the %s response header", matches[0], header), nil
+	}
+
+	return withExplanation(Result{
+		Target:   target,
+		Detector: d.Name(),
+		Severity: "info",
+		Summary:  "PHP version not disclosed in response headers",
+		Metadata: map[string]interface{}{MetaVersion: "unknown"},
+	}, d.explain, "neither the X-Powered-By nor the Server header contained a PHP/x.y version"), nil
+}
@@ -0,0 +1,46 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPHPDetectorFlagsEndOfLifeVersion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Powered-By", "PHP/7.4")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer ts.Close()
+
+	res, err := NewPHPDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Severity != "warning" {
+		t.Fatalf("expected warning for PHP 7.4, got %s", res.Severity)
+	}
+
+	if res.Metadata["version"] != "7.4" || res.Metadata["header"] != "PHP/7.4" {
+		t.Fatalf("unexpected metadata: %v", res.Metadata)
+	}
+}
+
+func TestPHPDetectorReportsUnknownWhenHidden(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer ts.Close()
+
+	res, err := NewPHPDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Severity != "info" || res.Metadata["version"] != "unknown" {
+		t.Fatalf("expected unknown info result, got %+v", res)
+	}
+}
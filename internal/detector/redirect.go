@@ -0,0 +1,57 @@
+package detector
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxRedirects matches net/http's default limit on redirect hops.
+const DefaultMaxRedirects = 10
+
+// RedirectError reports a redirect chain that looped or exceeded the hop limit.
+type RedirectError struct {
+	// Chain lists every URL visited, ending with the one that was refused.
+	Chain []string
+	Loop  bool
+}
+
+func (e *RedirectError) Error() string {
+	if e.Loop {
+		return fmt.Sprintf("redirect loop detected: %s", strings.Join(e.Chain, " -> "))
+	}
+	return fmt.Sprintf("stopped after %d redirects: %s", len(e.Chain)-1, strings.Join(e.Chain, " -> "))
+}
+
+// CheckRedirect returns an http.Client CheckRedirect policy that refuses to revisit a
+// URL already in the chain and stops after maxHops redirects.
+func CheckRedirect(maxHops int) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		chain := make([]string, 0, len(via)+1)
+		for _, prev := range via {
+			chain = append(chain, prev.URL.String())
+		}
+		next := req.URL.String()
+		chain = append(chain, next)
+
+		for _, visited := range chain[:len(chain)-1] {
+			if visited == next {
+				return &RedirectError{Chain: chain, Loop: true}
+			}
+		}
+		if len(via) >= maxHops {
+			return &RedirectError{Chain: chain}
+		}
+		return nil
+	}
+}
+
+// redirectChain returns the chain recorded in err, if err is a RedirectError.
+func redirectChain(err error) []string {
+	var redirectErr *RedirectError
+	if errors.As(err, &redirectErr) {
+		return redirectErr.Chain
+	}
+	return nil
+}
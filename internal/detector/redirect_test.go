@@ -0,0 +1,56 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedirectLoopIsDetectedAndReported(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/", "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/a", http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := ts.Client()
+	client.CheckRedirect = CheckRedirect(DefaultMaxRedirects)
+
+	results, err := Run(context.Background(), []Detector{NewPHPDetector(client)}, []string{ts.URL + "/a"})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	res := results[0]
+	if res.ErrorKind != ErrorKindRedirect || !strings.Contains(res.Summary, "redirect loop detected") {
+		t.Fatalf("expected redirect loop error result, got %+v", res)
+	}
+
+	chain, ok := res.Metadata["redirectChain"].([]string)
+	if !ok || len(chain) != 3 || chain[0] != ts.URL+"/a" || chain[1] != ts.URL+"/b" || chain[2] != ts.URL+"/a" {
+		t.Fatalf("expected chain a -> b -> a, got %v", res.Metadata["redirectChain"])
+	}
+}
+
+func TestCheckRedirectStopsAfterMaxHops(t *testing.T) {
+	hops := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hops++
+		http.Redirect(w, r, "/hop/"+strings.Repeat("x", hops), http.StatusFound)
+	}))
+	defer ts.Close()
+
+	client := ts.Client()
+	client.CheckRedirect = CheckRedirect(3)
+
+	_, err := fetch(context.Background(), client, ts.URL, DefaultMaxBodyBytes)
+	if err == nil || !strings.Contains(err.Error(), "stopped after 3 redirects") || ClassifyError(err) != ErrorKindRedirect {
+		t.Fatalf("expected hop limit error, got %v", err)
+	}
+}
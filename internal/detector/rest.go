@@ -0,0 +1,99 @@
+package detector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// restDisabledCodes are WordPress error codes returned at /wp-json/ when a site has
+// switched the REST API off (via a filter or security plugin) rather than lacking it.
+var restDisabledCodes = map[string]struct{}{
+	"rest_disabled":       {},
+	"rest_no_route":       {},
+	"rest_cannot_access":  {},
+	"rest_login_required": {},
+	"rest_not_logged_in":  {},
+}
+
+// restIndex is the subset of a /wp-json/ response the REST detector inspects. Enabled
+// sites return a namespace listing; disabled ones return a WP_Error with a code.
+type restIndex struct {
+	Namespaces []string `json:"namespaces"`
+	Code       string   `json:"code"`
+	Message    string   `json:"message"`
+}
+
+// RESTDetector reports whether the WordPress REST API is exposed at /wp-json/.
+type RESTDetector struct {
+	client       *http.Client
+	maxBodyBytes int64
+	explain      bool
+}
+
+// NewRESTDetector builds a detector with an optional custom HTTP client.
+func NewRESTDetector(client *http.Client) *RESTDetector {
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	return &RESTDetector{client: client, maxBodyBytes: DefaultMaxBodyBytes}
+}
+
+func newRESTDetectorFromOptions(opts DetectorOptions) *RESTDetector {
+	d := NewRESTDetector(opts.Client)
+	d.explain = opts.Explain
+	return d
+}
+
+// Name implements Detector.
+func (d *RESTDetector) Name() string {
+	return "rest"
+}
+
+// Detect requests /wp-json/ and distinguishes an enabled API, an intentionally
+// disabled one, and a target that does not serve the WordPress REST API at all.
+func (d *RESTDetector) Detect(ctx context.Context, target string) (Result, error) {
+	resp, err := fetch(ctx, d.client, joinTargetPath(target, "/wp-json/"), d.maxBodyBytes)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var index restIndex
+	if err := json.Unmarshal(resp.Body, &index); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return Result{}, &StatusError{StatusCode: resp.StatusCode}
+		}
+		return Result{}, &ParseError{Msg: "wp-json response is not JSON"}
+	}
+
+	if _, disabled := restDisabledCodes[index.Code]; disabled {
+		return withExplanation(Result{
+			Target:   target,
+			Detector: d.Name(),
+			Severity: "info",
+			Summary:  fmt.Sprintf("WordPress REST API disabled (%s)", index.Code),
+			Metadata: map[string]interface{}{"rest": "disabled", "code": index.Code, "status": resp.StatusCode},
+		}, d.explain, "/wp-json/ returned HTTP %d with WordPress error code `%s`", resp.StatusCode, index.Code), nil
+	}
+
+	if resp.StatusCode == http.StatusOK && index.Namespaces != nil {
+		sort.Strings(index.Namespaces)
+		return withExplanation(Result{
+			Target:   target,
+			Detector: d.Name(),
+			Severity: "info",
+			Summary:  fmt.Sprintf("WordPress REST API enabled (%d namespaces)", len(index.Namespaces)),
+			Metadata: map[string]interface{}{"rest": "enabled", "namespaces": index.Namespaces},
+		}, d.explain, "/wp-json/ returned a namespace listing"), nil
+	}
+
+	if index.Code != "" {
+		return Result{}, &ParseError{Msg: fmt.Sprintf("unexpected wp-json error code %q", index.Code)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, &StatusError{StatusCode: resp.StatusCode}
+	}
+	return Result{}, &ParseError{Msg: "wp-json response has no namespace listing"}
+}
@@ -0,0 +1,62 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRESTDetectorReportsDisabledAPI(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wp-json/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"code":"rest_disabled","message":"The REST API is disabled on this site.","data":{"status":401}}`))
+	}))
+	defer ts.Close()
+
+	res, err := NewRESTDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Severity != "info" || res.Metadata["rest"] != "disabled" || res.Metadata["code"] != "rest_disabled" {
+		t.Fatalf("expected disabled REST result, got %+v", res)
+	}
+}
+
+func TestRESTDetectorReportsNamespaceListing(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"Blog","namespaces":["wp/v2","oembed/1.0"],"routes":{}}`))
+	}))
+	defer ts.Close()
+
+	res, err := NewRESTDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	namespaces, ok := res.Metadata["namespaces"].([]string)
+	if res.Metadata["rest"] != "enabled" || !ok || len(namespaces) != 2 || namespaces[0] != "oembed/1.0" {
+		t.Fatalf("expected enabled REST result with sorted namespaces, got %+v", res)
+	}
+}
+
+func TestRESTDetectorRejectsNonWordPress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	_, err := NewRESTDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status error for non-WordPress target, got %v", err)
+	}
+}
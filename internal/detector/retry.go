@@ -0,0 +1,121 @@
+package detector
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRetries is how many times a rate-limited request is retried.
+	DefaultRetries = 2
+	// DefaultRetryBackoff is the wait before a retry when the server sends no Retry-After.
+	DefaultRetryBackoff = time.Second
+	// MaxRetryAfter caps how long a server-provided Retry-After may stall a request.
+	// It stays well below DefaultHTTPTimeout, whose deadline also covers the wait.
+	MaxRetryAfter = 5 * time.Second
+)
+
+// RetryTransport is an http.RoundTripper that retries requests answered with 429 Too
+// Many Requests or 503 Service Unavailable. It waits for the response's Retry-After
+// (delta-seconds or HTTP-date, capped at MaxRetryAfter) and falls back to Backoff
+// when the header is missing or invalid. A wait that would outlast the request's
+// deadline is not attempted; the rate-limited response is returned instead.
+type RetryTransport struct {
+	Base    http.RoundTripper
+	Retries int
+	Backoff time.Duration
+
+	now func() time.Time
+}
+
+// WithRetry wraps base (http.DefaultTransport when nil) so rate-limited requests are
+// retried up to retries times. Non-positive values fall back to the defaults.
+func WithRetry(base http.RoundTripper, retries int, backoff time.Duration) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if retries <= 0 {
+		retries = DefaultRetries
+	}
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	return &RetryTransport{Base: base, Retries: retries, Backoff: backoff, now: time.Now}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.Base.RoundTrip(req)
+		if err != nil || attempt >= t.Retries || !retryableStatus(resp.StatusCode) {
+			return resp, err
+		}
+
+		wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), t.now())
+		if !ok {
+			wait = t.Backoff
+		}
+		if wait > MaxRetryAfter {
+			wait = MaxRetryAfter
+		}
+		if deadline, ok := req.Context().Deadline(); ok && !t.now().Add(wait).Before(deadline) {
+			return resp, nil
+		}
+
+		// Requests whose body cannot be replayed are returned as-is.
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, DefaultMaxBodyBytes))
+		resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+}
+
+// parseRetryAfter reads a Retry-After value given as delta-seconds or an HTTP-date.
+// Dates in the past yield a zero wait.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait := at.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
@@ -2,22 +2,65 @@ package detector
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // Registry maps detector names to constructors.
 type Registry map[string]Factory
 
-// Factory builds a detector instance.
-type Factory func() Detector
+// Factory builds a detector instance configured with the shared options.
+type Factory func(opts DetectorOptions) Detector
 
 // DefaultRegistry contains built-in detectors.
 var DefaultRegistry = Registry{
-	"version": func() Detector { return NewVersionDetector(nil) },
+	"version":    func(opts DetectorOptions) Detector { return newVersionDetectorFromOptions(opts) },
+	"vcs":        func(opts DetectorOptions) Detector { return newVCSExposureDetectorFromOptions(opts) },
+	"php":        func(opts DetectorOptions) Detector { return newPHPDetectorFromOptions(opts) },
+	"admintools": func(opts DetectorOptions) Detector { return newAdminToolsDetectorFromOptions(opts) },
+	"hosting":    func(opts DetectorOptions) Detector { return newHostingDetectorFromOptions(opts) },
+	"rest":       func(opts DetectorOptions) Detector { return newRESTDetectorFromOptions(opts) },
+	"hardening":  func(opts DetectorOptions) Detector { return newHardeningDetectorFromOptions(opts) },
+	"installer":  func(opts DetectorOptions) Detector { return newInstallerDetectorFromOptions(opts) },
+	"status":     func(opts DetectorOptions) Detector { return newStatusDetectorFromOptions(opts) },
+	"cache":      func(opts DetectorOptions) Detector { return newCacheDetectorFromOptions(opts) },
+	"staging":    func(opts DetectorOptions) Detector { return newStagingDetectorFromOptions(opts) },
+}
+
+// Clone returns a copy of r that can be extended without changing r.
+func (r Registry) Clone() Registry {
+	clone := make(Registry, len(r))
+	for name, factory := range r {
+		clone[name] = factory
+	}
+	return clone
+}
+
+// RemoveDisabled drops disabled detector names from names, matching case-insensitively,
+// and returns the names kept and, in order, the requested names that were removed.
+func RemoveDisabled(names, disabled []string) (kept, removed []string) {
+	if len(disabled) == 0 {
+		return names, nil
+	}
+
+	blocked := make(map[string]struct{}, len(disabled))
+	for _, name := range disabled {
+		blocked[strings.ToLower(name)] = struct{}{}
+	}
+	for _, name := range names {
+		if _, ok := blocked[strings.ToLower(name)]; ok {
+			removed = append(removed, name)
+			continue
+		}
+		kept = append(kept, name)
+	}
+	return kept, removed
 }
 
 // BuildDetectors instantiates detectors from the provided names.
-func (r Registry) BuildDetectors(names []string) ([]Detector, error) {
+func (r Registry) BuildDetectors(names []string, opts DetectorOptions) ([]Detector, error) {
 	if len(names) == 0 {
 		return nil, nil
 	}
@@ -33,39 +76,131 @@ func (r Registry) BuildDetectors(names []string) ([]Detector, error) {
 			continue
 		}
 		seen[name] = struct{}{}
-		detectors = append(detectors, factory())
+		detectors = append(detectors, factory(opts))
 	}
-	return detectors, nil
+
+	// Surface missing dependencies and cycles when the detector set is built
+	// rather than halfway through a scan.
+	return OrderDetectors(detectors)
+}
+
+// targetTimeoutKey carries the per-target time budget set by WithTargetTimeout.
+type targetTimeoutKey struct{}
+
+// WithTargetTimeout makes Run give all detectors of one target at most timeout in
+// total. Detectors still pending when it elapses are skipped with ErrorKindTargetTimeout
+// results. A non-positive timeout leaves targets unbounded.
+func WithTargetTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, targetTimeoutKey{}, timeout)
 }
 
-// Run executes detectors sequentially for each target.
+// Run executes detectors sequentially for each target. Detectors are ordered so
+// dependencies run first, and each detector can read the results produced earlier
+// for the same target via PriorResults. When ctx carries a tracer (see WithTracer),
+// each target's detection is wrapped in a span, and when it carries a per-target
+// timeout (see WithTargetTimeout), each target's detectors share that deadline.
 func Run(ctx context.Context, detectors []Detector, targets []string) ([]Result, error) {
 	if len(detectors) == 0 || len(targets) == 0 {
 		return nil, nil
 	}
 
+	ordered, err := OrderDetectors(detectors)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, _ := ctx.Value(targetTimeoutKey{}).(time.Duration)
+
 	var results []Result
 	for _, target := range targets {
-		for _, detector := range detectors {
+		targetCtx, span := startTargetSpan(ctx, target)
+		cancel := context.CancelFunc(func() {})
+		if timeout > 0 {
+			targetCtx, cancel = context.WithTimeout(targetCtx, timeout)
+		}
+		var targetResults []Result
+		for _, detector := range ordered {
 			select {
 			case <-ctx.Done():
-				return results, ctx.Err()
+				cancel()
+				endTargetSpan(span, targetResults)
+				return append(results, targetResults...), ctx.Err()
 			default:
 			}
+			if targetCtx.Err() != nil {
+				targetResults = append(targetResults, Result{
+					Target:     target,
+					Detector:   detector.Name(),
+					Severity:   "info",
+					Summary:    fmt.Sprintf("detector %s skipped: target exceeded its %s timeout", detector.Name(), timeout),
+					ErrorKind:  ErrorKindTargetTimeout,
+					DetectedAt: time.Now().UTC(),
+				})
+				continue
+			}
 
-			result, err := detector.Detect(ctx, target)
+			start := time.Now()
+			result, err := detectSafely(withPriorResults(targetCtx, targetResults), detector, target)
+			elapsed := time.Since(start).Milliseconds()
 			if err != nil {
-				results = append(results, Result{
-					Target:   target,
-					Detector: detector.Name(),
-					Severity: "info",
-					Summary:  fmt.Sprintf("detector error: %v", err),
-				})
+				errResult := Result{
+					Target:     target,
+					Detector:   detector.Name(),
+					Severity:   "info",
+					Summary:    fmt.Sprintf("detector error: %v", err),
+					ErrorKind:  ClassifyError(err),
+					Err:        err,
+					DetectedAt: time.Now().UTC(),
+					DurationMs: elapsed,
+				}
+				if targetCtx.Err() != nil && ctx.Err() == nil {
+					// The shared target deadline, not the detector, cut the run short.
+					errResult.ErrorKind = ErrorKindTargetTimeout
+				}
+				if chain := redirectChain(err); chain != nil {
+					errResult.Metadata = map[string]interface{}{MetaRedirectChain: chain}
+				}
+				var panicErr *PanicError
+				if errors.As(err, &panicErr) {
+					errResult.Summary = fmt.Sprintf("detector %s panicked", detector.Name())
+					if errResult.Metadata == nil {
+						errResult.Metadata = map[string]interface{}{}
+					}
+					errResult.Metadata[MetaPanic] = panicErr.Value
+				}
+				targetResults = append(targetResults, errResult)
 				continue
 			}
-			results = append(results, result)
+			if result.DetectedAt.IsZero() {
+				result.DetectedAt = time.Now().UTC()
+			}
+			result.DurationMs = elapsed
+			targetResults = append(targetResults, NormalizeMetadata(result))
 		}
+		cancel()
+		endTargetSpan(span, targetResults)
+		results = append(results, targetResults...)
 	}
 
 	return results, nil
 }
+
+// detectSafely runs det.Detect, converting a panic into a *PanicError so one faulty
+// detector cannot abort the scan and lose every other result.
+func detectSafely(ctx context.Context, det Detector, target string) (res Result, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			res, err = Result{}, newPanicError(recovered)
+		}
+	}()
+	return det.Detect(ctx, target)
+}
+
+// GroupByTarget buckets results per target, preserving the order results were produced in.
+func GroupByTarget(results []Result) map[string][]Result {
+	grouped := make(map[string][]Result)
+	for _, res := range results {
+		grouped[res.Target] = append(grouped[res.Target], res)
+	}
+	return grouped
+}
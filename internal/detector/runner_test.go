@@ -2,8 +2,15 @@ package detector
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 type fakeDetector struct {
@@ -34,12 +41,56 @@ func TestRunAggregatesResults(t *testing.T) {
 	}
 }
 
+type panickingDetector struct{}
+
+func (panickingDetector) Name() string { return "panicky" }
+
+func (panickingDetector) Detect(ctx context.Context, target string) (Result, error) {
+	panic("index out of range")
+}
+
+func TestRunRecoversDetectorPanic(t *testing.T) {
+	dets := []Detector{
+		panickingDetector{},
+		fakeDetector{name: "after", result: Result{Target: "https://example", Detector: "after", Severity: "info"}},
+	}
+
+	results, err := Run(context.Background(), dets, []string{"https://example", "https://other"})
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected results for every detector and target, got %d: %+v", len(results), results)
+	}
+
+	panicked := results[0]
+	if panicked.Detector != "panicky" || panicked.Summary != "detector panicky panicked" || panicked.Severity != "info" {
+		t.Fatalf("unexpected panic result: %+v", panicked)
+	}
+	if panicked.ErrorKind != ErrorKindPanic || panicked.Metadata[MetaPanic] != "index out of range" {
+		t.Fatalf("expected panic kind and recovered value, got %+v", panicked)
+	}
+	if results[1].Detector != "after" || results[1].Err != nil {
+		t.Fatalf("expected the next detector to still run, got %+v", results[1])
+	}
+}
+
+func TestNewPanicErrorTruncatesOnRuneBoundary(t *testing.T) {
+	// "é" is two bytes, so a byte cut at maxPanicValueLen would split one.
+	value := "x" + strings.Repeat("é", maxPanicValueLen)
+	got := newPanicError(value).Value
+
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "...") || len(got) > maxPanicValueLen+len("...") {
+		t.Fatalf("expected a valid UTF-8 value truncated to %d bytes, got %d bytes", maxPanicValueLen, len(got))
+	}
+}
+
 func TestRegistryBuildDetectors(t *testing.T) {
 	r := Registry{
-		"fake": func() Detector { return fakeDetector{name: "fake"} },
+		"fake": func(DetectorOptions) Detector { return fakeDetector{name: "fake"} },
 	}
 
-	dets, err := r.BuildDetectors([]string{"fake"})
+	dets, err := r.BuildDetectors([]string{"fake"}, DetectorOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -48,3 +99,273 @@ func TestRegistryBuildDetectors(t *testing.T) {
 		t.Fatalf("unexpected detectors: %#v", dets)
 	}
 }
+
+func TestGroupByTarget(t *testing.T) {
+	results := []Result{
+		{Target: "https://one.test", Detector: "version"},
+		{Target: "https://two.test", Detector: "version"},
+		{Target: "https://one.test", Detector: "vcs"},
+	}
+
+	grouped := GroupByTarget(results)
+	if len(grouped) != 2 {
+		t.Fatalf("expected one key per target, got %d", len(grouped))
+	}
+
+	one := grouped["https://one.test"]
+	if len(one) != 2 || one[0].Detector != "version" || one[1].Detector != "vcs" {
+		t.Fatalf("unexpected results for one.test: %+v", one)
+	}
+
+	if len(grouped["https://two.test"]) != 1 {
+		t.Fatalf("unexpected results for two.test: %+v", grouped["https://two.test"])
+	}
+}
+
+func TestRunRecordsErrorKind(t *testing.T) {
+	statusErr := &StatusError{StatusCode: 503}
+	dets := []Detector{fakeDetector{name: "broken", err: statusErr}}
+
+	results, err := Run(context.Background(), dets, []string{"https://example"})
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+
+	if !errors.Is(results[0].Err, statusErr) {
+		t.Fatalf("expected underlying error to be preserved, got %v", results[0].Err)
+	}
+
+	if results[0].ErrorKind != ErrorKindHTTPStatus {
+		t.Fatalf("expected error kind %s, got %s", ErrorKindHTTPStatus, results[0].ErrorKind)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "deadline", err: fmt.Errorf("get: %w", context.DeadlineExceeded), expected: ErrorKindTimeout},
+		{name: "dial", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, expected: ErrorKindConnection},
+		{name: "dns", err: &net.DNSError{Err: "no such host", Name: "missing.test"}, expected: ErrorKindConnection},
+		{name: "status", err: &StatusError{StatusCode: 404}, expected: ErrorKindHTTPStatus},
+		{name: "parse", err: &ParseError{Msg: "no generator"}, expected: ErrorKindParse},
+		{name: "other", err: errors.New("boom"), expected: ErrorKindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRunStampsDetectedAt(t *testing.T) {
+	before := time.Now().UTC()
+	dets := []Detector{
+		fakeDetector{name: "ok", result: Result{Target: "https://example", Detector: "ok", Severity: "info"}},
+		fakeDetector{name: "broken", err: errors.New("boom")},
+	}
+
+	results, err := Run(context.Background(), dets, []string{"https://example"})
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+
+	for _, res := range results {
+		if res.DetectedAt.Before(before) {
+			t.Fatalf("expected %s result to carry a detection time, got %v", res.Detector, res.DetectedAt)
+		}
+	}
+}
+
+func TestResultJSONOmitsUnsetDetectedAt(t *testing.T) {
+	unset, err := json.Marshal(Result{Target: "https://example", Detector: "ok", Severity: "info"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(unset), "detectedAt") {
+		t.Fatalf("expected no detectedAt for an unset time, got %s", unset)
+	}
+
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	set, err := json.Marshal(Result{Target: "https://example", Detector: "ok", Severity: "info", DetectedAt: at})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded Result
+	if err := json.Unmarshal(set, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !decoded.DetectedAt.Equal(at) || decoded.Target != "https://example" {
+		t.Fatalf("expected the result to round-trip, got %s", set)
+	}
+}
+
+func TestResultTagsJSONRoundTrip(t *testing.T) {
+	original := Result{Target: "https://example", Detector: "vcs", Severity: "critical", Tags: []string{"owasp-a05", "cis-1.2"}}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var decoded Result
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded.Tags, original.Tags) {
+		t.Fatalf("expected tags %v, got %v", original.Tags, decoded.Tags)
+	}
+
+	untagged, err := json.Marshal(Result{Target: "https://example"})
+	if err != nil {
+		t.Fatalf("marshal untagged: %v", err)
+	}
+	if strings.Contains(string(untagged), "tags") {
+		t.Fatalf("empty tags should be omitted: %s", untagged)
+	}
+}
+
+// dependentDetector records the prior results it saw and declares prerequisites.
+type dependentDetector struct {
+	name  string
+	deps  []string
+	order *[]string
+	seen  *[]string
+}
+
+func (d dependentDetector) Name() string { return d.name }
+
+func (d dependentDetector) Dependencies() []string { return d.deps }
+
+func (d dependentDetector) Detect(ctx context.Context, target string) (Result, error) {
+	*d.order = append(*d.order, d.name)
+	for _, prior := range PriorResults(ctx) {
+		*d.seen = append(*d.seen, d.name+"<-"+prior.Detector)
+	}
+	return Result{Target: target, Detector: d.name, Severity: "info"}, nil
+}
+
+func TestRunOrdersDependenciesFirst(t *testing.T) {
+	var order, seen []string
+	correlate := dependentDetector{name: "correlate", deps: []string{"enumerate"}, order: &order, seen: &seen}
+	enumerate := dependentDetector{name: "enumerate", order: &order, seen: &seen}
+
+	results, err := Run(context.Background(), []Detector{correlate, enumerate}, []string{"https://a.test", "https://b.test"})
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(order, []string{"enumerate", "correlate", "enumerate", "correlate"}) {
+		t.Fatalf("unexpected execution order: %v", order)
+	}
+
+	// Prior results are scoped to the current target.
+	if !reflect.DeepEqual(seen, []string{"correlate<-enumerate", "correlate<-enumerate"}) {
+		t.Fatalf("unexpected prior results: %v", seen)
+	}
+
+	if len(results) != 4 || results[0].Detector != "enumerate" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
+
+func TestOrderDetectorsRejectsCyclesAndMissingDependencies(t *testing.T) {
+	var order, seen []string
+	a := dependentDetector{name: "a", deps: []string{"b"}, order: &order, seen: &seen}
+	b := dependentDetector{name: "b", deps: []string{"a"}, order: &order, seen: &seen}
+
+	if _, err := OrderDetectors([]Detector{a, b}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+
+	if _, err := Run(context.Background(), []Detector{a}, []string{"https://a.test"}); err == nil {
+		t.Fatal("expected error for missing dependency")
+	}
+
+	plain := []Detector{fakeDetector{name: "x"}, fakeDetector{name: "y"}}
+	ordered, err := OrderDetectors(plain)
+	if err != nil || ordered[0].Name() != "x" || ordered[1].Name() != "y" {
+		t.Fatalf("independent detectors should keep their order, got %v (%v)", ordered, err)
+	}
+}
+
+func TestRemoveDisabledDropsDetectorFromBuiltSet(t *testing.T) {
+	names, removed := RemoveDisabled([]string{"version", "vcs", "php"}, []string{"VCS", "admintools"})
+	if !reflect.DeepEqual(names, []string{"version", "php"}) || !reflect.DeepEqual(removed, []string{"vcs"}) {
+		t.Fatalf("unexpected filter result: kept %v, removed %v", names, removed)
+	}
+
+	detectors, err := DefaultRegistry.BuildDetectors(names, DetectorOptions{})
+	if err != nil {
+		t.Fatalf("build detectors: %v", err)
+	}
+	for _, det := range detectors {
+		if det.Name() == "vcs" {
+			t.Fatal("expected disabled detector to be absent from the built set")
+		}
+	}
+	if len(detectors) != 2 {
+		t.Fatalf("expected 2 detectors, got %d", len(detectors))
+	}
+}
+
+// slowDetector blocks until its delay passes or ctx is done.
+type slowDetector struct {
+	name  string
+	delay time.Duration
+}
+
+func (d slowDetector) Name() string { return d.name }
+
+func (d slowDetector) Detect(ctx context.Context, target string) (Result, error) {
+	select {
+	case <-time.After(d.delay):
+		return Result{Target: target, Detector: d.name, Severity: "info", Summary: "done"}, nil
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+}
+
+func TestRunSkipsDetectorsPastTargetTimeout(t *testing.T) {
+	detectors := []Detector{
+		slowDetector{name: "slow", delay: time.Second},
+		fakeDetector{name: "after", result: Result{Severity: "info", Summary: "ran"}},
+		fakeDetector{name: "last", result: Result{Severity: "info", Summary: "ran"}},
+	}
+
+	ctx := WithTargetTimeout(context.Background(), 50*time.Millisecond)
+	start := time.Now()
+	results, err := Run(ctx, detectors, []string{"https://slow.test", "https://next.test"})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected each target to stop at its timeout, took %s", elapsed)
+	}
+	if len(results) != 6 {
+		t.Fatalf("expected a result per detector and target, got %+v", results)
+	}
+
+	for _, res := range results {
+		if res.ErrorKind != ErrorKindTargetTimeout {
+			t.Fatalf("expected %s for %s on %s, got %+v", ErrorKindTargetTimeout, res.Detector, res.Target, res)
+		}
+	}
+	if !strings.Contains(results[1].Summary, "after skipped") {
+		t.Fatalf("expected the pending detector to be skipped, got %q", results[1].Summary)
+	}
+	// Each skipped detector stays visible after deduplication on the default key.
+	if deduped := Dedup(results, DefaultDedupKey); len(deduped) != len(results) {
+		t.Fatalf("expected skip results to stay distinct, got %+v", deduped)
+	}
+}
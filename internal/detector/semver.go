@@ -0,0 +1,45 @@
+package detector
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares dotted numeric versions such as "6.4" and "6.4.2",
+// returning -1, 0, or 1. Missing components count as zero and any non-numeric
+// suffix on a component (e.g. "1-beta") is ignored.
+func CompareVersions(a, b string) int {
+	left := strings.Split(strings.TrimSpace(a), ".")
+	right := strings.Split(strings.TrimSpace(b), ".")
+
+	for i := 0; i < len(left) || i < len(right); i++ {
+		l, r := versionComponent(left, i), versionComponent(right, i)
+		switch {
+		case l < r:
+			return -1
+		case l > r:
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionComponent(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+
+	digits := parts[i]
+	for j, r := range digits {
+		if r < '0' || r > '9' {
+			digits = digits[:j]
+			break
+		}
+	}
+
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,23 @@
+package detector
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"6.4", "6.4.0", 0},
+		{"6.4.1", "6.4", 1},
+		{"6.3.9", "6.4", -1},
+		{"10.0", "9.9.9", 1},
+		{"7.4.33", "8.0", -1},
+		{"8.1.2-beta", "8.1.2", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.expected {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
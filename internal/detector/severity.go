@@ -0,0 +1,44 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// severityRanks orders known severities; unknown severities such as "info" or
+// "clean" rank lowest.
+var severityRanks = map[string]int{
+	"info":     0,
+	"clean":    0,
+	"low":      1,
+	"medium":   2,
+	"warning":  2,
+	"high":     3,
+	"critical": 4,
+}
+
+// SeverityRank returns how severe severity is, from 0 (informational) to 4 (critical).
+func SeverityRank(severity string) int {
+	return severityRanks[strings.ToLower(severity)]
+}
+
+// ValidateSeverity reports an error when severity is not a known severity name.
+func ValidateSeverity(severity string) error {
+	if _, ok := severityRanks[strings.ToLower(severity)]; !ok {
+		return fmt.Errorf("unknown severity %q (valid: info, clean, low, medium, warning, high, critical)", severity)
+	}
+	return nil
+}
+
+// FilterFindings keeps results at or above minSeverity, dropping informational and
+// clean results that need no action. Order is preserved.
+func FilterFindings(results []Result, minSeverity string) []Result {
+	min := SeverityRank(minSeverity)
+	filtered := make([]Result, 0, len(results))
+	for _, res := range results {
+		if SeverityRank(res.Severity) >= min {
+			filtered = append(filtered, res)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,25 @@
+package detector
+
+import "testing"
+
+func TestFilterFindingsDropsCleanResults(t *testing.T) {
+	results := []Result{
+		{Target: "https://one.test", Detector: "version", Severity: "info", Summary: "WordPress version 6.5.1 detected"},
+		{Target: "https://one.test", Detector: "php", Severity: "warning", Summary: "PHP 7.4 is end-of-life"},
+		{Target: "https://two.test", Detector: "vcs", Severity: "clean", Summary: "No exposed VCS files"},
+		{Target: "https://two.test", Detector: "vcs", Severity: "critical", Summary: "Exposed .git/config"},
+	}
+
+	findings := FilterFindings(results, "warning")
+	if len(findings) != 2 || findings[0].Severity != "warning" || findings[1].Severity != "critical" {
+		t.Fatalf("expected warning and critical findings in order, got %+v", findings)
+	}
+
+	if critical := FilterFindings(results, "critical"); len(critical) != 1 {
+		t.Fatalf("expected a critical threshold to keep 1 result, got %d", len(critical))
+	}
+
+	if err := ValidateSeverity("urgent"); err == nil {
+		t.Fatal("expected unknown severity to be rejected")
+	}
+}
@@ -0,0 +1,117 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// StagingIndicatorConfidence reflects that each indicator is suggestive rather than
+// conclusive: production sites can send noindex or leak debug notices too.
+const StagingIndicatorConfidence = 0.7
+
+// stagingHostLabels are hostname tokens (split on dots and dashes) that mark a
+// non-production site. Whole tokens are matched so "devon.example" is not flagged.
+var stagingHostLabels = map[string]struct{}{
+	"staging":     {},
+	"stage":       {},
+	"dev":         {},
+	"develop":     {},
+	"development": {},
+}
+
+// phpDebugNoticeRegex matches the HTML-formatted PHP notices WP_DEBUG prints into pages.
+var phpDebugNoticeRegex = regexp.MustCompile(`<b>(?:Notice|Warning|Deprecated|Fatal error)</b>:\s.*? in <b>[^<]+</b> on line <b>\d+</b>`)
+
+// StagingDetector looks for signs that a target is a staging or development site,
+// so scans can be scoped to production.
+type StagingDetector struct {
+	client       *http.Client
+	maxBodyBytes int64
+	explain      bool
+}
+
+// NewStagingDetector builds a detector with an optional custom HTTP client.
+func NewStagingDetector(client *http.Client) *StagingDetector {
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	return &StagingDetector{client: client, maxBodyBytes: DefaultMaxBodyBytes}
+}
+
+func newStagingDetectorFromOptions(opts DetectorOptions) *StagingDetector {
+	d := NewStagingDetector(opts.Client)
+	d.explain = opts.Explain
+	return d
+}
+
+// Name implements Detector.
+func (d *StagingDetector) Name() string {
+	return "staging"
+}
+
+// Detect checks the target hostname, then fetches the root document and checks it
+// for X-Robots-Tag: noindex, a non-live X-Pantheon-Environment, and WP_DEBUG notices.
+func (d *StagingDetector) Detect(ctx context.Context, target string) (Result, error) {
+	resp, err := fetch(ctx, d.client, normalizeTargetURL(target), d.maxBodyBytes)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var indicators []string
+	if label := stagingHostLabel(target); label != "" {
+		indicators = append(indicators, fmt.Sprintf("hostname contains %q", label))
+	}
+	for _, value := range resp.Header.Values("X-Robots-Tag") {
+		if strings.Contains(strings.ToLower(value), "noindex") {
+			indicators = append(indicators, fmt.Sprintf("header X-Robots-Tag: %s", value))
+			break
+		}
+	}
+	if env := resp.Header.Get("X-Pantheon-Environment"); env != "" && !strings.EqualFold(env, "live") {
+		indicators = append(indicators, fmt.Sprintf("header X-Pantheon-Environment: %s", env))
+	}
+	if phpDebugNoticeRegex.Match(resp.Body) {
+		indicators = append(indicators, "PHP debug notice in page (WP_DEBUG enabled)")
+	}
+
+	if len(indicators) == 0 {
+		return withExplanation(Result{
+			Target:   target,
+			Detector: d.Name(),
+			Severity: "info",
+			Summary:  "No staging indicators found",
+			Metadata: map[string]interface{}{"staging": false, "indicators": []string{}},
+		}, d.explain, "hostname, robots and Pantheon headers, and page body show no non-production signs"), nil
+	}
+
+	return withExplanation(Result{
+		Target:     target,
+		Detector:   d.Name(),
+		Severity:   "info",
+		Summary:    fmt.Sprintf("Site looks non-production: %s", strings.Join(indicators, "; ")),
+		Metadata:   map[string]interface{}{"staging": true, "indicators": indicators},
+		Confidence: StagingIndicatorConfidence,
+	}, d.explain, "matched %s", strings.Join(indicators, "; ")), nil
+}
+
+// stagingHostLabel returns the first hostname token of target naming a
+// non-production environment, or "" when there is none.
+func stagingHostLabel(target string) string {
+	u, err := url.Parse(normalizeTargetURL(target))
+	if err != nil {
+		return ""
+	}
+	tokens := strings.FieldsFunc(strings.ToLower(u.Hostname()), func(r rune) bool {
+		return r == '.' || r == '-'
+	})
+	for _, token := range tokens {
+		if _, ok := stagingHostLabels[token]; ok {
+			return token
+		}
+	}
+	return ""
+}
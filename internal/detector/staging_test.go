@@ -0,0 +1,83 @@
+package detector
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestStagingDetectorFlagsNoindexHeader(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer ts.Close()
+
+	res, err := NewStagingDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	want := []string{"header X-Robots-Tag: noindex, nofollow"}
+	if res.Severity != "info" || res.Metadata["staging"] != true || !reflect.DeepEqual(res.Metadata["indicators"], want) {
+		t.Fatalf("expected a noindex staging indicator, got %+v", res)
+	}
+}
+
+func TestStagingDetectorFlagsStagingHostname(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer ts.Close()
+
+	// Resolve every hostname to the test server so the target can carry a real name.
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, ts.Listener.Addr().String())
+		},
+	}}
+
+	res, err := NewStagingDetector(client).Detect(context.Background(), "http://staging.example.test")
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	want := []string{`hostname contains "staging"`}
+	if res.Metadata["staging"] != true || !reflect.DeepEqual(res.Metadata["indicators"], want) {
+		t.Fatalf("expected a staging hostname indicator, got %+v", res)
+	}
+}
+
+func TestStagingDetectorIgnoresProductionSite(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Pantheon-Environment", "live")
+		_, _ = w.Write([]byte("<html><body>Welcome</body></html>"))
+	}))
+	defer ts.Close()
+
+	res, err := NewStagingDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Metadata["staging"] != false {
+		t.Fatalf("expected no staging indicators, got %+v", res)
+	}
+}
+
+func TestStagingHostLabel(t *testing.T) {
+	tests := map[string]string{
+		"https://staging.example.com":   "staging",
+		"https://example-dev.host.test": "dev",
+		"https://devon.example.com":     "",
+		"https://www.example.com":       "",
+	}
+	for target, want := range tests {
+		if got := stagingHostLabel(target); got != want {
+			t.Fatalf("stagingHostLabel(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
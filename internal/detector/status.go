@@ -0,0 +1,109 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// StatusRange is an inclusive range of HTTP status codes.
+type StatusRange struct {
+	Min int
+	Max int
+}
+
+// Contains reports whether code falls within the range.
+func (r StatusRange) Contains(code int) bool {
+	return code >= r.Min && code <= r.Max
+}
+
+func (r StatusRange) String() string {
+	if r.Min == r.Max {
+		return strconv.Itoa(r.Min)
+	}
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// DefaultStatusWarnRanges flags server errors when no ranges are configured.
+var DefaultStatusWarnRanges = []StatusRange{{Min: 500, Max: 599}}
+
+// ParseStatusRanges parses specs such as "500-599" or "404" into status ranges.
+func ParseStatusRanges(specs []string) ([]StatusRange, error) {
+	ranges := make([]StatusRange, 0, len(specs))
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		low, high, isRange := strings.Cut(spec, "-")
+		if !isRange {
+			high = low
+		}
+		min, errMin := strconv.Atoi(strings.TrimSpace(low))
+		max, errMax := strconv.Atoi(strings.TrimSpace(high))
+		if errMin != nil || errMax != nil || min < 100 || max > 599 || min > max {
+			return nil, fmt.Errorf("invalid status range %q: expected a code or range between 100 and 599 (e.g. 500-599)", spec)
+		}
+		ranges = append(ranges, StatusRange{Min: min, Max: max})
+	}
+	return ranges, nil
+}
+
+// StatusDetector records the homepage HTTP status and flags statuses in the
+// configured ranges, for teams that treat e.g. any 5xx as a finding.
+type StatusDetector struct {
+	client       *http.Client
+	maxBodyBytes int64
+	explain      bool
+	warn         []StatusRange
+}
+
+// NewStatusDetector builds a detector with an optional custom HTTP client that
+// warns on statuses within warn (DefaultStatusWarnRanges when empty).
+func NewStatusDetector(client *http.Client, warn []StatusRange) *StatusDetector {
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	if len(warn) == 0 {
+		warn = DefaultStatusWarnRanges
+	}
+	return &StatusDetector{client: client, maxBodyBytes: DefaultMaxBodyBytes, warn: warn}
+}
+
+func newStatusDetectorFromOptions(opts DetectorOptions) *StatusDetector {
+	d := NewStatusDetector(opts.Client, opts.StatusWarn)
+	d.explain = opts.Explain
+	return d
+}
+
+// Name implements Detector.
+func (d *StatusDetector) Name() string {
+	return "status"
+}
+
+// Detect fetches the target root document and reports its status code.
+func (d *StatusDetector) Detect(ctx context.Context, target string) (Result, error) {
+	resp, err := fetch(ctx, d.client, normalizeTargetURL(target), d.maxBodyBytes)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{
+		Target:   target,
+		Detector: d.Name(),
+		Severity: "info",
+		Summary:  fmt.Sprintf("Homepage returned HTTP %d", resp.StatusCode),
+		Metadata: map[string]interface{}{"status": resp.StatusCode},
+	}
+	for _, r := range d.warn {
+		if r.Contains(resp.StatusCode) {
+			res.Severity = "warning"
+			res.Metadata["range"] = r.String()
+			return withExplanation(res, d.explain, "HTTP %d falls within the warning range %s", resp.StatusCode, r), nil
+		}
+	}
+	return withExplanation(res, d.explain, "HTTP %d is outside the warning ranges", resp.StatusCode), nil
+}
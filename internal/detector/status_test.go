@@ -0,0 +1,56 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusDetector(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		wantSeverity string
+	}{
+		{name: "server error", status: http.StatusInternalServerError, wantSeverity: "warning"},
+		{name: "ok", status: http.StatusOK, wantSeverity: "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer ts.Close()
+
+			res, err := NewStatusDetector(ts.Client(), []StatusRange{{Min: 500, Max: 599}}).Detect(context.Background(), ts.URL)
+			if err != nil {
+				t.Fatalf("detect failed: %v", err)
+			}
+
+			if res.Severity != tt.wantSeverity {
+				t.Fatalf("expected %s severity, got %+v", tt.wantSeverity, res)
+			}
+			if res.Metadata["status"] != tt.status {
+				t.Fatalf("expected status %d in metadata, got %v", tt.status, res.Metadata["status"])
+			}
+		})
+	}
+}
+
+func TestParseStatusRanges(t *testing.T) {
+	ranges, err := ParseStatusRanges([]string{"500-599", " 404 "})
+	if err != nil {
+		t.Fatalf("parse ranges: %v", err)
+	}
+	if len(ranges) != 2 || ranges[0] != (StatusRange{Min: 500, Max: 599}) || ranges[1] != (StatusRange{Min: 404, Max: 404}) {
+		t.Fatalf("unexpected ranges: %v", ranges)
+	}
+
+	for _, spec := range []string{"5xx", "599-500", "42", "500-700"} {
+		if _, err := ParseStatusRanges([]string{spec}); err == nil {
+			t.Fatalf("expected %q to be rejected", spec)
+		}
+	}
+}
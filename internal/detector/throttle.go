@@ -0,0 +1,125 @@
+package detector
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSlowResponseThreshold is the response time above which a host is
+	// considered to be struggling and further requests to it are spaced out.
+	DefaultSlowResponseThreshold = 2 * time.Second
+	// DefaultMaxThrottleDelay caps the pause inserted before requests to a slow host.
+	// The pause runs inside the request's timeout, so it stays well below
+	// DefaultHTTPTimeout.
+	DefaultMaxThrottleDelay = 3 * time.Second
+)
+
+// ThrottlingTransport is an http.RoundTripper that adapts a per-host delay to how
+// quickly each host answers. A response slower than Threshold adds its latency to
+// the host's delay (capped at MaxDelay); a fast response halves it. Each request to
+// a host reserves the next slot, one delay after the previous one, so concurrent
+// requests to a slow host are queued rather than released together.
+type ThrottlingTransport struct {
+	Base      http.RoundTripper
+	Threshold time.Duration
+	MaxDelay  time.Duration
+
+	mu    sync.Mutex
+	now   func() time.Time
+	hosts map[string]*hostThrottle
+}
+
+type hostThrottle struct {
+	delay time.Duration
+	// ready is the earliest start of the next request to the host.
+	ready time.Time
+}
+
+// NewThrottlingTransport wraps base (http.DefaultTransport when nil) with adaptive
+// per-host throttling. Non-positive durations fall back to the defaults.
+func NewThrottlingTransport(base http.RoundTripper, threshold, maxDelay time.Duration) *ThrottlingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if threshold <= 0 {
+		threshold = DefaultSlowResponseThreshold
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxThrottleDelay
+	}
+	return &ThrottlingTransport{Base: base, Threshold: threshold, MaxDelay: maxDelay, now: time.Now, hosts: map[string]*hostThrottle{}}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ThrottlingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	if wait := t.wait(host); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	start := t.now()
+	resp, err := t.Base.RoundTrip(req)
+	t.observe(host, t.now().Sub(start))
+	return resp, err
+}
+
+// Delay reports the current delay applied before requests to host.
+func (t *ThrottlingTransport) Delay(host string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if state, ok := t.hosts[host]; ok {
+		return state.delay
+	}
+	return 0
+}
+
+// wait reserves the next request slot for host and reports how long to sleep until it.
+func (t *ThrottlingTransport) wait(host string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := t.state(host)
+	now := t.now()
+	slot := state.ready
+	if slot.Before(now) {
+		slot = now
+	}
+	state.ready = slot.Add(state.delay)
+	return slot.Sub(now)
+}
+
+func (t *ThrottlingTransport) observe(host string, latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state := t.state(host)
+	if latency > t.Threshold {
+		state.delay += latency
+		if state.delay > t.MaxDelay {
+			state.delay = t.MaxDelay
+		}
+	} else {
+		state.delay /= 2
+	}
+	// Slots already reserved by queued requests are kept.
+	if ready := t.now().Add(state.delay); ready.After(state.ready) {
+		state.ready = ready
+	}
+}
+
+// state returns host's throttle state, creating it on first use. t.mu must be held.
+func (t *ThrottlingTransport) state(host string) *hostThrottle {
+	state, ok := t.hosts[host]
+	if !ok {
+		state = &hostThrottle{}
+		t.hosts[host] = state
+	}
+	return state
+}
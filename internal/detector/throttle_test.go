@@ -0,0 +1,125 @@
+package detector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestThrottlingTransportSpacesOutSlowHost(t *testing.T) {
+	newServer := func(latency time.Duration) (*httptest.Server, *[]time.Time, *sync.Mutex) {
+		var mu sync.Mutex
+		var arrivals []time.Time
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			arrivals = append(arrivals, time.Now())
+			mu.Unlock()
+			time.Sleep(latency)
+			_, _ = w.Write([]byte("ok"))
+		}))
+		return server, &arrivals, &mu
+	}
+
+	slow, slowArrivals, slowMu := newServer(60 * time.Millisecond)
+	defer slow.Close()
+	fast, fastArrivals, fastMu := newServer(0)
+	defer fast.Close()
+
+	transport := NewThrottlingTransport(nil, 30*time.Millisecond, time.Second)
+	client := &http.Client{Transport: transport}
+
+	// gaps measures the idle time between each response and the next request's arrival.
+	gaps := func(url string, arrivals *[]time.Time, mu *sync.Mutex) []time.Duration {
+		var finished []time.Time
+		for i := 0; i < 3; i++ {
+			resp, err := client.Get(url)
+			if err != nil {
+				t.Fatalf("get %s: %v", url, err)
+			}
+			resp.Body.Close()
+			finished = append(finished, time.Now())
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		var out []time.Duration
+		for i := 1; i < len(*arrivals); i++ {
+			out = append(out, (*arrivals)[i].Sub(finished[i-1]))
+		}
+		return out
+	}
+
+	slowGaps := gaps(slow.URL, slowArrivals, slowMu)
+	fastGaps := gaps(fast.URL, fastArrivals, fastMu)
+
+	for i, gap := range slowGaps {
+		if gap < 50*time.Millisecond {
+			t.Fatalf("expected slow host request %d to be delayed, gap was %s", i+1, gap)
+		}
+		if gap <= fastGaps[i] {
+			t.Fatalf("expected slow host gap %s to exceed fast host gap %s", gap, fastGaps[i])
+		}
+	}
+	if delay := transport.Delay(slow.Listener.Addr().String()); delay == 0 {
+		t.Fatal("expected a delay to be recorded for the slow host")
+	}
+	if delay := transport.Delay(fast.Listener.Addr().String()); delay != 0 {
+		t.Fatalf("expected no delay for the fast host, got %s", delay)
+	}
+}
+
+func TestThrottlingTransportQueuesConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		time.Sleep(60 * time.Millisecond)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer slow.Close()
+
+	transport := NewThrottlingTransport(nil, 30*time.Millisecond, time.Second)
+	client := &http.Client{Transport: transport}
+
+	get := func() {
+		resp, err := client.Get(slow.URL)
+		if err != nil {
+			t.Errorf("get: %v", err)
+			return
+		}
+		resp.Body.Close()
+	}
+
+	// The first response marks the host as slow.
+	get()
+	delay := transport.Delay(slow.Listener.Addr().String())
+	if delay == 0 {
+		t.Fatal("expected a delay to be recorded for the slow host")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			get()
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(arrivals) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(arrivals))
+	}
+	gap := arrivals[2].Sub(arrivals[1])
+	if gap < 0 {
+		gap = -gap
+	}
+	if gap < delay-10*time.Millisecond {
+		t.Fatalf("expected concurrent requests to be spaced by about %s, arrived %s apart", delay, gap)
+	}
+}
@@ -0,0 +1,53 @@
+package detector
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracerKey struct{}
+
+// WithTracer makes Run wrap each target's detection in a span started from tracer.
+// Without it Run records no spans.
+func WithTracer(ctx context.Context, tracer trace.Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// startTargetSpan starts the span covering every detector run for target, or returns
+// ctx and a nil span when no tracer is configured.
+func startTargetSpan(ctx context.Context, target string) (context.Context, trace.Span) {
+	tracer, ok := ctx.Value(tracerKey{}).(trace.Tracer)
+	if !ok || tracer == nil {
+		return ctx, nil
+	}
+	return tracer.Start(ctx, "detect", trace.WithAttributes(attribute.String("wphunter.target", target)))
+}
+
+// endTargetSpan records each result as a span event and ends span. Targets where any
+// detector failed get an error status.
+func endTargetSpan(span trace.Span, results []Result) {
+	if span == nil {
+		return
+	}
+
+	failed := 0
+	for _, res := range results {
+		attrs := []attribute.KeyValue{
+			attribute.String("wphunter.detector", res.Detector),
+			attribute.String("wphunter.severity", res.Severity),
+			attribute.String("wphunter.summary", res.Summary),
+		}
+		if res.Err != nil {
+			failed++
+			attrs = append(attrs, attribute.String("wphunter.error_kind", res.ErrorKind))
+		}
+		span.AddEvent("detection", trace.WithAttributes(attrs...))
+	}
+	if failed > 0 {
+		span.SetStatus(codes.Error, "detector errors")
+	}
+	span.End()
+}
@@ -0,0 +1,57 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRunCreatesSpanPerTarget(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	dets := []Detector{
+		fakeDetector{name: "one", result: Result{Detector: "one", Severity: "info"}},
+		fakeDetector{name: "two", err: errors.New("boom")},
+	}
+	targets := []string{"https://one.test", "https://two.test"}
+
+	ctx := WithTracer(context.Background(), provider.Tracer("test"))
+	if _, err := Run(ctx, dets, targets); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != len(targets) {
+		t.Fatalf("expected one span per target, got %d", len(spans))
+	}
+	for i, span := range spans {
+		var target string
+		for _, attr := range span.Attributes {
+			if attr.Key == "wphunter.target" {
+				target = attr.Value.AsString()
+			}
+		}
+		if target != targets[i] {
+			t.Fatalf("span %d target = %q, want %q", i, target, targets[i])
+		}
+		if len(span.Events) != len(dets) {
+			t.Fatalf("expected a span event per detector, got %d", len(span.Events))
+		}
+		if span.Status.Code != codes.Error {
+			t.Fatalf("expected error status for failed detector, got %v", span.Status)
+		}
+	}
+}
+
+func TestRunWithoutTracerRecordsNoSpans(t *testing.T) {
+	ctx, span := startTargetSpan(context.Background(), "https://one.test")
+	if span != nil || ctx == nil {
+		t.Fatalf("expected no span without a tracer, got %v", span)
+	}
+}
@@ -0,0 +1,121 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// VCSExposureConfidence is reported when an exposed file matches its content marker
+// and differs from the target's soft-404 page.
+const VCSExposureConfidence = 0.95
+
+var (
+	svnEntriesRegex = regexp.MustCompile(`^\d+\s*\n`)
+	dotEnvRegex     = regexp.MustCompile(`(?m)^[A-Z][A-Z0-9_]*=`)
+)
+
+// vcsProbe describes a sensitive file and how to recognize its real contents.
+type vcsProbe struct {
+	Path   string
+	Marker func(body []byte) bool
+}
+
+var vcsProbes = []vcsProbe{
+	{
+		Path:   "/.git/config",
+		Marker: func(body []byte) bool { return bytes.Contains(body, []byte("[core]")) },
+	},
+	{
+		Path:   "/.svn/entries",
+		Marker: func(body []byte) bool { return svnEntriesRegex.Match(body) },
+	},
+	{
+		Path: "/.env",
+		Marker: func(body []byte) bool {
+			return dotEnvRegex.Match(body) && !bytes.Contains(bytes.ToLower(body), []byte("<html"))
+		},
+	},
+}
+
+// VCSExposureDetector probes for version-control metadata and environment files left in the web root.
+type VCSExposureDetector struct {
+	client       *http.Client
+	maxBodyBytes int64
+	confidence   float64
+	explain      bool
+}
+
+// NewVCSExposureDetector builds a detector with an optional custom HTTP client.
+func NewVCSExposureDetector(client *http.Client) *VCSExposureDetector {
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+	return &VCSExposureDetector{client: client, maxBodyBytes: DefaultMaxBodyBytes, confidence: VCSExposureConfidence}
+}
+
+func newVCSExposureDetectorFromOptions(opts DetectorOptions) *VCSExposureDetector {
+	d := NewVCSExposureDetector(opts.Client)
+	d.confidence = opts.ConfidenceFor(ConfidenceVCSExposure, VCSExposureConfidence)
+	d.explain = opts.Explain
+	return d
+}
+
+// Name implements Detector.
+func (d *VCSExposureDetector) Name() string {
+	return "vcs"
+}
+
+// Detect requests each well-known path and flags those returning recognizable content.
+func (d *VCSExposureDetector) Detect(ctx context.Context, target string) (Result, error) {
+	baseline, err := newSoft404(ctx, d.client, target, d.maxBodyBytes)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var exposed []string
+	for _, probe := range vcsProbes {
+		resp, err := fetch(ctx, d.client, joinTargetPath(target, probe.Path), d.maxBodyBytes)
+		if err != nil {
+			return Result{}, err
+		}
+
+		if resp.StatusCode != http.StatusOK || baseline.matches(probe.Path, resp) {
+			continue
+		}
+
+		if probe.Marker(resp.Body) {
+			exposed = append(exposed, probe.Path)
+		}
+	}
+
+	if len(exposed) == 0 {
+		return withExplanation(Result{
+			Target:   target,
+			Detector: d.Name(),
+			Severity: "info",
+			Summary:  "No exposed VCS or environment files found",
+		}, d.explain, "requested %s; none returned 200 with the file's expected content", vcsProbePaths()), nil
+	}
+
+	return withExplanation(Result{
+		Target:     target,
+		Detector:   d.Name(),
+		Severity:   "critical",
+		Summary:    fmt.Sprintf("Exposed sensitive files: %s", strings.Join(exposed, ", ")),
+		Metadata:   map[string]interface{}{"paths": exposed},
+		Confidence: d.confidence,
+		Tags:       []string{TagOWASPMisconfiguration},
+	}, d.explain, "%s returned 200 with content matching the file format and differing from the soft-404 page", strings.Join(exposed, ", ")), nil
+}
+
+func vcsProbePaths() string {
+	paths := make([]string, 0, len(vcsProbes))
+	for _, probe := range vcsProbes {
+		paths = append(paths, probe.Path)
+	}
+	return strings.Join(paths, ", ")
+}
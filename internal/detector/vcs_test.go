@@ -0,0 +1,53 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVCSExposureDetectorFlagsGitConfig(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/.git/config" {
+			_, _ = w.Write([]byte("[core]\n\trepositoryformatversion = 0\n\tbare = false\n"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	detector := NewVCSExposureDetector(ts.Client())
+	res, err := detector.Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Severity != "critical" {
+		t.Fatalf("expected critical severity, got %s", res.Severity)
+	}
+
+	paths, ok := res.Metadata["paths"].([]string)
+	if !ok || len(paths) != 1 || paths[0] != "/.git/config" {
+		t.Fatalf("expected /.git/config in metadata, got %v", res.Metadata)
+	}
+}
+
+func TestVCSExposureDetectorIgnoresSoft404(t *testing.T) {
+	// A catch-all page that happens to contain every marker must not be reported.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("12\n[core]\nAPP_ENV=production\n"))
+	}))
+	defer ts.Close()
+
+	detector := NewVCSExposureDetector(ts.Client())
+	res, err := detector.Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Severity != "info" {
+		t.Fatalf("expected soft-404 responses to be ignored, got %+v", res)
+	}
+}
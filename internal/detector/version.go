@@ -1,40 +1,137 @@
 package detector
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"io"
+	"math"
+	"mime"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
-	"time"
 )
 
-var versionRegex = regexp.MustCompile(`WordPress\s+([0-9]+\.[0-9]+(\.[0-9]+)?)`)
+// versionRegex matches the "WordPress X.Y[.Z]" generator content. The word boundaries
+// keep it from matching inside longer words ("MyWordPress 6.5") or taking a prefix of
+// a malformed version ("6.5beta"), and a minor version is required, so neither
+// "WordPress 6" nor "WordPress.org" content yields a version.
+var versionRegex = regexp.MustCompile(`\bWordPress\s+([0-9]+\.[0-9]+(?:\.[0-9]+)?)\b`)
+
+// ParseVersionPattern compiles a replacement for the generator version regex. The
+// first capture group must hold the version.
+func ParseVersionPattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if re.NumSubexp() < 1 {
+		return nil, fmt.Errorf("version pattern %q has no capture group for the version", pattern)
+	}
+	return re, nil
+}
+
+// readmeVersionRegex matches the "Version X.Y" line of a core /readme.html.
+var readmeVersionRegex = regexp.MustCompile(`(?i)<br\s*/?>\s*version\s+([0-9]+\.[0-9]+(?:\.[0-9]+)?)`)
+
+// assetVersionRegex matches core assets whose ?ver= query carries the WordPress
+// version. Other wp-includes assets (such as jQuery) carry their own versions.
+var assetVersionRegex = regexp.MustCompile(`wp-includes/(?:css/dist/block-library/style(?:\.min)?\.css|js/wp-emoji-release\.min\.js)\?ver=([0-9]+\.[0-9]+(?:\.[0-9]+)?)`)
 
 // GeneratorTagConfidence represents the confidence level for WordPress version detection
 // via generator meta tags. Set to 0.85 because while generator tags are reliable indicators
 // of WordPress presence, they can be modified or removed, making them not 100% definitive.
 const GeneratorTagConfidence = 0.85
 
+// ReadmeConfidence and AssetVersionConfidence weigh the weaker version sources: the
+// readme is often stale after upgrades, and asset versions can be rewritten by caches.
+const (
+	ReadmeConfidence       = 0.6
+	AssetVersionConfidence = 0.5
+)
+
 // DefaultMaxBodyBytes is the default maximum number of bytes to read from HTTP response bodies
 // when detecting WordPress versions. Set to 1MB to limit memory usage while capturing
 // enough content to find generator meta tags.
 const DefaultMaxBodyBytes = 1024 * 1024
 
+// DefaultOutdatedSeverity is the severity for versions below the configured minimum.
+const DefaultOutdatedSeverity = "warning"
+
+// DefaultHomePath is the entry path the version detector fetches when none is configured.
+const DefaultHomePath = "/"
+
 // VersionDetector inspects the target homepage for WordPress generator metadata.
 type VersionDetector struct {
 	client       *http.Client
 	maxBodyBytes int64
+	confidence   float64
+	// readmeConfidence and assetConfidence weigh the secondary version sources.
+	readmeConfidence float64
+	assetConfidence  float64
+	// homePath is appended to the normalized target to locate the homepage.
+	homePath string
+	// versionPattern matches the generator version in the homepage.
+	versionPattern *regexp.Regexp
+	// minVersion, when set, marks older versions as outdated at outdatedSeverity.
+	minVersion       string
+	outdatedSeverity string
+	// headFirst checks the homepage content type with a HEAD before the GET.
+	headFirst bool
+	explain   bool
+}
+
+// versionEvidence is one source's claim about the WordPress version.
+type versionEvidence struct {
+	source  string
+	version string
+	match   string
+	weight  float64
+}
+
+// versionEvidenceDescriptions phrase each source for --explain.
+var versionEvidenceDescriptions = map[string]string{
+	SourceGeneratorMeta: "generator meta tag `%s` in the homepage",
+	SourceAssetVersion:  "core asset `%s` in the homepage",
+	SourceReadme:        "readme line `%s` in /readme.html",
 }
 
 // NewVersionDetector builds a detector with an optional custom HTTP client.
 func NewVersionDetector(client *http.Client) *VersionDetector {
 	if client == nil {
-		client = &http.Client{Timeout: 10 * time.Second}
+		client = defaultHTTPClient()
+	}
+	return &VersionDetector{
+		client:           client,
+		maxBodyBytes:     DefaultMaxBodyBytes,
+		confidence:       GeneratorTagConfidence,
+		readmeConfidence: ReadmeConfidence,
+		assetConfidence:  AssetVersionConfidence,
+		homePath:         DefaultHomePath,
+		versionPattern:   versionRegex,
+		outdatedSeverity: DefaultOutdatedSeverity,
+	}
+}
+
+func newVersionDetectorFromOptions(opts DetectorOptions) *VersionDetector {
+	d := NewVersionDetector(opts.Client)
+	d.confidence = opts.ConfidenceFor(ConfidenceVersionGenerator, GeneratorTagConfidence)
+	d.readmeConfidence = opts.ConfidenceFor(ConfidenceVersionReadme, ReadmeConfidence)
+	d.assetConfidence = opts.ConfidenceFor(ConfidenceVersionAsset, AssetVersionConfidence)
+	if opts.HomePath != "" {
+		d.homePath = opts.HomePath
+	}
+	if opts.VersionPattern != nil {
+		d.versionPattern = opts.VersionPattern
+	}
+	d.minVersion = opts.MinVersion
+	if opts.OutdatedSeverity != "" {
+		d.outdatedSeverity = opts.OutdatedSeverity
 	}
-	return &VersionDetector{client: client, maxBodyBytes: DefaultMaxBodyBytes}
+	d.headFirst = opts.HeadFirst
+	d.explain = opts.Explain
+	return d
 }
 
 // Name implements Detector.
@@ -42,9 +139,31 @@ func (d *VersionDetector) Name() string {
 	return "version"
 }
 
-// Detect fetches the target root document and scans for a generator meta tag.
+// Detect fetches the target homepage (at homePath) and /readme.html and collects version evidence
+// from the generator meta tag, the readme, and core asset ?ver= queries. The version
+// with the most weight wins, and its confidence grows with every agreeing source.
 func (d *VersionDetector) Detect(ctx context.Context, target string) (Result, error) {
 	url := normalizeTargetURL(target)
+	if d.homePath != DefaultHomePath {
+		url = joinTargetPath(target, d.homePath)
+	}
+	if d.headFirst {
+		contentType, err := d.headContentType(ctx, url)
+		if err != nil {
+			return Result{}, err
+		}
+		if contentType != "" && !isHTMLContentType(contentType) {
+			res := Result{
+				Target:   target,
+				Detector: d.Name(),
+				Severity: "info",
+				Summary:  fmt.Sprintf("Skipped version detection: homepage serves %s, not HTML", contentType),
+				Metadata: map[string]interface{}{"contentType": contentType, "skipped": true},
+			}
+			return withExplanation(res, d.explain, "HEAD %s returned Content-Type %s", url, contentType), nil
+		}
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return Result{}, err
@@ -57,7 +176,7 @@ func (d *VersionDetector) Detect(ctx context.Context, target string) (Result, er
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return Result{}, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return Result{}, &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	reader := io.LimitReader(resp.Body, d.maxBodyBytes)
@@ -66,20 +185,120 @@ func (d *VersionDetector) Detect(ctx context.Context, target string) (Result, er
 		return Result{}, err
 	}
 
-	matches := versionRegex.FindSubmatch(bodyBytes)
-	if len(matches) < 2 {
-		return Result{}, errors.New("version not discovered in generator tag")
+	var evidence []versionEvidence
+	if matches := d.versionPattern.FindSubmatch(bodyBytes); len(matches) >= 2 && len(matches[1]) > 0 {
+		evidence = append(evidence, versionEvidence{source: SourceGeneratorMeta, version: string(matches[1]), match: string(matches[0]), weight: d.confidence})
+	}
+	if matches := assetVersionRegex.FindSubmatch(bodyBytes); len(matches) >= 2 {
+		evidence = append(evidence, versionEvidence{source: SourceAssetVersion, version: string(matches[1]), match: string(matches[0]), weight: d.assetConfidence})
+	}
+	readme, err := fetch(ctx, d.client, joinTargetPath(target, "/readme.html"), d.maxBodyBytes)
+	if err != nil && ctx.Err() != nil {
+		return Result{}, ctx.Err()
+	}
+	if err == nil && readme.StatusCode == http.StatusOK && bytes.Contains(readme.Body, []byte("WordPress")) {
+		if matches := readmeVersionRegex.FindSubmatch(readme.Body); len(matches) >= 2 {
+			evidence = append(evidence, versionEvidence{source: SourceReadme, version: string(matches[1]), match: strings.TrimSpace(string(matches[0])), weight: d.readmeConfidence})
+		}
 	}
 
-	version := string(matches[1])
-	return Result{
+	if len(evidence) == 0 {
+		return Result{}, &ParseError{Msg: "version not discovered in generator tag, readme, or asset versions"}
+	}
+
+	version, agreeing := pickVersion(evidence)
+	sources := make([]string, 0, len(agreeing))
+	matched := make([]string, 0, len(agreeing))
+	for _, ev := range agreeing {
+		sources = append(sources, ev.source)
+		matched = append(matched, fmt.Sprintf(versionEvidenceDescriptions[ev.source], ev.match))
+	}
+
+	res := Result{
 		Target:     target,
 		Detector:   d.Name(),
 		Severity:   "info",
 		Summary:    fmt.Sprintf("WordPress version %s detected", version),
-		Metadata:   map[string]interface{}{"version": version, "source": "meta-generator"},
-		Confidence: GeneratorTagConfidence,
-	}, nil
+		Metadata:   map[string]interface{}{MetaVersion: version, MetaSource: agreeing[0].source, MetaSources: sources},
+		Confidence: combineConfidence(agreeing),
+	}
+	if d.headFirst {
+		res.Metadata["contentType"] = resp.Header.Get("Content-Type")
+	}
+	if d.minVersion != "" && CompareVersions(version, d.minVersion) < 0 {
+		res.Severity = d.outdatedSeverity
+		res.Summary = fmt.Sprintf("Outdated WordPress version %s detected (minimum %s)", version, d.minVersion)
+		res.Metadata["minVersion"] = d.minVersion
+		res.Tags = []string{TagOWASPOutdated}
+	}
+	return withExplanation(res, d.explain, "matched %s at %s", strings.Join(matched, ", "), url), nil
+}
+
+// headContentType sends a HEAD to url and returns its Content-Type. Servers that
+// reject HEAD or fail to answer it yield "", so the caller falls back to the GET;
+// only context cancellation and error statuses are returned as errors.
+func (d *VersionDetector) headContentType(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", nil
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented:
+		return "", nil
+	case resp.StatusCode >= 400:
+		return "", &StatusError{StatusCode: resp.StatusCode}
+	}
+	return resp.Header.Get("Content-Type"), nil
+}
+
+// isHTMLContentType reports whether contentType names an HTML document.
+func isHTMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// pickVersion returns the version backed by the most evidence weight and the
+// evidence agreeing with it, strongest first. Ties go to the earlier evidence.
+func pickVersion(evidence []versionEvidence) (string, []versionEvidence) {
+	totals := map[string]float64{}
+	best := ""
+	for _, ev := range evidence {
+		totals[ev.version] += ev.weight
+		if best == "" || totals[ev.version] > totals[best] {
+			best = ev.version
+		}
+	}
+
+	var agreeing []versionEvidence
+	for _, ev := range evidence {
+		if ev.version == best {
+			agreeing = append(agreeing, ev)
+		}
+	}
+	sort.SliceStable(agreeing, func(i, j int) bool { return agreeing[i].weight > agreeing[j].weight })
+	return best, agreeing
+}
+
+// combineConfidence treats each agreeing source as independent evidence: the
+// result is the chance that at least one of them is right, rounded to two decimals.
+func combineConfidence(agreeing []versionEvidence) float64 {
+	miss := 1.0
+	for _, ev := range agreeing {
+		miss *= 1 - ev.weight
+	}
+	return math.Round((1-miss)*100) / 100
 }
 
 func normalizeTargetURL(target string) string {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -263,3 +264,299 @@ func TestNormalizeTargetURL(t *testing.T) {
 		})
 	}
 }
+
+func TestVersionDetectorUsesCalibratedConfidence(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<meta name="generator" content="WordPress 6.5.1" />`))
+	}))
+	defer ts.Close()
+
+	dets, err := DefaultRegistry.BuildDetectors([]string{"version"}, DetectorOptions{
+		Confidence: map[string]float64{ConfidenceVersionGenerator: 0.9},
+	})
+	if err != nil {
+		t.Fatalf("build detectors: %v", err)
+	}
+
+	detector := dets[0].(*VersionDetector)
+	detector.client = ts.Client()
+
+	res, err := detector.Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Confidence != 0.9 {
+		t.Fatalf("expected calibrated confidence 0.9, got %v", res.Confidence)
+	}
+}
+
+func TestVersionDetectorDefaultConfidence(t *testing.T) {
+	detector := newVersionDetectorFromOptions(DetectorOptions{})
+	if detector.confidence != GeneratorTagConfidence {
+		t.Fatalf("expected default confidence %v, got %v", GeneratorTagConfidence, detector.confidence)
+	}
+}
+
+func TestVersionDetectorExplainsGeneratorMatch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<meta name="generator" content="WordPress 6.5.1" />`))
+	}))
+	defer ts.Close()
+
+	detector := newVersionDetectorFromOptions(DetectorOptions{Client: ts.Client(), Explain: true})
+	res, err := detector.Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	explanation, _ := res.Metadata["explanation"].(string)
+	if !strings.Contains(explanation, "generator meta tag `WordPress 6.5.1`") {
+		t.Fatalf("expected explanation to reference the generator tag, got %q", explanation)
+	}
+
+	plain, err := newVersionDetectorFromOptions(DetectorOptions{Client: ts.Client()}).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+	if _, ok := plain.Metadata["explanation"]; ok {
+		t.Fatalf("expected no explanation without Explain, got %+v", plain.Metadata)
+	}
+}
+
+func TestVersionDetectorAgreeingSourcesRaiseConfidence(t *testing.T) {
+	newServer := func(withReadme bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/":
+				_, _ = w.Write([]byte(`<meta name="generator" content="WordPress 6.5.1" />`))
+			case "/readme.html":
+				if !withReadme {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(`<h1 id="logo">WordPress<br /> Version 6.5.1</h1>`))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+	}
+
+	single := newServer(false)
+	defer single.Close()
+	agreeing := newServer(true)
+	defer agreeing.Close()
+
+	singleRes, err := NewVersionDetector(single.Client()).Detect(context.Background(), single.URL)
+	if err != nil {
+		t.Fatalf("detect single source: %v", err)
+	}
+	agreeingRes, err := NewVersionDetector(agreeing.Client()).Detect(context.Background(), agreeing.URL)
+	if err != nil {
+		t.Fatalf("detect agreeing sources: %v", err)
+	}
+
+	if agreeingRes.Confidence <= singleRes.Confidence {
+		t.Fatalf("expected agreeing sources to raise confidence above %v, got %v", singleRes.Confidence, agreeingRes.Confidence)
+	}
+	sources, _ := agreeingRes.Metadata[MetaSources].([]string)
+	if len(sources) != 2 || sources[0] != SourceGeneratorMeta || sources[1] != SourceReadme {
+		t.Fatalf("expected generator and readme sources, got %v", agreeingRes.Metadata)
+	}
+}
+
+func TestVersionDetectorWeakSingleSource(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`<script src="/wp-includes/js/wp-emoji-release.min.js?ver=6.4.2"></script>`))
+	}))
+	defer ts.Close()
+
+	res, err := NewVersionDetector(ts.Client()).Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+	if res.Metadata[MetaVersion] != "6.4.2" || res.Metadata[MetaSource] != SourceAssetVersion {
+		t.Fatalf("expected asset version 6.4.2, got %v", res.Metadata)
+	}
+	if res.Confidence != AssetVersionConfidence {
+		t.Fatalf("expected weak single-source confidence %v, got %v", AssetVersionConfidence, res.Confidence)
+	}
+}
+
+func TestVersionDetectorFetchesConfiguredHomePath(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.php" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`<meta name="generator" content="WordPress 6.4.3" />`))
+	}))
+	defer ts.Close()
+
+	detector := newVersionDetectorFromOptions(DetectorOptions{Client: ts.Client(), HomePath: "/index.php"})
+	res, err := detector.Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	if res.Metadata["version"] != "6.4.3" {
+		t.Fatalf("expected version 6.4.3 from /index.php, got %v", res.Metadata)
+	}
+}
+
+func TestVersionDetectorFlagsVersionsBelowMinimum(t *testing.T) {
+	tests := []struct {
+		name         string
+		version      string
+		severity     string
+		wantSeverity string
+	}{
+		{name: "below minimum", version: "6.3.2", wantSeverity: DefaultOutdatedSeverity},
+		{name: "below minimum critical", version: "5.9", severity: "critical", wantSeverity: "critical"},
+		{name: "at minimum", version: "6.4", wantSeverity: "info"},
+		{name: "above minimum", version: "6.5.1", wantSeverity: "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `<meta name="generator" content="WordPress %s" />`, tt.version)
+			}))
+			defer ts.Close()
+
+			detector := newVersionDetectorFromOptions(DetectorOptions{Client: ts.Client(), MinVersion: "6.4", OutdatedSeverity: tt.severity})
+			res, err := detector.Detect(context.Background(), ts.URL)
+			if err != nil {
+				t.Fatalf("detect failed: %v", err)
+			}
+
+			if res.Severity != tt.wantSeverity {
+				t.Fatalf("severity = %q, want %q (%+v)", res.Severity, tt.wantSeverity, res)
+			}
+			_, outdated := res.Metadata["minVersion"]
+			if outdated != (tt.wantSeverity != "info") {
+				t.Fatalf("expected minVersion metadata only for outdated versions, got %v", res.Metadata)
+			}
+		})
+	}
+}
+
+func TestVersionRegexTrickyGeneratorStrings(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{body: `<meta name="generator" content="WordPress 6.5.1" />`, want: "6.5.1"},
+		{body: `<meta name="generator" content="WordPress 6.5.1-de_DE" />`, want: "6.5.1"},
+		{body: `<meta name="generator" content="WordPress 6" />`, want: ""},
+		{body: `<meta name="generator" content="WordPress.org" />`, want: ""},
+		{body: `<meta name="generator" content="MyWordPress 5.0" />`, want: ""},
+		{body: `<meta name="generator" content="WordPress 6.5beta2" />`, want: ""},
+	}
+
+	for _, tt := range tests {
+		got := ""
+		if matches := versionRegex.FindStringSubmatch(tt.body); len(matches) >= 2 {
+			got = matches[1]
+		}
+		if got != tt.want {
+			t.Fatalf("versionRegex on %s = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestVersionDetectorUsesConfiguredVersionPattern(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<meta name="generator" content="WordPress-Version: 6.4.3" />`))
+	}))
+	defer ts.Close()
+
+	pattern, err := ParseVersionPattern(`WordPress-Version:\s*([0-9.]+)`)
+	if err != nil {
+		t.Fatalf("parse pattern: %v", err)
+	}
+	detector := newVersionDetectorFromOptions(DetectorOptions{Client: ts.Client(), VersionPattern: pattern})
+	res, err := detector.Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+	if res.Metadata["version"] != "6.4.3" {
+		t.Fatalf("expected version 6.4.3 from the custom pattern, got %v", res.Metadata)
+	}
+}
+
+func TestParseVersionPatternRequiresCaptureGroup(t *testing.T) {
+	if _, err := ParseVersionPattern(`WordPress [0-9.]+`); err == nil {
+		t.Fatalf("expected an error for a pattern without a capture group")
+	}
+	if _, err := ParseVersionPattern(`WordPress (`); err == nil {
+		t.Fatalf("expected an error for an invalid pattern")
+	}
+}
+
+func TestVersionDetectorHeadFirstSkipsNonHTML(t *testing.T) {
+	var gets int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets++
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer ts.Close()
+
+	detector := newVersionDetectorFromOptions(DetectorOptions{Client: ts.Client(), HeadFirst: true})
+	res, err := detector.Detect(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("expected a skip result, got error: %v", err)
+	}
+	if gets != 0 {
+		t.Fatalf("expected no GET for a non-HTML target, got %d", gets)
+	}
+	if res.Severity != "info" || !strings.Contains(res.Summary, "not HTML") {
+		t.Fatalf("unexpected skip result: %+v", res)
+	}
+	if res.Metadata["contentType"] != "application/json; charset=utf-8" || res.Metadata["skipped"] != true {
+		t.Fatalf("expected content type and skipped in metadata, got %v", res.Metadata)
+	}
+}
+
+func TestVersionDetectorHeadFirstFallsBackToGET(t *testing.T) {
+	tests := []struct {
+		name        string
+		headStatus  int
+		headContent string
+	}{
+		{name: "html", headStatus: http.StatusOK, headContent: "text/html; charset=UTF-8"},
+		{name: "head not allowed", headStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodHead {
+					if tt.headContent != "" {
+						w.Header().Set("Content-Type", tt.headContent)
+					}
+					w.WriteHeader(tt.headStatus)
+					return
+				}
+				w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+				_, _ = w.Write([]byte(`<meta name="generator" content="WordPress 6.5.1" />`))
+			}))
+			defer ts.Close()
+
+			detector := newVersionDetectorFromOptions(DetectorOptions{Client: ts.Client(), HeadFirst: true})
+			res, err := detector.Detect(context.Background(), ts.URL)
+			if err != nil {
+				t.Fatalf("detect: %v", err)
+			}
+			if res.Metadata[MetaVersion] != "6.5.1" || res.Metadata["contentType"] != "text/html; charset=UTF-8" {
+				t.Fatalf("expected the version and content type, got %v", res.Metadata)
+			}
+		})
+	}
+}
@@ -15,10 +15,16 @@ type Event struct {
 	Fields    map[string]interface{} `json:"fields,omitempty"`
 }
 
+// Sink receives a copy of every emitted event, e.g. to forward it to syslog.
+type Sink interface {
+	Send(evt Event) error
+}
+
 // Emitter writes NDJSON events to an io.Writer safely across goroutines.
 type Emitter struct {
 	writer io.Writer
 	mu     sync.Mutex
+	sinks  []Sink
 }
 
 // NewEmitter returns a new NDJSON emitter.
@@ -26,6 +32,15 @@ func NewEmitter(w io.Writer) *Emitter {
 	return &Emitter{writer: w}
 }
 
+// AddSink registers an additional destination for events. Sinks are best effort:
+// a failing sink never fails Emit, so the NDJSON stream stays authoritative. Sinks
+// run under the emitter's lock and must not block.
+func (e *Emitter) AddSink(s Sink) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sinks = append(e.sinks, s)
+}
+
 // Emit serializes the event to JSON and appends a newline.
 func (e *Emitter) Emit(evt Event) error {
 	if evt.Timestamp.IsZero() {
@@ -44,5 +59,9 @@ func (e *Emitter) Emit(evt Event) error {
 		return err
 	}
 
+	for _, sink := range e.sinks {
+		_ = sink.Send(evt)
+	}
+
 	return nil
 }
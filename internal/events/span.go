@@ -0,0 +1,60 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanSink records every event as an OpenTelemetry span event on span, typically the
+// span covering a whole scan. Event fields become attributes; values other than
+// strings, booleans, and numbers are JSON-encoded.
+type SpanSink struct {
+	span trace.Span
+}
+
+// NewSpanSink returns a sink that adds events to span.
+func NewSpanSink(span trace.Span) *SpanSink {
+	return &SpanSink{span: span}
+}
+
+// Send implements Sink.
+func (s *SpanSink) Send(evt Event) error {
+	attrs := make([]attribute.KeyValue, 0, len(evt.Fields)+1)
+	if evt.Message != "" {
+		attrs = append(attrs, attribute.String("message", evt.Message))
+	}
+	for key, value := range evt.Fields {
+		attrs = append(attrs, spanAttribute(key, value))
+	}
+
+	opts := []trace.EventOption{trace.WithAttributes(attrs...)}
+	if !evt.Timestamp.IsZero() {
+		opts = append(opts, trace.WithTimestamp(evt.Timestamp))
+	}
+	s.span.AddEvent(evt.Type, opts...)
+	return nil
+}
+
+func spanAttribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	}
+	if data, err := json.Marshal(value); err == nil {
+		return attribute.String(key, string(data))
+	}
+	return attribute.String(key, fmt.Sprint(value))
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpanSinkRecordsEvents(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Tracer("test").Start(context.Background(), "scan")
+	emitter := NewEmitter(&discardWriter{})
+	emitter.AddSink(NewSpanSink(span))
+
+	if err := emitter.Emit(Event{Type: "scan-start", Message: "Starting scan", Fields: map[string]interface{}{"targets": 2, "dryRun": false, "paths": []string{"a"}, "meta": map[string]int{"x": 1}}}); err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || len(spans[0].Events) != 1 {
+		t.Fatalf("expected one span with one event, got %+v", spans)
+	}
+
+	evt := spans[0].Events[0]
+	if evt.Name != "scan-start" {
+		t.Fatalf("event name = %q, want scan-start", evt.Name)
+	}
+	got := map[string]string{}
+	for _, attr := range evt.Attributes {
+		got[string(attr.Key)] = attr.Value.Emit()
+	}
+	want := map[string]string{"message": "Starting scan", "targets": "2", "dryRun": "false", "paths": `["a"]`, "meta": `{"x":1}`}
+	for key, value := range want {
+		if got[key] != value {
+			t.Fatalf("attribute %s = %q, want %q (all: %v)", key, got[key], value, got)
+		}
+	}
+}
+
+type discardWriter struct{}
+
+func (discardWriter) Write(p []byte) (int, error) { return len(p), nil }
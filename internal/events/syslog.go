@@ -0,0 +1,207 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSyslogAddr is the local syslog socket used when no address is given.
+const DefaultSyslogAddr = "unixgram:///dev/log"
+
+// Syslog severities (RFC 5424 section 6.2.1).
+const (
+	syslogCritical = 2
+	syslogError    = 3
+	syslogWarning  = 4
+	syslogNotice   = 5
+	syslogInfo     = 6
+)
+
+// syslogFacilityUser is the "user-level messages" facility.
+const syslogFacilityUser = 1
+
+// syslogQueueSize bounds the messages waiting for the syslog writer; further
+// events are dropped until it catches up.
+const syslogQueueSize = 1024
+
+// syslogWriteTimeout bounds each write to the collector, and Close's wait for the
+// queue to drain.
+const syslogWriteTimeout = 2 * time.Second
+
+// errSyslogQueueFull is returned by Send when the event was dropped.
+var errSyslogQueueFull = errors.New("syslog queue full; event dropped")
+
+// SyslogSink forwards events to a syslog server as RFC 5424 messages whose
+// MSG part is the event's JSON encoding. Messages are written by a background
+// goroutine, so a stalled collector never blocks Emit: once the queue is full,
+// events are dropped for syslog while the NDJSON stream keeps them.
+type SyslogSink struct {
+	conn     net.Conn
+	hostname string
+	appName  string
+	queue    chan []byte
+	done     chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewSyslogSink dials addr, given as network://address (udp://host:514,
+// tcp://host:601, unixgram:///dev/log). A bare host:port is treated as UDP.
+func NewSyslogSink(addr string) (*SyslogSink, error) {
+	if addr == "" {
+		addr = DefaultSyslogAddr
+	}
+
+	network, address := "udp", addr
+	if idx := strings.Index(addr, "://"); idx >= 0 {
+		network, address = addr[:idx], addr[idx+3:]
+	}
+
+	switch network {
+	case "udp", "tcp", "unix", "unixgram":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %q (expected udp, tcp, unix, or unixgram)", network)
+	}
+
+	conn, err := net.DialTimeout(network, address, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("connect to syslog %s: %w", addr, err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	s := &SyslogSink{
+		conn:     conn,
+		hostname: hostname,
+		appName:  "wphunter",
+		queue:    make(chan []byte, syslogQueueSize),
+		done:     make(chan struct{}),
+	}
+	go s.write()
+	return s, nil
+}
+
+// Send implements Sink.
+func (s *SyslogSink) Send(evt Event) error {
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	timestamp := evt.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now().UTC()
+	}
+
+	priority := syslogFacilityUser*8 + syslogSeverity(evt)
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d %s - %s", priority, timestamp.Format(time.RFC3339Nano), s.hostname, s.appName, os.Getpid(), msgID(evt.Type), payload)
+
+	if _, ok := s.conn.(*net.TCPConn); ok {
+		// Octet-counting framing (RFC 6587) for stream transports.
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return net.ErrClosed
+	}
+	select {
+	case s.queue <- []byte(msg):
+		return nil
+	default:
+		return errSyslogQueueFull
+	}
+}
+
+// write sends queued messages until the queue is closed. Each write gets
+// syslogWriteTimeout; after a failed write on a stream transport the framing can
+// no longer be trusted, so the remaining messages are discarded.
+func (s *SyslogSink) write() {
+	defer close(s.done)
+
+	_, stream := s.conn.(*net.TCPConn)
+	broken := false
+	for msg := range s.queue {
+		if broken {
+			continue
+		}
+		_ = s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+		if _, err := s.conn.Write(msg); err != nil && stream {
+			broken = true
+		}
+	}
+}
+
+// Close flushes queued messages for up to syslogWriteTimeout, then releases the
+// syslog connection.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	timer := time.NewTimer(syslogWriteTimeout)
+	defer timer.Stop()
+	select {
+	case <-s.done:
+		return s.conn.Close()
+	case <-timer.C:
+		// Closing the connection fails the pending write, so the writer exits.
+		err := s.conn.Close()
+		<-s.done
+		return err
+	}
+}
+
+// syslogSeverity maps an event onto a syslog severity. Detection events use the
+// finding severity; other events are informational unless their type signals a failure.
+func syslogSeverity(evt Event) int {
+	if evt.Type == "detection" {
+		severity, _ := evt.Fields["severity"].(string)
+		switch strings.ToLower(severity) {
+		case "critical":
+			return syslogCritical
+		case "high":
+			return syslogError
+		case "medium", "warning":
+			return syslogWarning
+		case "low":
+			return syslogNotice
+		}
+		return syslogInfo
+	}
+
+	if strings.Contains(evt.Type, "error") || strings.Contains(evt.Type, "failed") || strings.Contains(evt.Type, "unavailable") {
+		return syslogError
+	}
+	return syslogInfo
+}
+
+// msgID turns an event type into a valid RFC 5424 MSGID (printable ASCII, max 32 chars).
+func msgID(eventType string) string {
+	if eventType == "" {
+		return "-"
+	}
+	if len(eventType) > 32 {
+		eventType = eventType[:32]
+	}
+	return strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, eventType)
+}
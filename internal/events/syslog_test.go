@@ -0,0 +1,123 @@
+package events
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogSinkSendsEventsOverUDP(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp: %v", err)
+	}
+	defer listener.Close()
+
+	sink, err := NewSyslogSink("udp://" + listener.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("new syslog sink: %v", err)
+	}
+	defer sink.Close()
+
+	emitter := NewEmitter(&strings.Builder{})
+	emitter.AddSink(sink)
+
+	if err := emitter.Emit(Event{Type: "detection", Message: "exposed", Fields: map[string]interface{}{"severity": "critical"}}); err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	if err := listener.SetReadDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		t.Fatalf("set deadline: %v", err)
+	}
+	n, _, err := listener.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read syslog message: %v", err)
+	}
+
+	msg := string(buf[:n])
+	// user facility (1) * 8 + critical (2) = 10
+	if !strings.HasPrefix(msg, "<10>1 ") {
+		t.Fatalf("unexpected priority header: %q", msg)
+	}
+	if !strings.Contains(msg, " wphunter ") || !strings.Contains(msg, " detection - ") {
+		t.Fatalf("missing app name or msgid: %q", msg)
+	}
+	if !strings.Contains(msg, `"message":"exposed"`) {
+		t.Fatalf("missing event payload: %q", msg)
+	}
+}
+
+func TestSyslogSinkDoesNotBlockOnStalledCollector(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen tcp: %v", err)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Accept but never read, like a collector that stopped draining its socket.
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			<-stop
+		}
+	}()
+	t.Cleanup(func() {
+		close(stop)
+		listener.Close()
+		<-done
+	})
+
+	sink, err := NewSyslogSink("tcp://" + listener.Addr().String())
+	if err != nil {
+		t.Fatalf("new syslog sink: %v", err)
+	}
+
+	emitter := NewEmitter(&strings.Builder{})
+	emitter.AddSink(sink)
+
+	// Far more than the socket buffers hold, so a synchronous write would stall.
+	padding := strings.Repeat("x", 4*1024)
+	start := time.Now()
+	for i := 0; i < 2*syslogQueueSize; i++ {
+		if err := emitter.Emit(Event{Type: "detection", Message: padding}); err != nil {
+			t.Fatalf("emit: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected emits to bypass the stalled collector, took %s", elapsed)
+	}
+
+	start = time.Now()
+	_ = sink.Close()
+	if elapsed := time.Since(start); elapsed > 2*syslogWriteTimeout {
+		t.Fatalf("expected close to give up after the write timeout, took %s", elapsed)
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	tests := []struct {
+		evt      Event
+		expected int
+	}{
+		{evt: Event{Type: "scan-start"}, expected: syslogInfo},
+		{evt: Event{Type: "syslog-unavailable"}, expected: syslogError},
+		{evt: Event{Type: "detection", Fields: map[string]interface{}{"severity": "warning"}}, expected: syslogWarning},
+		{evt: Event{Type: "detection", Fields: map[string]interface{}{"severity": "info"}}, expected: syslogInfo},
+	}
+
+	for _, tt := range tests {
+		if got := syslogSeverity(tt.evt); got != tt.expected {
+			t.Fatalf("%s: expected severity %d, got %d", tt.evt.Type, tt.expected, got)
+		}
+	}
+}
+
+func TestNewSyslogSinkRejectsUnknownNetwork(t *testing.T) {
+	if _, err := NewSyslogSink("http://localhost:514"); err == nil {
+		t.Fatal("expected error for unsupported network")
+	}
+}
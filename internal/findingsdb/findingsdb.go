@@ -0,0 +1,96 @@
+// Package findingsdb exports detector results to a SQLite database so findings can be
+// queried with SQL across many scans.
+package findingsdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/example/wphunter/internal/detector"
+
+	// Register the pure-Go SQLite driver (no cgo required).
+	_ "modernc.org/sqlite"
+)
+
+const schema = `CREATE TABLE IF NOT EXISTS findings (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id TEXT NOT NULL,
+	target TEXT NOT NULL,
+	detector TEXT NOT NULL,
+	severity TEXT NOT NULL,
+	confidence REAL,
+	summary TEXT NOT NULL,
+	metadata TEXT,
+	detected_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS findings_run_id ON findings (run_id);
+CREATE INDEX IF NOT EXISTS findings_target ON findings (target);`
+
+// DB is a findings database opened with Open.
+type DB struct {
+	db *sql.DB
+}
+
+// Open opens (or creates) the SQLite database at path and ensures the findings schema exists.
+func Open(path string) (*DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open findings database %s: %w", path, err)
+	}
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create findings schema in %s: %w", path, err)
+	}
+
+	return &DB{db: db}, nil
+}
+
+// Insert stores results under runID in a single transaction.
+func (d *DB) Insert(ctx context.Context, runID string, results []detector.Result) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO findings (run_id, target, detector, severity, confidence, summary, metadata, detected_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, res := range results {
+		var metadata interface{}
+		if len(res.Metadata) > 0 {
+			data, err := json.Marshal(res.Metadata)
+			if err != nil {
+				return fmt.Errorf("encode metadata for %s/%s: %w", res.Target, res.Detector, err)
+			}
+			metadata = string(data)
+		}
+
+		detectedAt := res.DetectedAt
+		if detectedAt.IsZero() {
+			detectedAt = time.Now()
+		}
+
+		if _, err := stmt.ExecContext(ctx, runID, res.Target, res.Detector, res.Severity, res.Confidence, res.Summary, metadata, detectedAt.UTC().Format(time.RFC3339Nano)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Close releases the database handle.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
@@ -0,0 +1,60 @@
+package findingsdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/example/wphunter/internal/detector"
+)
+
+func TestInsertAndQueryFindings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "findings.sqlite")
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	results := []detector.Result{
+		{Target: "https://one.test", Detector: "version", Severity: "info", Summary: "WordPress version 6.5.1 detected", Confidence: 0.85, Metadata: map[string]interface{}{"version": "6.5.1"}, DetectedAt: time.Now()},
+		{Target: "https://two.test", Detector: "vcs", Severity: "critical", Summary: "Exposed sensitive files: /.git/config"},
+	}
+	if err := db.Insert(context.Background(), "run-1", results); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := db.Insert(context.Background(), "run-2", results[:1]); err != nil {
+		t.Fatalf("insert second run: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	// Reopening must keep existing rows and tolerate the existing schema.
+	db, err = Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer db.Close()
+
+	var total, critical int
+	if err := db.db.QueryRow(`SELECT COUNT(*) FROM findings`).Scan(&total); err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	if err := db.db.QueryRow(`SELECT COUNT(*) FROM findings WHERE run_id = 'run-1' AND severity = 'critical'`).Scan(&critical); err != nil {
+		t.Fatalf("count critical rows: %v", err)
+	}
+
+	if total != 3 || critical != 1 {
+		t.Fatalf("expected 3 rows with 1 critical in run-1, got %d and %d", total, critical)
+	}
+
+	var metadata string
+	if err := db.db.QueryRow(`SELECT metadata FROM findings WHERE detector = 'version' LIMIT 1`).Scan(&metadata); err != nil {
+		t.Fatalf("read metadata: %v", err)
+	}
+	if metadata != `{"version":"6.5.1"}` {
+		t.Fatalf("unexpected metadata JSON %q", metadata)
+	}
+}
@@ -0,0 +1,204 @@
+// Package tui renders an interactive terminal view of a running scan by consuming
+// the same events that are otherwise written as NDJSON.
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/example/wphunter/internal/events"
+)
+
+// maxVisibleFindings caps how many of the most recent findings are rendered.
+const maxVisibleFindings = 15
+
+// EventMsg delivers a scan event to the model.
+type EventMsg events.Event
+
+// FinishedMsg tells the model the scan has ended, with its error if any.
+type FinishedMsg struct {
+	Err error
+}
+
+type finding struct {
+	target   string
+	detector string
+	severity string
+	summary  string
+}
+
+type targetState struct {
+	findings int
+	skipped  bool
+}
+
+// Model is the bubbletea model for the interactive scan view.
+type Model struct {
+	targets   []string
+	states    map[string]*targetState
+	findings  []finding
+	artifacts int
+	lastEvent string
+	finished  bool
+	err       error
+}
+
+// NewModel returns a model tracking targets, all initially pending.
+func NewModel(targets []string) Model {
+	states := make(map[string]*targetState, len(targets))
+	for _, target := range targets {
+		states[target] = &targetState{}
+	}
+	return Model{targets: targets, states: states}
+}
+
+// Init implements tea.Model.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		}
+	case FinishedMsg:
+		m.finished = true
+		m.err = msg.Err
+	case EventMsg:
+		m.applyEvent(events.Event(msg))
+	}
+	return m, nil
+}
+
+func (m *Model) applyEvent(evt events.Event) {
+	m.lastEvent = evt.Type
+	if evt.Message != "" {
+		m.lastEvent += ": " + evt.Message
+	}
+
+	target, _ := evt.Fields["target"].(string)
+	switch evt.Type {
+	case "detection":
+		m.findings = append(m.findings, finding{
+			target:   target,
+			detector: fmt.Sprint(evt.Fields["detector"]),
+			severity: fmt.Sprint(evt.Fields["severity"]),
+			summary:  evt.Message,
+		})
+		m.state(target).findings++
+	case "target-skipped":
+		m.state(target).skipped = true
+	case "artifact-written":
+		m.artifacts++
+	case "scan-finished":
+		m.finished = true
+	}
+}
+
+// state returns the tracked state for target, adding targets the model was not told about.
+func (m *Model) state(target string) *targetState {
+	if st, ok := m.states[target]; ok {
+		return st
+	}
+	st := &targetState{}
+	m.states[target] = st
+	m.targets = append(m.targets, target)
+	return st
+}
+
+// View implements tea.Model.
+func (m Model) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "wphunter scan: %d target(s), %d finding(s), %d artifact(s)\n\n", len(m.targets), len(m.findings), m.artifacts)
+
+	b.WriteString("Targets\n")
+	for _, target := range m.targets {
+		st := m.states[target]
+		status := "pending"
+		switch {
+		case st.skipped:
+			status = "skipped"
+		case st.findings > 0:
+			status = fmt.Sprintf("%d finding(s)", st.findings)
+		case m.finished:
+			status = "done"
+		}
+		fmt.Fprintf(&b, "  %-40s %s\n", target, status)
+	}
+
+	b.WriteString("\nFindings\n")
+	if len(m.findings) == 0 {
+		b.WriteString("  (none yet)\n")
+	}
+	start := 0
+	if len(m.findings) > maxVisibleFindings {
+		start = len(m.findings) - maxVisibleFindings
+	}
+	for _, f := range m.findings[start:] {
+		fmt.Fprintf(&b, "  [%-8s] %-10s %s: %s\n", f.severity, f.detector, f.target, f.summary)
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.err != nil:
+		fmt.Fprintf(&b, "Scan failed: %v (press q to quit)\n", m.err)
+	case m.finished:
+		b.WriteString("Scan complete (press q to quit)\n")
+	default:
+		fmt.Fprintf(&b, "Scanning... %s (press q to abort)\n", m.lastEvent)
+	}
+
+	return b.String()
+}
+
+// Session runs the interactive view alongside a scan. It implements events.Sink so it
+// can be attached to the scan's emitter.
+type Session struct {
+	program  *tea.Program
+	done     chan error
+	finished chan struct{}
+}
+
+// Start launches the terminal UI on in/out. onAbort is called when the user quits
+// before the scan has finished so the caller can cancel it.
+func Start(targets []string, in io.Reader, out io.Writer, onAbort func()) *Session {
+	s := &Session{
+		program:  tea.NewProgram(NewModel(targets), tea.WithInput(in), tea.WithOutput(out)),
+		done:     make(chan error, 1),
+		finished: make(chan struct{}),
+	}
+
+	go func() {
+		_, err := s.program.Run()
+		select {
+		case <-s.finished:
+		default:
+			if onAbort != nil {
+				onAbort()
+			}
+		}
+		s.done <- err
+	}()
+
+	return s
+}
+
+// Send implements events.Sink.
+func (s *Session) Send(evt events.Event) error {
+	s.program.Send(EventMsg(evt))
+	return nil
+}
+
+// Finish reports the scan outcome and blocks until the user closes the UI.
+func (s *Session) Finish(err error) error {
+	close(s.finished)
+	s.program.Send(FinishedMsg{Err: err})
+	return <-s.done
+}
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/example/wphunter/internal/events"
+)
+
+func TestModelProcessesDetectionEvent(t *testing.T) {
+	var model tea.Model = NewModel([]string{"https://one.test", "https://two.test"})
+	if cmd := model.Init(); cmd != nil {
+		t.Fatal("expected no initial command")
+	}
+
+	model, _ = model.Update(EventMsg(events.Event{
+		Type:    "detection",
+		Message: "Exposed sensitive files: /.git/config",
+		Fields:  map[string]interface{}{"target": "https://two.test", "detector": "vcs", "severity": "critical"},
+	}))
+	model, _ = model.Update(EventMsg(events.Event{Type: "target-skipped", Fields: map[string]interface{}{"target": "https://one.test"}}))
+
+	view := model.View()
+	for _, expected := range []string{"1 finding(s)", "[critical]", "/.git/config", "skipped"} {
+		if !strings.Contains(view, expected) {
+			t.Fatalf("expected view to contain %q:\n%s", expected, view)
+		}
+	}
+
+	model, _ = model.Update(FinishedMsg{Err: errors.New("boom")})
+	if !strings.Contains(model.View(), "Scan failed: boom") {
+		t.Fatalf("expected failure in view:\n%s", model.View())
+	}
+
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Fatal("expected q to quit")
+	}
+}
@@ -0,0 +1,228 @@
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// DefaultSSHPort is used when the tunnel spec omits a port.
+const DefaultSSHPort = 22
+
+// handshakeTimeout bounds connecting to and authenticating with the jump host.
+const handshakeTimeout = 15 * time.Second
+
+// Config identifies the SSH jump host used to reach internal targets.
+type Config struct {
+	User string
+	Host string
+	Port int
+	// KeyFile is an optional private key; the SSH agent is used when empty.
+	KeyFile string
+	// KnownHostsFile verifies the jump host key; defaults to ~/.ssh/known_hosts.
+	KnownHostsFile string
+}
+
+// ParseConfig parses a `user@host[:port]` tunnel spec.
+func ParseConfig(spec string) (Config, error) {
+	spec = strings.TrimSpace(spec)
+	at := strings.LastIndex(spec, "@")
+	if at <= 0 || at == len(spec)-1 {
+		return Config{}, fmt.Errorf("invalid ssh tunnel %q: expected user@host[:port]", spec)
+	}
+
+	cfg := Config{User: spec[:at], Port: DefaultSSHPort}
+	hostPort := spec[at+1:]
+
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		// No port present; accept a bare host (including bracketed IPv6 literals).
+		host = strings.TrimSuffix(strings.TrimPrefix(hostPort, "["), "]")
+		if host == "" || strings.ContainsAny(host, "[]") {
+			return Config{}, fmt.Errorf("invalid ssh tunnel %q: %w", spec, err)
+		}
+		cfg.Host = host
+		return cfg, nil
+	}
+
+	if host == "" {
+		return Config{}, fmt.Errorf("invalid ssh tunnel %q: missing host", spec)
+	}
+
+	parsed, err := strconv.Atoi(port)
+	if err != nil || parsed < 1 || parsed > 65535 {
+		return Config{}, fmt.Errorf("invalid ssh tunnel %q: port must be between 1 and 65535", spec)
+	}
+
+	cfg.Host = host
+	cfg.Port = parsed
+	return cfg, nil
+}
+
+// Addr returns the host:port of the jump host.
+func (c Config) Addr() string {
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+// Tunnel forwards TCP connections through an established SSH session,
+// equivalent to an `ssh -L` local forward for every dialed address.
+type Tunnel struct {
+	client *ssh.Client
+	// agent is the ssh-agent connection used for authentication; nil with a key file.
+	agent net.Conn
+}
+
+// Dial connects to the jump host described by cfg.
+func Dial(ctx context.Context, cfg Config) (_ *Tunnel, err error) {
+	auth, agentConn, err := authMethods(cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if agentConn != nil {
+		defer func() {
+			if err != nil {
+				agentConn.Close()
+			}
+		}()
+	}
+
+	hostKeyCallback, err := hostKeyCallback(cfg.KnownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	clientCfg := &ssh.ClientConfig{
+		User:            cfg.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         handshakeTimeout,
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", cfg.Addr())
+	if err != nil {
+		return nil, fmt.Errorf("ssh tunnel: %w", err)
+	}
+
+	// A jump host that accepts TCP and then stalls must not hang the scan, so the
+	// handshake is bounded by handshakeTimeout and ctx; the deadline is cleared
+	// once the session is up.
+	deadline := time.Now().Add(handshakeTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ssh tunnel: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Unix(1, 0)) })
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, cfg.Addr(), clientCfg)
+	if !stop() && err == nil {
+		err = ctx.Err()
+		sshConn.Close()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ssh tunnel: %w", err)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		sshConn.Close()
+		return nil, fmt.Errorf("ssh tunnel: %w", err)
+	}
+
+	return &Tunnel{client: ssh.NewClient(sshConn, chans, reqs), agent: agentConn}, nil
+}
+
+// DialContext opens a forwarded connection to addr from the jump host.
+// It matches the signature of net.Dialer.DialContext so it can back an http.Transport.
+func (t *Tunnel) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		conn, err := t.client.Dial(network, addr)
+		done <- result{conn: conn, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		// Close the connection if it arrives after the caller gave up.
+		go func() {
+			if res := <-done; res.conn != nil {
+				res.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	case res := <-done:
+		return res.conn, res.err
+	}
+}
+
+// Close tears down the SSH session and all forwarded connections, and releases the
+// ssh-agent connection.
+func (t *Tunnel) Close() error {
+	err := t.client.Close()
+	if t.agent != nil {
+		if agentErr := t.agent.Close(); err == nil {
+			err = agentErr
+		}
+	}
+	return err
+}
+
+// authMethods returns the key file's signer, or the ssh-agent's signers together with
+// the agent connection, which the caller must close.
+func authMethods(keyFile string) ([]ssh.AuthMethod, net.Conn, error) {
+	if keyFile != "" {
+		key, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read ssh key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse ssh key: %w", err)
+		}
+		return []ssh.AuthMethod{ssh.PublicKeys(signer)}, nil, nil
+	}
+
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, nil, errors.New("ssh tunnel requires --ssh-key or a running ssh-agent (SSH_AUTH_SOCK)")
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connect to ssh-agent: %w", err)
+	}
+	return []ssh.AuthMethod{ssh.PublicKeysCallback(agent.NewClient(conn).Signers)}, conn, nil
+}
+
+func hostKeyCallback(path string) (ssh.HostKeyCallback, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("locate known_hosts: %w", err)
+		}
+		path = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("load known_hosts for ssh tunnel: %w", err)
+	}
+	return callback, nil
+}
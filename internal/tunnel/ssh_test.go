@@ -0,0 +1,128 @@
+package tunnel
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected Config
+		wantErr  bool
+	}{
+		{
+			name:     "host with port",
+			spec:     "deploy@bastion.example.com:2222",
+			expected: Config{User: "deploy", Host: "bastion.example.com", Port: 2222},
+		},
+		{
+			name:     "default port",
+			spec:     "deploy@bastion.example.com",
+			expected: Config{User: "deploy", Host: "bastion.example.com", Port: DefaultSSHPort},
+		},
+		{
+			name:     "ipv6 with port",
+			spec:     "ops@[2001:db8::1]:22",
+			expected: Config{User: "ops", Host: "2001:db8::1", Port: 22},
+		},
+		{
+			name:     "ipv6 without port",
+			spec:     "ops@[2001:db8::1]",
+			expected: Config{User: "ops", Host: "2001:db8::1", Port: DefaultSSHPort},
+		},
+		{name: "missing user", spec: "bastion.example.com:22", wantErr: true},
+		{name: "missing host", spec: "deploy@", wantErr: true},
+		{name: "empty host with port", spec: "deploy@:22", wantErr: true},
+		{name: "non numeric port", spec: "deploy@bastion:ssh", wantErr: true},
+		{name: "port out of range", spec: "deploy@bastion:70000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseConfig(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got %+v", tt.spec, cfg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg != tt.expected {
+				t.Fatalf("expected %+v, got %+v", tt.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestConfigAddr(t *testing.T) {
+	cfg := Config{User: "ops", Host: "2001:db8::1", Port: 2222}
+	if got := cfg.Addr(); got != "[2001:db8::1]:2222" {
+		t.Fatalf("unexpected addr %s", got)
+	}
+}
+
+func TestDialBoundsStalledHandshake(t *testing.T) {
+	// The jump host accepts TCP but never speaks SSH.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+	t.Cleanup(func() {
+		listener.Close()
+		select {
+		case conn := <-accepted:
+			conn.Close()
+		default:
+		}
+	})
+
+	dir := t.TempDir()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	keyFile := filepath.Join(dir, "id_ed25519")
+	knownHosts := filepath.Join(dir, "known_hosts")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	if err := os.WriteFile(knownHosts, nil, 0o600); err != nil {
+		t.Fatalf("write known_hosts: %v", err)
+	}
+
+	addr := listener.Addr().(*net.TCPAddr)
+	cfg := Config{User: "scanner", Host: "127.0.0.1", Port: addr.Port, KeyFile: keyFile, KnownHostsFile: knownHosts}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := Dial(ctx, cfg); err == nil {
+		t.Fatal("expected the stalled handshake to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected the handshake to stop at the context deadline, took %s", elapsed)
+	}
+}
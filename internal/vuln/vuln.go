@@ -0,0 +1,152 @@
+// Package vuln correlates detector findings with a local vulnerability feed.
+package vuln
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/example/wphunter/internal/detector"
+)
+
+// CoreSlug is the feed slug used for WordPress core, matched against the version detector.
+const CoreSlug = "wordpress"
+
+// Entry describes one vulnerability affecting a range of versions of a component.
+// The range is [Introduced, Fixed) when Fixed is set, otherwise [Introduced, LastAffected].
+// An empty Introduced means every version up to the upper bound is affected.
+type Entry struct {
+	Slug         string `json:"slug"`
+	Type         string `json:"type,omitempty"`
+	CVE          string `json:"cve"`
+	Title        string `json:"title,omitempty"`
+	Severity     string `json:"severity"`
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"lastAffected,omitempty"`
+}
+
+// Affects reports whether version falls inside the entry's range.
+func (e Entry) Affects(version string) bool {
+	if version == "" || version == "unknown" {
+		return false
+	}
+	if e.Introduced != "" && detector.CompareVersions(version, e.Introduced) < 0 {
+		return false
+	}
+	if e.Fixed != "" {
+		return detector.CompareVersions(version, e.Fixed) < 0
+	}
+	if e.LastAffected != "" {
+		return detector.CompareVersions(version, e.LastAffected) <= 0
+	}
+	// An entry without an upper bound affects every version from Introduced on.
+	return e.Introduced != ""
+}
+
+// DB indexes feed entries by slug.
+type DB struct {
+	bySlug map[string][]Entry
+}
+
+// feed is the on-disk JSON layout accepted by Load.
+type feed struct {
+	Vulnerabilities []Entry `json:"vulnerabilities"`
+}
+
+// Load reads a JSON feed of the form {"vulnerabilities": [...]}.
+func Load(path string) (*DB, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read vulnerability feed: %w", err)
+	}
+
+	var f feed
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse vulnerability feed %s: %w", path, err)
+	}
+
+	return New(f.Vulnerabilities)
+}
+
+// New builds a DB from entries, rejecting entries without a slug, CVE, or version bound.
+func New(entries []Entry) (*DB, error) {
+	db := &DB{bySlug: map[string][]Entry{}}
+	for i, entry := range entries {
+		slug := strings.ToLower(strings.TrimSpace(entry.Slug))
+		if slug == "" || entry.CVE == "" {
+			return nil, fmt.Errorf("vulnerability feed entry %d: slug and cve are required", i)
+		}
+		if entry.Introduced == "" && entry.Fixed == "" && entry.LastAffected == "" {
+			return nil, fmt.Errorf("vulnerability feed entry %d (%s): a version range is required", i, entry.CVE)
+		}
+		entry.Slug = slug
+		db.bySlug[slug] = append(db.bySlug[slug], entry)
+	}
+	return db, nil
+}
+
+// Lookup returns the entries affecting slug at version.
+func (db *DB) Lookup(slug, version string) []Entry {
+	var matches []Entry
+	for _, entry := range db.bySlug[strings.ToLower(slug)] {
+		if entry.Affects(version) {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
+// Enrich annotates results whose component and version match feed entries with
+// Metadata["cve"], records each CVE's own severity in Metadata["cveSeverity"], and
+// raises the result's severity to the most severe matching entry.
+// Results identify components via Metadata["slug"] and Metadata["version"]; the
+// version detector's results are matched against WordPress core.
+func (db *DB) Enrich(results []detector.Result) []detector.Result {
+	for i, res := range results {
+		slug, version := Component(res)
+		if slug == "" {
+			continue
+		}
+
+		matches := db.Lookup(slug, version)
+		if len(matches) == 0 {
+			continue
+		}
+
+		cves := make([]string, 0, len(matches))
+		cveSeverity := make(map[string]string, len(matches))
+		severity := res.Severity
+		for _, entry := range matches {
+			cves = append(cves, entry.CVE)
+			cveSeverity[entry.CVE] = entry.Severity
+			if detector.SeverityRank(entry.Severity) > detector.SeverityRank(severity) {
+				severity = entry.Severity
+			}
+		}
+
+		if res.Metadata == nil {
+			res.Metadata = map[string]interface{}{}
+		}
+		res.Metadata[detector.MetaCVE] = cves
+		res.Metadata[detector.MetaCVESeverity] = cveSeverity
+		res.Severity = severity
+		res.Summary = fmt.Sprintf("%s (%d known vulnerabilities: %s)", res.Summary, len(cves), strings.Join(cves, ", "))
+		results[i] = res
+	}
+	return results
+}
+
+// Component returns the feed slug and version a result describes, or empty strings
+// when the result does not name a component.
+func Component(res detector.Result) (string, string) {
+	version, _ := res.Metadata[detector.MetaVersion].(string)
+	if slug, ok := res.Metadata[detector.MetaSlug].(string); ok && slug != "" {
+		return slug, version
+	}
+	if res.Detector == "version" && version != "" {
+		return CoreSlug, version
+	}
+	return "", ""
+}
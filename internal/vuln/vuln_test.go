@@ -0,0 +1,94 @@
+package vuln
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/example/wphunter/internal/detector"
+)
+
+const testFeed = `{
+  "vulnerabilities": [
+    {"slug": "contact-form-7", "type": "plugin", "cve": "CVE-2020-35489", "severity": "critical", "fixed": "5.3.2"},
+    {"slug": "contact-form-7", "type": "plugin", "cve": "CVE-2023-6449", "severity": "high", "introduced": "5.0", "lastAffected": "5.8.3"},
+    {"slug": "wordpress", "type": "core", "cve": "CVE-2022-21661", "severity": "high", "introduced": "3.7", "fixed": "5.8.3"}
+  ]
+}`
+
+func loadTestFeed(t *testing.T) *DB {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "feed.json")
+	if err := os.WriteFile(path, []byte(testFeed), 0o600); err != nil {
+		t.Fatalf("write feed: %v", err)
+	}
+
+	db, err := Load(path)
+	if err != nil {
+		t.Fatalf("load feed: %v", err)
+	}
+	return db
+}
+
+func TestEntryAffects(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    Entry
+		version  string
+		expected bool
+	}{
+		{name: "below fixed", entry: Entry{Fixed: "5.3.2"}, version: "5.3.1", expected: true},
+		{name: "at fixed", entry: Entry{Fixed: "5.3.2"}, version: "5.3.2", expected: false},
+		{name: "shorter version below fixed", entry: Entry{Fixed: "5.3.2"}, version: "5.3", expected: true},
+		{name: "before introduced", entry: Entry{Introduced: "5.0", Fixed: "5.9"}, version: "4.9.9", expected: false},
+		{name: "at introduced", entry: Entry{Introduced: "5.0", Fixed: "5.9"}, version: "5.0", expected: true},
+		{name: "at last affected", entry: Entry{LastAffected: "5.8.3"}, version: "5.8.3", expected: true},
+		{name: "after last affected", entry: Entry{LastAffected: "5.8.3"}, version: "5.8.4", expected: false},
+		{name: "numeric not lexical", entry: Entry{Fixed: "5.10"}, version: "5.9", expected: true},
+		{name: "unknown version", entry: Entry{Fixed: "9.9"}, version: "unknown", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.Affects(tt.version); got != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestEnrichMarksVulnerablePluginVersion(t *testing.T) {
+	db := loadTestFeed(t)
+
+	results := db.Enrich([]detector.Result{
+		{Target: "https://old.test", Detector: "plugins", Severity: "info", Metadata: map[string]interface{}{"slug": "contact-form-7", "version": "5.3.1"}},
+		{Target: "https://new.test", Detector: "plugins", Severity: "info", Metadata: map[string]interface{}{"slug": "contact-form-7", "version": "5.9"}},
+		{Target: "https://core.test", Detector: "version", Severity: "info", Metadata: map[string]interface{}{"version": "5.8.2"}},
+	})
+
+	if results[0].Severity != "critical" {
+		t.Fatalf("expected vulnerable plugin to be bumped to critical, got %s", results[0].Severity)
+	}
+	if !reflect.DeepEqual(results[0].Metadata[detector.MetaCVE], []string{"CVE-2020-35489", "CVE-2023-6449"}) {
+		t.Fatalf("unexpected cves: %v", results[0].Metadata[detector.MetaCVE])
+	}
+	if expected := map[string]string{"CVE-2020-35489": "critical", "CVE-2023-6449": "high"}; !reflect.DeepEqual(results[0].Metadata[detector.MetaCVESeverity], expected) {
+		t.Fatalf("expected per-CVE severities %v, got %v", expected, results[0].Metadata[detector.MetaCVESeverity])
+	}
+
+	if results[1].Severity != "info" || results[1].Metadata["cve"] != nil {
+		t.Fatalf("patched plugin should be untouched, got %+v", results[1])
+	}
+
+	if results[2].Severity != "high" || !reflect.DeepEqual(results[2].Metadata["cve"], []string{"CVE-2022-21661"}) {
+		t.Fatalf("expected core version to match feed, got %+v", results[2])
+	}
+}
+
+func TestNewRejectsEntriesWithoutRange(t *testing.T) {
+	if _, err := New([]Entry{{Slug: "akismet", CVE: "CVE-0000-0000", Severity: "high"}}); err == nil {
+		t.Fatal("expected error for entry without a version range")
+	}
+}
@@ -0,0 +1,77 @@
+package wpprobe
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// Known wpprobe exit codes.
+const (
+	// ExitFindings means the scan completed and reported findings.
+	ExitFindings = 1
+	// ExitUsage means wpprobe rejected its arguments.
+	ExitUsage = 2
+)
+
+// Exit classifications reported in ExitError.Kind.
+const (
+	ExitKindFindings = "findings"
+	ExitKindUsage    = "usage"
+	ExitKindFailure  = "failure"
+)
+
+var (
+	// ErrFindings matches (via errors.Is) wpprobe runs that exited because they found
+	// something. The scan output is complete, so callers usually keep going.
+	ErrFindings = errors.New("wpprobe reported findings")
+	// ErrUsage matches wpprobe runs that rejected their arguments.
+	ErrUsage = errors.New("wpprobe usage error")
+)
+
+// ExitError reports a wpprobe process that exited with a non-zero status.
+type ExitError struct {
+	Code int
+	Kind string
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	switch e.Kind {
+	case ExitKindFindings:
+		return fmt.Sprintf("%v (exit code %d)", ErrFindings, e.Code)
+	case ExitKindUsage:
+		return fmt.Sprintf("%v (exit code %d)", ErrUsage, e.Code)
+	}
+	return fmt.Sprintf("wpprobe failed with exit code %d: %v", e.Code, e.Err)
+}
+
+// Unwrap exposes both the classification sentinel and the underlying *exec.ExitError.
+func (e *ExitError) Unwrap() []error {
+	switch e.Kind {
+	case ExitKindFindings:
+		return []error{ErrFindings, e.Err}
+	case ExitKindUsage:
+		return []error{ErrUsage, e.Err}
+	}
+	return []error{e.Err}
+}
+
+// classifyExit turns an *exec.ExitError into an *ExitError keyed by the known wpprobe
+// exit codes. Other errors, such as a missing binary, are returned unchanged.
+func classifyExit(err error) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+
+	code := exitErr.ExitCode()
+	kind := ExitKindFailure
+	switch code {
+	case ExitFindings:
+		kind = ExitKindFindings
+	case ExitUsage:
+		kind = ExitKindUsage
+	}
+	return &ExitError{Code: code, Kind: kind, Err: err}
+}
@@ -0,0 +1,188 @@
+package wpprobe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/example/wphunter/internal/detector"
+)
+
+// MaxTopVulnerabilities caps how many vulnerabilities a Digest keeps in Top.
+const MaxTopVulnerabilities = 10
+
+// Report is one target's entry in wpprobe's JSON output.
+type Report struct {
+	URL     string                   `json:"url"`
+	Plugins map[string][]PluginMatch `json:"plugins"`
+}
+
+// PluginMatch is a detected plugin version with its known vulnerabilities grouped by severity.
+type PluginMatch struct {
+	Version    string                     `json:"version"`
+	Severities map[string][]SeverityGroup `json:"severities"`
+}
+
+// SeverityGroup lists vulnerabilities sharing an authentication requirement.
+type SeverityGroup struct {
+	AuthType        string          `json:"auth_type"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+// Vulnerability is a single wpprobe vulnerability match.
+type Vulnerability struct {
+	CVE       string  `json:"cve"`
+	Title     string  `json:"title"`
+	CVSSScore float64 `json:"cvss_score,omitempty"`
+}
+
+// TopVulnerability is a Digest entry locating a vulnerability on a target.
+type TopVulnerability struct {
+	Target   string  `json:"target" yaml:"target"`
+	Plugin   string  `json:"plugin" yaml:"plugin"`
+	Version  string  `json:"version,omitempty" yaml:"version,omitempty"`
+	Severity string  `json:"severity" yaml:"severity"`
+	CVE      string  `json:"cve,omitempty" yaml:"cve,omitempty"`
+	Title    string  `json:"title" yaml:"title"`
+	CVSS     float64 `json:"cvss,omitempty" yaml:"cvss,omitempty"`
+}
+
+// Digest condenses wpprobe output for embedding in a scan summary.
+type Digest struct {
+	Targets         int                `json:"targets" yaml:"targets"`
+	Plugins         int                `json:"plugins" yaml:"plugins"`
+	Vulnerabilities int                `json:"vulnerabilities" yaml:"vulnerabilities"`
+	Severities      map[string]int     `json:"severities" yaml:"severities"`
+	Top             []TopVulnerability `json:"top" yaml:"top"`
+}
+
+// ParseReports decodes wpprobe JSON output. A single report object, an array of
+// reports, and newline-delimited reports are accepted. Empty input yields no reports.
+func ParseReports(data []byte) ([]Report, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		var reports []Report
+		if err := json.Unmarshal(data, &reports); err != nil {
+			return nil, fmt.Errorf("parse wpprobe output: %w", err)
+		}
+		return reports, nil
+	}
+
+	var reports []Report
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	var pending []byte
+	for scanner.Scan() {
+		pending = append(pending, scanner.Bytes()...)
+		pending = append(pending, '\n')
+		var report Report
+		if err := json.Unmarshal(pending, &report); err != nil {
+			// A pretty-printed object spans lines; keep reading until it decodes.
+			continue
+		}
+		reports = append(reports, report)
+		pending = pending[:0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(pending)) > 0 {
+		return nil, errors.New("parse wpprobe output: trailing data is not a JSON report")
+	}
+	return reports, nil
+}
+
+// DigestFile summarizes the wpprobe JSON output at path. A missing or empty file
+// yields an empty digest, since wpprobe writes nothing when it finds nothing.
+func DigestFile(path string) (Digest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewDigest(nil), nil
+	}
+	if err != nil {
+		return Digest{}, err
+	}
+
+	reports, err := ParseReports(data)
+	if err != nil {
+		return Digest{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return NewDigest(reports), nil
+}
+
+// NewDigest counts targets, plugins, and vulnerabilities per severity and keeps the
+// most severe vulnerabilities in Top.
+func NewDigest(reports []Report) Digest {
+	d := Digest{Severities: map[string]int{}, Top: []TopVulnerability{}}
+	for _, report := range reports {
+		d.Targets++
+		for plugin, matches := range report.Plugins {
+			d.Plugins++
+			for _, match := range matches {
+				for severity, groups := range match.Severities {
+					for _, group := range groups {
+						for _, vuln := range group.Vulnerabilities {
+							d.Vulnerabilities++
+							d.Severities[strings.ToLower(severity)]++
+							d.Top = append(d.Top, TopVulnerability{
+								Target:   report.URL,
+								Plugin:   plugin,
+								Version:  match.Version,
+								Severity: strings.ToLower(severity),
+								CVE:      vuln.CVE,
+								Title:    vuln.Title,
+								CVSS:     vuln.CVSSScore,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+	d.trimTop()
+	return d
+}
+
+// Merge folds other into d, keeping the combined most severe vulnerabilities.
+func (d *Digest) Merge(other Digest) {
+	if d.Severities == nil {
+		d.Severities = map[string]int{}
+	}
+	if d.Top == nil {
+		d.Top = []TopVulnerability{}
+	}
+	d.Targets += other.Targets
+	d.Plugins += other.Plugins
+	d.Vulnerabilities += other.Vulnerabilities
+	for severity, count := range other.Severities {
+		d.Severities[severity] += count
+	}
+	d.Top = append(d.Top, other.Top...)
+	d.trimTop()
+}
+
+func (d *Digest) trimTop() {
+	sort.SliceStable(d.Top, func(i, j int) bool {
+		a, b := d.Top[i], d.Top[j]
+		if rankA, rankB := detector.SeverityRank(a.Severity), detector.SeverityRank(b.Severity); rankA != rankB {
+			return rankA > rankB
+		}
+		if a.CVSS != b.CVSS {
+			return a.CVSS > b.CVSS
+		}
+		return a.CVE < b.CVE
+	})
+	if len(d.Top) > MaxTopVulnerabilities {
+		d.Top = d.Top[:MaxTopVulnerabilities]
+	}
+}
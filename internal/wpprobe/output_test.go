@@ -0,0 +1,70 @@
+package wpprobe
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleOutput = `{"url":"https://one.test","plugins":{"give":[{"version":"2.20.1","severities":{"critical":[{"auth_type":"Unauth","vulnerabilities":[{"cve":"CVE-2024-5932","title":"GiveWP PHP Object Injection","cvss_score":10}]}],"medium":[{"auth_type":"Auth","vulnerabilities":[{"cve":"CVE-2023-0001","title":"Stored XSS","cvss_score":5.4},{"cve":"CVE-2023-0002","title":"CSRF","cvss_score":4.3}]}]}}],"akismet":[{"version":"5.0","severities":{}}]}}
+{"url":"https://two.test","plugins":{}}
+`
+
+func TestDigestFileCountsFindings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.json")
+	if err := os.WriteFile(path, []byte(sampleOutput), 0o600); err != nil {
+		t.Fatalf("write sample: %v", err)
+	}
+
+	digest, err := DigestFile(path)
+	if err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+
+	if digest.Targets != 2 || digest.Plugins != 2 || digest.Vulnerabilities != 3 {
+		t.Fatalf("unexpected counts: %+v", digest)
+	}
+	if digest.Severities["critical"] != 1 || digest.Severities["medium"] != 2 {
+		t.Fatalf("unexpected severity counts: %v", digest.Severities)
+	}
+	if len(digest.Top) != 3 || digest.Top[0].CVE != "CVE-2024-5932" || digest.Top[1].CVE != "CVE-2023-0001" {
+		t.Fatalf("unexpected top vulnerabilities: %+v", digest.Top)
+	}
+}
+
+func TestDigestMergeRanksSeverityCaseInsensitively(t *testing.T) {
+	d := Digest{Top: []TopVulnerability{{CVE: "CVE-2023-0001", Severity: "low", CVSS: 9}}}
+	d.Merge(Digest{Top: []TopVulnerability{{CVE: "CVE-2023-0002", Severity: "High", CVSS: 7}}})
+
+	if len(d.Top) != 2 || d.Top[0].CVE != "CVE-2023-0002" {
+		t.Fatalf("expected the High vulnerability first, got %+v", d.Top)
+	}
+}
+
+func TestDigestFileMissingOrEmpty(t *testing.T) {
+	dir := t.TempDir()
+
+	digest, err := DigestFile(filepath.Join(dir, "missing.json"))
+	if err != nil || digest.Targets != 0 {
+		t.Fatalf("expected empty digest for missing file, got %+v, %v", digest, err)
+	}
+
+	empty := filepath.Join(dir, "empty.json")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatalf("write empty: %v", err)
+	}
+	if digest, err := DigestFile(empty); err != nil || digest.Vulnerabilities != 0 {
+		t.Fatalf("expected empty digest for empty file, got %+v, %v", digest, err)
+	}
+}
+
+func TestParseReportsArray(t *testing.T) {
+	reports, err := ParseReports([]byte(`[{"url":"https://one.test","plugins":{}},{"url":"https://two.test"}]`))
+	if err != nil || len(reports) != 2 {
+		t.Fatalf("expected two reports, got %v, %v", reports, err)
+	}
+
+	if _, err := ParseReports([]byte(`{"url":`)); err == nil {
+		t.Fatal("expected truncated output to fail")
+	}
+}
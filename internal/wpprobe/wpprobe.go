@@ -38,6 +38,9 @@ type ScanInput struct {
 	OutputPath  string
 	Stdout      io.Writer
 	Stderr      io.Writer
+	// OnExec, when set, receives the full argv (binary followed by arguments)
+	// right before the command is started.
+	OnExec func(argv []string)
 }
 
 // NewRunner returns a default command runner.
@@ -61,7 +64,8 @@ func (r *CommandRunner) EnsureBinary() error {
 	return nil
 }
 
-// Scan executes wpprobe scan with the provided arguments.
+// Scan executes wpprobe scan with the provided arguments. A non-zero exit is
+// returned as an *ExitError classifying the exit code.
 func (r *CommandRunner) Scan(ctx context.Context, input ScanInput) error {
 	args := []string{
 		"scan",
@@ -81,7 +85,11 @@ func (r *CommandRunner) Scan(ctx context.Context, input ScanInput) error {
 	cmd.Stdout = input.Stdout
 	cmd.Stderr = input.Stderr
 
-	return cmd.Run()
+	if input.OnExec != nil {
+		input.OnExec(append([]string{r.Binary}, args...))
+	}
+
+	return classifyExit(cmd.Run())
 }
 
 // Update runs `wpprobe update` to refresh vulnerability databases.
@@ -3,6 +3,7 @@ package wpprobe
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os/exec"
 	"reflect"
 	"testing"
@@ -316,6 +317,29 @@ func TestScanSetsStdoutStderr(t *testing.T) {
 	_ = runner.Scan(context.Background(), input)
 }
 
+// TestScanReportsArgvBeforeRun verifies that OnExec receives the binary and constructed args.
+func TestScanReportsArgvBeforeRun(t *testing.T) {
+	mock := &mockCommandContext{}
+	runner := &CommandRunner{
+		Binary:         "wpprobe-missing-binary",
+		commandContext: mock.CommandContext,
+	}
+
+	var argv []string
+	_ = runner.Scan(context.Background(), ScanInput{
+		TargetsFile: "/tmp/targets.txt",
+		Mode:        "hybrid",
+		Threads:     4,
+		OutputPath:  "/tmp/output.json",
+		OnExec:      func(got []string) { argv = got },
+	})
+
+	expected := []string{"wpprobe-missing-binary", "scan", "-f", "/tmp/targets.txt", "--mode", "hybrid", "-o", "/tmp/output.json", "-t", "4"}
+	if !reflect.DeepEqual(argv, expected) {
+		t.Fatalf("expected argv %v, got %v", expected, argv)
+	}
+}
+
 // TestUpdateRunsCommand verifies that Update executes the update command with correct arguments.
 func TestUpdateRunsCommand(t *testing.T) {
 	mockCmdCtx := &mockCommandContext{}
@@ -489,3 +513,48 @@ func TestFakeRunnerUpdate(t *testing.T) {
 		})
 	}
 }
+
+// TestCommandRunner_ScanClassifiesExitCodes verifies known wpprobe exit codes map onto typed errors.
+func TestCommandRunner_ScanClassifiesExitCodes(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	tests := []struct {
+		code     int
+		wantKind string
+		wantIs   error
+	}{
+		{code: 1, wantKind: ExitKindFindings, wantIs: ErrFindings},
+		{code: 2, wantKind: ExitKindUsage, wantIs: ErrUsage},
+		{code: 7, wantKind: ExitKindFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantKind, func(t *testing.T) {
+			runner := &CommandRunner{
+				Binary: "wpprobe",
+				commandContext: func(ctx context.Context, name string, arg ...string) *exec.Cmd {
+					return exec.CommandContext(ctx, "sh", "-c", fmt.Sprintf("exit %d", tt.code))
+				},
+			}
+
+			err := runner.Scan(context.Background(), ScanInput{TargetsFile: "targets.txt", Mode: "hybrid", Threads: 1, OutputPath: "out.json"})
+
+			var exitErr *ExitError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("expected *ExitError, got %T: %v", err, err)
+			}
+			if exitErr.Code != tt.code || exitErr.Kind != tt.wantKind {
+				t.Fatalf("got code %d kind %q, want %d %q", exitErr.Code, exitErr.Kind, tt.code, tt.wantKind)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Fatalf("expected errors.Is(%v, %v)", err, tt.wantIs)
+			}
+			var execErr *exec.ExitError
+			if !errors.As(err, &execErr) {
+				t.Fatal("expected the underlying *exec.ExitError to stay reachable")
+			}
+		})
+	}
+}